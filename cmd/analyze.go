@@ -0,0 +1,50 @@
+package cmd
+
+import (
+	"log"
+	"os"
+
+	"github.com/jattento/alien-invasion-simulator/cmd/client"
+	"github.com/jattento/alien-invasion-simulator/internal/simulation"
+	"github.com/spf13/cobra"
+)
+
+var (
+	_analyzeJSON         *bool
+	_analyzeSkipDiameter *bool
+
+	analyzeCmd = &cobra.Command{
+		Use:   "analyze [city-config]",
+		Short: "Report which cities and roads hold the city layout together, before any alien lands",
+		Long: "Analyze the city layout loaded from the given file or --city-config, or generated from --matrix, --cities " +
+			"and --seed. The report lists the critical cities, the ones that would cut the map apart if they were destroyed, " +
+			"the critical roads, how many roads leave each city, the diameter and the amount of cycles. " +
+			"Measuring the diameter takes quadratic time, --skip-diameter leaves it out on huge maps.",
+		Args: cobra.MaximumNArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			cityConfig := *_cityConfig
+			if len(args) > 0 {
+				cityConfig = args[0]
+			}
+
+			directions := layoutDirections()
+
+			sim, err := simulation.NewInvasion(cityConfig, 0, systemManager(cityConfig, *_format, directions), *_days, *_cities, *_matrix, resolveSeed(cmd),
+				simulation.WithDirections(directions))
+			if err != nil {
+				log.Fatal("failed loading city layout: ", err.Error())
+			}
+
+			if err := client.PrintLayoutAnalysis(os.Stdout, sim.AnalyzeLayout(!*_analyzeSkipDiameter), *_analyzeJSON); err != nil {
+				log.Fatal("failed printing analysis: ", err.Error())
+			}
+		},
+	}
+)
+
+func init() {
+	_analyzeJSON = analyzeCmd.Flags().Bool("json", false, "Print the analysis as JSON instead of a table.")
+	_analyzeSkipDiameter = analyzeCmd.Flags().Bool("skip-diameter", false, "Don't measure the diameter, which takes quadratic time.")
+
+	rootCmd.AddCommand(analyzeCmd)
+}
@@ -0,0 +1,60 @@
+package cmd
+
+import (
+	"log"
+	"math/rand"
+	"os"
+	"runtime"
+
+	"github.com/jattento/alien-invasion-simulator/cmd/client"
+	"github.com/jattento/alien-invasion-simulator/internal/simulation"
+	"github.com/spf13/cobra"
+)
+
+var (
+	_runs    *int
+	_workers *int
+	_json    *bool
+
+	batchCmd = &cobra.Command{
+		Use:   "batch",
+		Short: "Run many invasions over the same map and report aggregated statistics",
+		Long: "Run many independent invasions in parallel over the same city layout and report " +
+			"the survival probability of each city, the day of the last battle and the aliens killed and trapped.",
+		Run: func(cmd *cobra.Command, args []string) {
+			seed := resolveSeed(cmd)
+			directions := layoutDirections()
+
+			cityLayout, cityAttributes, err := simulation.LoadCityLayoutWithAttributes(*_cityConfig, systemManager(*_cityConfig, *_format, directions),
+				*_cities, *_matrix, rand.New(rand.NewSource(seed)), directions)
+			if err != nil {
+				log.Fatal("failed loading city layout: ", err.Error())
+			}
+
+			report, err := simulation.RunBatch(cityLayout, simulation.BatchConfig{
+				Runs:         *_runs,
+				Workers:      *_workers,
+				AliensAmount: resolveAliens(cmd),
+				TickLimit:    *_days,
+				Seed:         seed,
+				Options: append(append([]simulation.Option{simulation.WithCityAttributes(cityAttributes)}, invasionOptions()...),
+					simulation.WithDirections(directions)),
+			})
+			if err != nil {
+				log.Fatal("failed running batch: ", err.Error())
+			}
+
+			if err := client.PrintBatchReport(os.Stdout, report, *_json); err != nil {
+				log.Fatal("failed printing batch report: ", err.Error())
+			}
+		},
+	}
+)
+
+func init() {
+	_runs = batchCmd.Flags().IntP("runs", "n", 1000, "Amount of invasions to run.")
+	_workers = batchCmd.Flags().IntP("workers", "w", runtime.NumCPU(), "Amount of invasions run in parallel.")
+	_json = batchCmd.Flags().Bool("json", false, "Print the report as JSON instead of a table.")
+
+	rootCmd.AddCommand(batchCmd)
+}
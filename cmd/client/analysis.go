@@ -0,0 +1,50 @@
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	"github.com/jattento/alien-invasion-simulator/internal/simulation"
+)
+
+// PrintLayoutAnalysis writes the analysis of a city layout to output as a table, or as indented JSON if asJSON is set.
+func PrintLayoutAnalysis(output io.Writer, analysis simulation.LayoutAnalysis, asJSON bool) error {
+	if asJSON {
+		encoder := json.NewEncoder(output)
+		encoder.SetIndent("", "  ")
+
+		return encoder.Encode(analysis)
+	}
+
+	w := tabwriter.NewWriter(output, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintf(w, "Cities:\t%d\n", analysis.Cities)
+	fmt.Fprintf(w, "Roads:\t%d\n", analysis.Roads)
+	fmt.Fprintf(w, "Components:\t%d\n", analysis.Components)
+	fmt.Fprintf(w, "Cycles:\t%d\n", analysis.Cycles)
+	if analysis.Diameter != nil {
+		fmt.Fprintf(w, "Diameter:\t%d\n", *analysis.Diameter)
+	}
+	fmt.Fprintln(w)
+
+	fmt.Fprintln(w, "ROADS\tCITIES")
+	for _, degree := range analysis.Degrees {
+		fmt.Fprintf(w, "%d\t%d\n", degree.Roads, degree.Cities)
+	}
+	fmt.Fprintln(w)
+
+	fmt.Fprintf(w, "CRITICAL CITIES (%d)\n", len(analysis.CriticalCities))
+	for _, city := range analysis.CriticalCities {
+		fmt.Fprintln(w, city)
+	}
+	fmt.Fprintln(w)
+
+	fmt.Fprintf(w, "CRITICAL ROADS (%d)\tDIRECTION\tTO\n", len(analysis.CriticalRoads))
+	for _, road := range analysis.CriticalRoads {
+		fmt.Fprintf(w, "%s\t%s\t%s\n", road.City, road.Direction, road.To)
+	}
+
+	return w.Flush()
+}
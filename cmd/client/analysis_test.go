@@ -0,0 +1,44 @@
+package client
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/jattento/alien-invasion-simulator/internal/simulation"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrintLayoutAnalysis(t *testing.T) {
+	diameter := 7
+	analysis := simulation.LayoutAnalysis{
+		Cities:         5,
+		Roads:          5,
+		Components:     1,
+		Cycles:         1,
+		Diameter:       &diameter,
+		CriticalCities: []string{"Paris"},
+		CriticalRoads:  []simulation.Road{{City: "Paris", Direction: "south", To: "Lyon"}},
+		Degrees:        []simulation.DegreeCount{{Roads: 1, Cities: 1}, {Roads: 2, Cities: 4}},
+	}
+
+	var table bytes.Buffer
+	require.NoError(t, PrintLayoutAnalysis(&table, analysis, false))
+	assert.Contains(t, table.String(), "Diameter:    7")
+	assert.Contains(t, table.String(), "CRITICAL CITIES (1)\nParis")
+	assert.Contains(t, table.String(), "Lyon")
+
+	var output bytes.Buffer
+	require.NoError(t, PrintLayoutAnalysis(&output, analysis, true))
+
+	var decoded simulation.LayoutAnalysis
+	require.NoError(t, json.Unmarshal(output.Bytes(), &decoded))
+	assert.Equal(t, analysis, decoded)
+
+	// Test case: the diameter wasn't measured
+	analysis.Diameter = nil
+	table.Reset()
+	require.NoError(t, PrintLayoutAnalysis(&table, analysis, false))
+	assert.NotContains(t, table.String(), "Diameter")
+}
@@ -0,0 +1,55 @@
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	"github.com/jattento/alien-invasion-simulator/internal/simulation"
+)
+
+// PrintBatchReport writes the batch statistics to output as a table, or as indented JSON if asJSON is set.
+func PrintBatchReport(output io.Writer, report simulation.BatchReport, asJSON bool) error {
+	if asJSON {
+		encoder := json.NewEncoder(output)
+		encoder.SetIndent("", "  ")
+
+		return encoder.Encode(report)
+	}
+
+	w := tabwriter.NewWriter(output, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintf(w, "Runs:\t%d\n", report.Runs)
+	fmt.Fprintf(w, "Seed:\t%d\n", report.Seed)
+	fmt.Fprintf(w, "Runs without battles:\t%d\n", report.RunsWithoutBattles)
+	fmt.Fprintln(w)
+
+	fmt.Fprintln(w, "\tMEAN\tMIN\tP50\tP90\tP99\tMAX")
+	for _, row := range []struct {
+		name         string
+		distribution simulation.Distribution
+	}{
+		{"Day of the last battle", report.LastBattleDay},
+		{"Aliens killed", report.AliensKilled},
+		{"Aliens trapped", report.AliensTrapped},
+		{"Humans killed", report.HumanCasualties},
+	} {
+		d := row.distribution
+		fmt.Fprintf(w, "%s\t%.2f\t%d\t%d\t%d\t%d\t%d\n", row.name, d.Mean, d.Min, d.P50, d.P90, d.P99, d.Max)
+	}
+	fmt.Fprintln(w)
+
+	fmt.Fprintln(w, "MOST DESTROYED\tDESTROYED")
+	for _, cityStats := range report.MostDestroyed {
+		fmt.Fprintf(w, "%s\t%d\n", cityStats.Name, cityStats.Destroyed)
+	}
+	fmt.Fprintln(w)
+
+	fmt.Fprintln(w, "CITY\tSURVIVAL\tDESTROYED")
+	for _, cityStats := range report.Cities {
+		fmt.Fprintf(w, "%s\t%.1f%%\t%d\n", cityStats.Name, cityStats.Survival*100, cityStats.Destroyed)
+	}
+
+	return w.Flush()
+}
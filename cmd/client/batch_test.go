@@ -0,0 +1,34 @@
+package client
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/jattento/alien-invasion-simulator/internal/simulation"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrintBatchReport(t *testing.T) {
+	report := simulation.BatchReport{
+		Runs:          10,
+		Seed:          42,
+		Cities:        []simulation.CityStats{{Name: "Paris", Destroyed: 3, Survival: 0.7}},
+		MostDestroyed: []simulation.CityStats{{Name: "Paris", Destroyed: 3, Survival: 0.7}},
+		LastBattleDay: simulation.Distribution{Mean: 4.5, Min: 1, P50: 4, P90: 8, P99: 9, Max: 9},
+	}
+
+	var table bytes.Buffer
+	require.NoError(t, PrintBatchReport(&table, report, false))
+	assert.Contains(t, table.String(), "Paris")
+	assert.Contains(t, table.String(), "70.0%")
+	assert.Contains(t, table.String(), "4.50")
+
+	var output bytes.Buffer
+	require.NoError(t, PrintBatchReport(&output, report, true))
+
+	var decoded simulation.BatchReport
+	require.NoError(t, json.Unmarshal(output.Bytes(), &decoded))
+	assert.Equal(t, report, decoded)
+}
@@ -2,8 +2,11 @@ package client
 
 import (
 	"fmt"
+	"io"
 	"math/rand"
 	"os"
+	"sort"
+	"strings"
 	"sync/atomic"
 	"time"
 
@@ -15,57 +18,238 @@ import (
 type Simulation interface {
 	Tick() (bool, simulation.TickReport)
 	Cities() map[string]map[earth.Direction]string
+	Directions() *earth.Directions
+	Seed() int64
 }
 
-// Run blocks until the program is ended or an error happen
-func Run(invSimulation Simulation, aliens int) error {
+// Summary describes the state of the world when a simulation ends.
+type Summary struct {
+	Days      int
+	Alive     int
+	Dead      int
+	Standing  int
+	Destroyed int
+
+	// HumanCasualties is the population of every destroyed city
+	HumanCasualties int
+
+	// StopReason is why a stop condition ended the simulation, empty if none did
+	StopReason string
+}
+
+// Exit codes returned by Summary.ExitCode, 1 is left for errors.
+const (
+	ExitCodeWorldSaved     = 0
+	ExitCodeTickLimit      = 2
+	ExitCodeWorldDestroyed = 3
+	ExitCodeStopCondition  = 4
+)
+
+// ExitCode maps the outcome of the simulation to a process exit code.
+func (summary Summary) ExitCode() int {
+	switch {
+	case summary.Standing == 0:
+		return ExitCodeWorldDestroyed
+	case summary.StopReason != "":
+		return ExitCodeStopCondition
+	case summary.Alive > 0:
+		return ExitCodeTickLimit
+	default:
+		return ExitCodeWorldSaved
+	}
+}
+
+// Run blocks until the program is ended or an error happen.
+// The simulation is stopped and waited for once the terminal is closed, so it can be read safely afterwards.
+func Run(invSimulation Simulation, aliens int, opts ...Option) error {
 	logsCh := make(chan string)
 	DaysCh := make(chan string)
 	citiesCh := make(chan []string)
+	stopCh := make(chan struct{})
+	playErrCh := make(chan error)
 
-	worldMatrix := worldMap{cities: make([]city, 0), citiesIndex: make(map[string]int), alive: aliens}
+	t := terminal.New(os.Stdout, logsCh, DaysCh, citiesCh)
 
-	remainingCities := invSimulation.Cities()
+	go func() {
+		_, err := play(invSimulation, aliens, logsCh, DaysCh, citiesCh, stopCh, func(tickStart time.Time) {
+			time.Sleep(time.Duration(atomic.LoadInt64(&t.WaitTime)) - (time.Now().Sub(tickStart)))
+		}, newOptions(opts))
+		playErrCh <- err
+	}()
+
+	runErr := t.Run()
+	close(stopCh)
 
-	for cityInfo := range remainingCities {
-		worldMatrix.save(city{name: cityInfo})
+	// The terminal might not read the channels anymore, they are drained until the simulation notices it was stopped
+	for {
+		select {
+		case <-logsCh:
+		case <-DaysCh:
+		case <-citiesCh:
+		case playErr := <-playErrCh:
+			if runErr != nil {
+				return runErr
+			}
+
+			return playErr
+		}
 	}
+}
 
-	t := terminal.New(os.Stdout, logsCh, DaysCh, citiesCh)
+// RunHeadless runs the simulation as fast as possible without the terminal UI,
+// writing the battle logs and the remaining cities layout to output.
+func RunHeadless(invSimulation Simulation, aliens int, output io.Writer, opts ...Option) (Summary, error) {
+	logsCh := make(chan string)
+	DaysCh := make(chan string)
+	citiesCh := make(chan []string)
+	summaryCh := make(chan Summary)
 
+	var playErr error
 	go func() {
-		for keepTicking := true; keepTicking && worldMatrix.alive > 0; {
-			now := time.Now()
+		var summary Summary
+		summary, playErr = play(invSimulation, aliens, logsCh, DaysCh, citiesCh, nil, func(time.Time) {}, newOptions(opts))
+		summaryCh <- summary
+	}()
+
+	// The channels must be drained until the end even if writing fails, otherwise the simulation blocks
+	var writeErr error
+	for {
+		select {
+		case log := <-logsCh:
+			if writeErr == nil {
+				_, writeErr = fmt.Fprintln(output, log)
+			}
+		case <-DaysCh:
+		case <-citiesCh:
+		case summary := <-summaryCh:
+			if playErr != nil {
+				return summary, playErr
+			}
+
+			return summary, writeErr
+		}
+	}
+}
 
-			var report simulation.TickReport
-			keepTicking, report = invSimulation.Tick()
+// play ticks the simulation until it ends or every alien is dead, sending its progress through the channels.
+// wait is called at the end of each tick with the moment in which the tick started.
+// If a tick hook fails the simulation is stopped, the error is logged and returned.
+// No more ticks are played once stopCh is closed, a nil stopCh plays until the end.
+func play(invSimulation Simulation, aliens int, logsCh chan<- string, DaysCh chan<- string, citiesCh chan<- []string,
+	stopCh <-chan struct{}, wait func(tickStart time.Time), opts options) (Summary, error) {
+	worldMatrix := worldMap{cities: make([]city, 0), citiesIndex: make(map[string]int), alive: aliens, incoming: opts.incomingAliens}
 
-			worldMatrix.clear()
+	remainingCities := invSimulation.Cities()
 
-			for _, battleReport := range report.Battles {
-				worldMatrix.save(city{name: battleReport.City, destroyed: true, aliens: battleReport.InvolvedAliens})
-				logsCh <- killLog(battleReport)
-				deleteCity(remainingCities, battleReport.City)
-			}
-			for cityName := range report.AlienPositions {
-				worldMatrix.save(city{name: cityName, aliens: report.AlienPositions[cityName]})
+	for _, cityName := range sortedCityNames(remainingCities) {
+		worldMatrix.save(city{name: cityName, critical: opts.criticalCities[cityName]})
+	}
+
+	// Weapons are picked from their own randomizer, so the logs don't alter the simulation outcome
+	randomizer := rand.New(rand.NewSource(invSimulation.Seed()))
+
+	days := 0
+	stopReason := ""
+	var hookErr error
+	for keepTicking := true; keepTicking && worldMatrix.alive+worldMatrix.incoming > 0 && hookErr == nil && !stopped(stopCh); {
+		now := time.Now()
+
+		var report simulation.TickReport
+		keepTicking, report = invSimulation.Tick()
+		days = report.Tick + 1
+		stopReason = report.StopReason
+
+		for _, hook := range opts.tickHooks {
+			if hookErr = hook(report); hookErr != nil {
+				break
 			}
+		}
 
-			citiesCh <- worldMatrix.prettySlice()
-			DaysCh <- fmt.Sprintf("🕒  :  %v   |   👽  :  %v   |   💀  :  %v   |   🏡  :  %v   |   🔥  :  %v",
-				report.Tick, worldMatrix.alive, worldMatrix.dead, worldMatrix.notDestroyed, worldMatrix.destroyed)
+		worldMatrix.clear()
 
-			time.Sleep(time.Duration(atomic.LoadInt64(&t.WaitTime)) - (time.Now().Sub(now)))
+		for _, roadBattle := range report.RoadBattles {
+			worldMatrix.bury(len(roadBattle.Casualties))
+			logsCh <- roadKillLog(roadBattle)
 		}
 
-		finalLogs(logsCh, remainingCities)
-	}()
+		worldMatrix.incoming = report.AliensIncoming
+		for _, wave := range report.Waves {
+			worldMatrix.land(len(wave.Aliens))
+			logsCh <- waveLog(wave)
+		}
+
+		for _, defense := range report.Defenses {
+			worldMatrix.defend(defense.City, defense.Casualties, defense.Losses, defense.Repelled)
+			logsCh <- defenseLog(defense)
+		}
+
+		for _, battleReport := range report.Battles {
+			worldMatrix.battle(battleReport)
+			logsCh <- killLog(battleReport, randomizer)
+
+			if battleReport.CityDestroyed {
+				deleteCity(remainingCities, battleReport.City)
+			}
+		}
+		for cityName := range report.AlienPositions {
+			worldMatrix.save(city{name: cityName, aliens: report.AlienPositions[cityName]})
+		}
+		worldMatrix.guard(report.Garrisons)
+
+		citiesCh <- worldMatrix.prettySlice()
+		DaysCh <- worldMatrix.status(report.Tick)
+
+		wait(now)
+	}
+
+	if hookErr != nil {
+		logsCh <- "ERROR: simulation stopped: " + hookErr.Error()
+	}
+
+	if stopReason != "" {
+		logsCh <- stopLog(stopReason)
+	}
 
-	if err := t.Run(); err != nil {
-		return err
+	if worldMatrix.defenders {
+		logsCh <- defendersSummaryLog(&worldMatrix)
 	}
 
-	return nil
+	if worldMatrix.humanCasualties > 0 {
+		logsCh <- fmt.Sprintf("👥 %d humans died in the destroyed cities", worldMatrix.humanCasualties)
+	}
+
+	finalLogs(logsCh, remainingCities, invSimulation.Directions(), invSimulation.Seed())
+
+	return Summary{
+		Days:      days,
+		Alive:     worldMatrix.alive,
+		Dead:      worldMatrix.dead,
+		Standing:  worldMatrix.notDestroyed,
+		Destroyed: worldMatrix.destroyed,
+
+		HumanCasualties: worldMatrix.humanCasualties,
+		StopReason:      stopReason,
+	}, hookErr
+}
+
+// stopped reports whether stopCh was closed without blocking.
+func stopped(stopCh <-chan struct{}) bool {
+	select {
+	case <-stopCh:
+		return true
+	default:
+		return false
+	}
+}
+
+func sortedCityNames(cities map[string]map[earth.Direction]string) []string {
+	names := make([]string, 0, len(cities))
+	for cityName := range cities {
+		names = append(names, cityName)
+	}
+	sort.Strings(names)
+
+	return names
 }
 
 func deleteCity(cities map[string]map[earth.Direction]string, city string) {
@@ -79,19 +263,21 @@ func deleteCity(cities map[string]map[earth.Direction]string, city string) {
 	}
 }
 
-func finalLogs(logsCh chan<- string, remainingCities map[string]map[earth.Direction]string) {
-	var enumToDirection = map[earth.Direction]string{
-		earth.North: "north",
-		earth.South: "south",
-		earth.East:  "east",
-		earth.West:  "west",
-	}
-
+func finalLogs(logsCh chan<- string, remainingCities map[string]map[earth.Direction]string, directions *earth.Directions, seed int64) {
 	logsCh <- "--------"
-	for cityName, adjacentData := range remainingCities {
+	for _, cityName := range sortedCityNames(remainingCities) {
+		adjacentData := remainingCities[cityName]
+
+		// Roads are written in the order of their directions, the compass ones first
+		sorted := make([]earth.Direction, 0, len(adjacentData))
+		for direction := range adjacentData {
+			sorted = append(sorted, direction)
+		}
+		sort.Ints(sorted)
+
 		cityInfo := cityName
-		for direction, adjacentCityName := range adjacentData {
-			cityInfo += " " + enumToDirection[direction] + "=" + adjacentCityName
+		for _, direction := range sorted {
+			cityInfo += " " + directions.Name(direction) + "=" + adjacentData[direction]
 		}
 
 		logsCh <- cityInfo
@@ -101,9 +287,10 @@ func finalLogs(logsCh chan<- string, remainingCities map[string]map[earth.Direct
 	logsCh <- "--------"
 	logsCh <- "Just remember, if any actual aliens come to visit, don't blame me if this isn't accurate."
 	logsCh <- "Congratulations on completing the alien simulation!"
+	logsCh <- fmt.Sprintf("Seed: %d (use --seed=%d to replay this invasion)", seed, seed)
 }
 
-func killLog(report earth.BattleReport) string {
+func killLog(report earth.BattleReport, randomizer *rand.Rand) string {
 	skull := "💀"
 	knife := "🗡️"
 	gun := "🔫"
@@ -115,21 +302,123 @@ func killLog(report earth.BattleReport) string {
 	paperClip := "📎"
 
 	weapons := []string{skull, knife, gun, bomb, wrench, poison, syringe, fire, paperClip}
-	weapon := weapons[randomInt(0, len(weapons)-1)]
+	weapon := weapons[randomInt(randomizer, 0, len(weapons)-1)]
 
-	fmtText := fmt.Sprintf("👽 %q", report.InvolvedAliens[0])
-	for i := 1; i < len(report.InvolvedAliens); i++ {
-		if i == len(report.InvolvedAliens)-1 {
-			fmtText += fmt.Sprintf(" and 👽 %q", report.InvolvedAliens[i])
+	var winner string
+	losers := make([]string, 0, len(report.InvolvedAliens))
+
+	for i, alien := range report.InvolvedAliens {
+		if alien == report.Winner {
+			winner = alienTag(report.InvolvedAliens, report.Species, i)
 			continue
 		}
-		fmtText += fmt.Sprintf(", 👽 %q", report.InvolvedAliens[i])
+
+		losers = append(losers, alienTag(report.InvolvedAliens, report.Species, i))
+	}
+
+	var text string
+	if winner != "" {
+		text = fmt.Sprintf("%s won the %s  duel against %s in %q", winner, weapon, joinTags(losers), report.City)
+	} else {
+		text = fmt.Sprintf("%s killed each other in %q in a %s  duel %s", joinTags(losers), report.City, weapon, skull)
+	}
+
+	if !report.CityDestroyed {
+		text += " but the city is still standing 🏠"
+	}
+
+	return text
+}
+
+// joinTags returns the tags separated by commas, the last one joined by "and".
+func joinTags(tags []string) string {
+	if len(tags) < 2 {
+		return strings.Join(tags, "")
+	}
+
+	return strings.Join(tags[:len(tags)-1], ", ") + " and " + tags[len(tags)-1]
+}
+
+// roadKillLog describes a battle fought on a road.
+func roadKillLog(report earth.RoadBattleReport) string {
+	first, second := alienTag(report.InvolvedAliens, report.Species, 0), alienTag(report.InvolvedAliens, report.Species, 1)
+	road := fmt.Sprintf("on the road from %q to %q", report.From, report.To)
+
+	switch report.Winner {
+	case report.InvolvedAliens[0]:
+		return fmt.Sprintf("%s ran over %s %s 💥", first, second, road)
+	case report.InvolvedAliens[1]:
+		return fmt.Sprintf("%s ran over %s %s 💥", second, first, road)
+	default:
+		return fmt.Sprintf("%s and %s crashed into each other %s 💥", first, second, road)
+	}
+}
+
+// defenseLog describes the fight between the garrisons of a city and the aliens that reached it.
+func defenseLog(report earth.DefenseReport) string {
+	garrisons := make([]string, 0, len(report.Garrisons))
+	for _, garrison := range report.Garrisons {
+		garrisons = append(garrisons, fmt.Sprintf("🛡️ %q", garrison))
+	}
+
+	aliens := make([]string, 0, len(report.Aliens))
+	for i := range report.Aliens {
+		aliens = append(aliens, alienTag(report.Aliens, nil, i))
+	}
+
+	if report.Repelled {
+		return fmt.Sprintf("%s held %q against %s 🏰", joinTags(garrisons), report.City, joinTags(aliens))
+	}
+
+	text := fmt.Sprintf("%s fell defending %q against %s", joinTags(garrisons), report.City, joinTags(aliens))
+	if len(report.Casualties) > 0 {
+		casualties := make([]string, 0, len(report.Casualties))
+		for i := range report.Casualties {
+			casualties = append(casualties, alienTag(report.Casualties, nil, i))
+		}
+
+		text += ", taking down " + joinTags(casualties)
+	}
+
+	return text + " ⚔️"
+}
+
+// waveLog lists the aliens of a wave and where they landed.
+func waveLog(report earth.WaveReport) string {
+	landings := make([]string, 0, len(report.Aliens))
+	for i := range report.Aliens {
+		landings = append(landings, fmt.Sprintf("%s in %q", alienTag(report.Aliens, report.Species, i), report.Cities[i]))
+	}
+
+	return fmt.Sprintf("🛸 %d aliens landed: %s", len(report.Aliens), strings.Join(landings, ", "))
+}
+
+// stopLog describes why a stop condition ended the simulation.
+func stopLog(reason string) string {
+	return "🏁 The invasion stopped: " + reason
+}
+
+// defendersSummaryLog describes how the defenders did in the whole invasion.
+func defendersSummaryLog(world *worldMap) string {
+	saved := world.savedCities()
+	if len(saved) == 0 {
+		return fmt.Sprintf("🛡️ The defenders lost %d strength and didn't save any city", world.defenderLosses)
+	}
+
+	return fmt.Sprintf("🛡️ The defenders lost %d strength and saved %d cities: %s",
+		world.defenderLosses, len(saved), strings.Join(saved, ", "))
+}
+
+// alienTag returns the name of the i alien of a battle, with its species unless it is the default one.
+func alienTag(aliens []string, species []string, i int) string {
+	tag := fmt.Sprintf("👽 %q", aliens[i])
+	if i < len(species) && species[i] != earth.DefaultSpecies.Name {
+		tag += " (" + species[i] + ")"
 	}
 
-	return fmt.Sprintf("%s killed each other in %q in a %s  duel %s",
-		fmtText, report.City, weapon, skull)
+	return tag
 }
 
-func randomInt(min int, max int) int {
-	return min + rand.Intn(max-min+1)
+func randomInt(randomizer *rand.Rand, min int, max int) int {
+	return min + randomizer.Intn(max-min+1)
 }
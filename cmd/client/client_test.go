@@ -1,13 +1,16 @@
 package client
 
 import (
+	"bytes"
 	"math/rand"
 	"strings"
 	"testing"
 	"time"
 
 	"github.com/jattento/alien-invasion-simulator/internal/earth"
+	"github.com/jattento/alien-invasion-simulator/internal/simulation"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestKillLog(t *testing.T) {
@@ -15,20 +18,60 @@ func TestKillLog(t *testing.T) {
 		City:           "New York",
 		InvolvedAliens: []string{"Alien1", "Alien2"},
 	}
-	log := killLog(report)
+	log := killLog(report, rand.New(rand.NewSource(0)))
 
 	assert.Contains(t, log, "Alien1")
 	assert.Contains(t, log, "Alien2")
 	assert.Contains(t, log, "New York")
+
+	report.Species = []string{earth.DefaultSpecies.Name, "scout"}
+	log = killLog(report, rand.New(rand.NewSource(0)))
+
+	assert.Contains(t, log, `👽 "Alien1" and 👽 "Alien2" (scout) killed each other`)
+	assert.Contains(t, log, "still standing")
+
+	report.Winner = "Alien2"
+	report.CityDestroyed = true
+	log = killLog(report, rand.New(rand.NewSource(0)))
+
+	assert.Regexp(t, `^👽 "Alien2" \(scout\) won the .+ duel against 👽 "Alien1" in "New York"$`, log)
+}
+
+func TestRoadKillLog(t *testing.T) {
+	report := earth.RoadBattleReport{InvolvedAliens: []string{"Alien1", "Alien2"}, From: "Paris", To: "Berlin", Species: []string{"scout", "common"}}
+
+	assert.Equal(t, `👽 "Alien1" (scout) and 👽 "Alien2" crashed into each other on the road from "Paris" to "Berlin" 💥`, roadKillLog(report))
+
+	report.Winner = "Alien2"
+	assert.Equal(t, `👽 "Alien2" ran over 👽 "Alien1" (scout) on the road from "Paris" to "Berlin" 💥`, roadKillLog(report))
+}
+
+func TestDefenseLog(t *testing.T) {
+	report := earth.DefenseReport{City: "Paris", Garrisons: []string{"Paris guard"}, Aliens: []string{"Alien1"}, Casualties: []string{"Alien1"}, Repelled: true}
+	assert.Equal(t, `🛡️ "Paris guard" held "Paris" against 👽 "Alien1" 🏰`, defenseLog(report))
+
+	report = earth.DefenseReport{
+		City:       "Paris",
+		Garrisons:  []string{"g1", "g2"},
+		Aliens:     []string{"Alien1", "Alien2"},
+		Casualties: []string{"Alien2"},
+	}
+	assert.Equal(t, `🛡️ "g1" and 🛡️ "g2" fell defending "Paris" against 👽 "Alien1" and 👽 "Alien2", taking down 👽 "Alien2" ⚔️`, defenseLog(report))
+}
+
+func TestWaveLog(t *testing.T) {
+	report := earth.WaveReport{Aliens: []string{"Alien1", "Alien2"}, Cities: []string{"Paris", "Berlin"}, Species: []string{earth.DefaultSpecies.Name, "scout"}}
+
+	assert.Equal(t, `🛸 2 aliens landed: 👽 "Alien1" in "Paris", 👽 "Alien2" (scout) in "Berlin"`, waveLog(report))
 }
 
 func TestRandomInt(t *testing.T) {
-	rand.Seed(42)
+	randomizer := rand.New(rand.NewSource(42))
 
-	output := randomInt(1, 10)
+	output := randomInt(randomizer, 1, 10)
 	assert.Equal(t, 6, output, "randomInt() returned %d, expected 8", output)
 
-	output = randomInt(5, 5)
+	output = randomInt(randomizer, 5, 5)
 	assert.Equal(t, 5, output, "randomInt() returned %d, expected 5", output)
 }
 
@@ -51,7 +94,7 @@ func TestFinalLogs(t *testing.T) {
 	remainingCities["New York"] = map[earth.Direction]string{earth.North: "Toronto", earth.South: "Philadelphia", earth.East: "Boston"}
 	remainingCities["Toronto"] = map[earth.Direction]string{earth.South: "New York"}
 
-	go finalLogs(logsCh, remainingCities)
+	go finalLogs(logsCh, remainingCities, earth.NewDirections(), 42)
 
 	expectedOutput := "--------" +
 		"New York north=Toronto south=Philadelphia east=Boston" +
@@ -59,10 +102,11 @@ func TestFinalLogs(t *testing.T) {
 		"These are the remaining cities..." +
 		"--------" +
 		"Just remember, if any actual aliens come to visit, don't blame me if this isn't accurucate." +
-		"Congratulations on completing the alien simulation!"
+		"Congratulations on completing the alien simulation!" +
+		"Seed: 42 (use --seed=42 to replay this invasion)"
 
 	timeout := time.After(5 * time.Second) // Wait for 5 seconds before timing out
-	for i := 0; i < 7; i++ {
+	for i := 0; i < 8; i++ {
 		select {
 		case actualOutput := <-logsCh:
 			assert.Contains(t, expectedOutput, strings.Split(actualOutput, " ")[0])
@@ -72,3 +116,117 @@ func TestFinalLogs(t *testing.T) {
 		}
 	}
 }
+
+func TestFinalLogs_Directions(t *testing.T) {
+	directions, err := earth.ParseDirections("inside:outside")
+	require.NoError(t, err)
+
+	inside, _ := directions.Lookup("inside")
+
+	logsCh := make(chan string, 16)
+	finalLogs(logsCh, map[string]map[earth.Direction]string{
+		"A": {inside: "E", earth.Up: "D", earth.Northeast: "C", earth.East: "B"},
+	}, directions, 42)
+
+	<-logsCh
+	assert.Equal(t, "A east=B northeast=C up=D inside=E", <-logsCh)
+}
+
+type fakeSimulation struct {
+	reports []simulation.TickReport
+	cities  map[string]map[earth.Direction]string
+	ticks   int
+}
+
+func (sim *fakeSimulation) Tick() (bool, simulation.TickReport) {
+	report := sim.reports[sim.ticks]
+	sim.ticks++
+
+	return sim.ticks < len(sim.reports), report
+}
+
+func (sim *fakeSimulation) Cities() map[string]map[earth.Direction]string {
+	return sim.cities
+}
+
+func (sim *fakeSimulation) Directions() *earth.Directions {
+	return earth.NewDirections()
+}
+
+func (sim *fakeSimulation) Seed() int64 {
+	return 0
+}
+
+func TestRunHeadless(t *testing.T) {
+	sim := &fakeSimulation{
+		cities: map[string]map[earth.Direction]string{
+			"Paris":  {earth.East: "Berlin"},
+			"Berlin": {earth.West: "Paris"},
+		},
+		reports: []simulation.TickReport{
+			{Tick: 0, AlienPositions: map[string][]string{"Paris": {"Alien1"}, "Berlin": {"Alien2"}}},
+			{Tick: 1, Battles: []earth.BattleReport{{
+				City:           "Paris",
+				InvolvedAliens: []string{"Alien1", "Alien2"},
+				Casualties:     []string{"Alien1", "Alien2"},
+				CityDestroyed:  true,
+			}}},
+			{Tick: 2},
+		},
+	}
+
+	var output bytes.Buffer
+	summary, err := RunHeadless(sim, 2, &output)
+	assert.NoError(t, err)
+
+	assert.Equal(t, Summary{Days: 2, Alive: 0, Dead: 2, Standing: 1, Destroyed: 1}, summary)
+	assert.Equal(t, ExitCodeWorldSaved, summary.ExitCode())
+	assert.Contains(t, output.String(), `👽 "Alien1" and 👽 "Alien2" killed each other in "Paris"`)
+	assert.Contains(t, output.String(), "\nBerlin\n")
+}
+
+func TestRunHeadless_StopReason(t *testing.T) {
+	sim := &fakeSimulation{
+		cities: map[string]map[earth.Direction]string{"Paris": {}, "Berlin": {}},
+		reports: []simulation.TickReport{
+			{Tick: 0, AlienPositions: map[string][]string{"Paris": {"Alien1"}, "Berlin": {"Alien2"}}},
+			{Tick: 1, AlienPositions: map[string][]string{"Paris": {"Alien1"}, "Berlin": {"Alien2"}}, StopReason: "every alien is isolated"},
+		},
+	}
+
+	var output bytes.Buffer
+	summary, err := RunHeadless(sim, 2, &output)
+	assert.NoError(t, err)
+
+	assert.Equal(t, "every alien is isolated", summary.StopReason)
+	assert.Equal(t, ExitCodeStopCondition, summary.ExitCode())
+	assert.Contains(t, output.String(), "🏁 The invasion stopped: every alien is isolated\n")
+}
+
+func TestPlay_Stopped(t *testing.T) {
+	sim := &fakeSimulation{
+		cities:  map[string]map[earth.Direction]string{"Paris": {}},
+		reports: []simulation.TickReport{{Tick: 0}, {Tick: 1}},
+	}
+
+	logsCh := make(chan string)
+	go func() {
+		for range logsCh {
+		}
+	}()
+	defer close(logsCh)
+
+	stopCh := make(chan struct{})
+	close(stopCh)
+
+	_, err := play(sim, 1, logsCh, nil, nil, stopCh, func(time.Time) {}, newOptions(nil))
+	assert.NoError(t, err)
+	assert.Zero(t, sim.ticks)
+}
+
+func TestSummary_ExitCode(t *testing.T) {
+	assert.Equal(t, ExitCodeWorldSaved, Summary{Standing: 3}.ExitCode())
+	assert.Equal(t, ExitCodeTickLimit, Summary{Standing: 3, Alive: 2}.ExitCode())
+	assert.Equal(t, ExitCodeWorldDestroyed, Summary{Standing: 0, Alive: 1}.ExitCode())
+	assert.Equal(t, ExitCodeStopCondition, Summary{Standing: 3, Alive: 2, StopReason: "every alien is isolated"}.ExitCode())
+}
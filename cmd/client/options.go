@@ -0,0 +1,64 @@
+package client
+
+import "github.com/jattento/alien-invasion-simulator/internal/simulation"
+
+// Option configures how the client runs a simulation.
+type Option interface {
+	apply(*options)
+}
+
+type options struct {
+	tickHooks      []func(simulation.TickReport) error
+	incomingAliens int
+
+	// City:Whether it is marked as critical in the city list
+	criticalCities map[string]bool
+}
+
+type tickHookOption func(simulation.TickReport) error
+
+func (hook tickHookOption) apply(opts *options) {
+	opts.tickHooks = append(opts.tickHooks, hook)
+}
+
+// WithTickHook calls hook with the report of every tick, right after the tick is processed.
+// If the hook fails the simulation is stopped and the error is returned by the client.
+func WithTickHook(hook func(simulation.TickReport) error) Option {
+	return tickHookOption(hook)
+}
+
+type incomingAliensOption int
+
+func (incoming incomingAliensOption) apply(opts *options) {
+	opts.incomingAliens = int(incoming)
+}
+
+// WithIncomingAliens keeps the simulation running while there are aliens of waves yet to land,
+// even if every alien already landed is dead.
+func WithIncomingAliens(incoming int) Option {
+	return incomingAliensOption(incoming)
+}
+
+type criticalCitiesOption []string
+
+func (cities criticalCitiesOption) apply(opts *options) {
+	opts.criticalCities = make(map[string]bool, len(cities))
+	for _, city := range cities {
+		opts.criticalCities[city] = true
+	}
+}
+
+// WithCriticalCities marks the cities in the city list while they are standing,
+// meant for the ones that would cut the map apart if they were destroyed.
+func WithCriticalCities(cities []string) Option {
+	return criticalCitiesOption(cities)
+}
+
+func newOptions(opts []Option) options {
+	var o options
+	for _, opt := range opts {
+		opt.apply(&o)
+	}
+
+	return o
+}
@@ -0,0 +1,57 @@
+package client
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/jattento/alien-invasion-simulator/internal/earth"
+	"github.com/jattento/alien-invasion-simulator/internal/simulation"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithTickHook(t *testing.T) {
+	newSimulation := func() *fakeSimulation {
+		return &fakeSimulation{
+			cities: map[string]map[earth.Direction]string{"Paris": {}},
+			reports: []simulation.TickReport{
+				{Tick: 0, AlienPositions: map[string][]string{"Paris": {"Alien1"}}},
+				{Tick: 1, AlienPositions: map[string][]string{"Paris": {"Alien1"}}},
+				{Tick: 2, AlienPositions: map[string][]string{"Paris": {"Alien1"}}},
+			},
+		}
+	}
+
+	t.Run("every tick is hooked", func(t *testing.T) {
+		ticks := make([]int, 0)
+
+		_, err := RunHeadless(newSimulation(), 1, new(bytes.Buffer), WithTickHook(func(report simulation.TickReport) error {
+			ticks = append(ticks, report.Tick)
+			return nil
+		}))
+
+		assert.NoError(t, err)
+		assert.Equal(t, []int{0, 1, 2}, ticks)
+	})
+
+	t.Run("failing hook stops the simulation", func(t *testing.T) {
+		hookErr := errors.New("disk full")
+
+		var output bytes.Buffer
+		summary, err := RunHeadless(newSimulation(), 1, &output, WithTickHook(func(report simulation.TickReport) error {
+			if report.Tick == 1 {
+				return hookErr
+			}
+			return nil
+		}))
+
+		assert.ErrorIs(t, err, hookErr)
+		assert.Equal(t, 2, summary.Days)
+		assert.Contains(t, output.String(), "ERROR: simulation stopped: disk full")
+	})
+}
+
+func TestWithCriticalCities(t *testing.T) {
+	opts := newOptions([]Option{WithCriticalCities([]string{"Paris", "Rome"})})
+	assert.Equal(t, map[string]bool{"Paris": true, "Rome": true}, opts.criticalCities)
+}
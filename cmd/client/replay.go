@@ -0,0 +1,282 @@
+package client
+
+import (
+	"math/rand"
+	"os"
+	"sort"
+	"sync/atomic"
+	"time"
+
+	"github.com/jattento/alien-invasion-simulator/internal/earth"
+	"github.com/jattento/alien-invasion-simulator/internal/interface/terminal"
+	"github.com/jattento/alien-invasion-simulator/internal/simulation"
+)
+
+// _jumpSize is the amount of days skipped by the jump commands.
+const _jumpSize = 10
+
+// frame is everything shown in the terminal for a single day of a recording.
+type frame struct {
+	cities []string
+	status string
+
+	// Every log up to this day, the last one is the most recent
+	logs []string
+}
+
+// Replay plays back a recorded invasion in the terminal UI starting at startTick,
+// it blocks until the program is ended or an error happen. The playback is stopped and waited for once the terminal
+// is closed.
+func Replay(recording simulation.Recording, startTick int) error {
+	frames := buildFrames(recording)
+	if len(frames) == 0 {
+		return nil
+	}
+
+	DaysCh := make(chan string)
+	citiesCh := make(chan []string)
+	logsResetCh := make(chan []string)
+	commandsCh := make(chan terminal.Command, 16)
+	stopCh := make(chan struct{})
+	playbackDoneCh := make(chan struct{})
+
+	t := terminal.New(os.Stdout, nil, DaysCh, citiesCh)
+	t.LogsResetCh = logsResetCh
+	t.CommandsCh = commandsCh
+
+	current := clampFrame(startTick, len(frames))
+
+	// Starting in the middle of the invasion is usually done to look at a specific moment
+	paused := current > 0
+
+	// show sends the current frame to the terminal, it returns false if the playback was stopped meanwhile
+	show := func() bool {
+		f := frames[current]
+
+		status := f.status
+		if paused {
+			status += "   |   ⏸"
+		}
+
+		select {
+		case citiesCh <- f.cities:
+		case <-stopCh:
+			return false
+		}
+
+		select {
+		case logsResetCh <- f.logs:
+		case <-stopCh:
+			return false
+		}
+
+		select {
+		case DaysCh <- status:
+		case <-stopCh:
+			return false
+		}
+
+		return true
+	}
+
+	go func() {
+		defer close(playbackDoneCh)
+
+		for show() {
+			var nextTickCh <-chan time.Time
+			if !paused && current < len(frames)-1 {
+				nextTickCh = time.After(time.Duration(atomic.LoadInt64(&t.WaitTime)))
+			}
+
+			select {
+			case command := <-commandsCh:
+				current, paused = applyCommand(command, current, paused, len(frames))
+			case <-nextTickCh:
+				current++
+			case <-stopCh:
+				return
+			}
+		}
+	}()
+
+	runErr := t.Run()
+	close(stopCh)
+	<-playbackDoneCh
+
+	return runErr
+}
+
+// applyCommand returns the frame to show and whether the playback is paused after the command.
+// Stepping and jumping pause the playback, so the selected day can be looked at.
+func applyCommand(command terminal.Command, current int, paused bool, framesAmount int) (int, bool) {
+	switch command {
+	case terminal.CommandPause:
+		return current, !paused
+	case terminal.CommandStepForward:
+		return clampFrame(current+1, framesAmount), true
+	case terminal.CommandStepBackward:
+		return clampFrame(current-1, framesAmount), true
+	case terminal.CommandJumpForward:
+		return clampFrame(current+_jumpSize, framesAmount), true
+	case terminal.CommandJumpBackward:
+		return clampFrame(current-_jumpSize, framesAmount), true
+	case terminal.CommandJumpStart:
+		return 0, true
+	case terminal.CommandJumpEnd:
+		return framesAmount - 1, true
+	}
+
+	return current, paused
+}
+
+func clampFrame(index, framesAmount int) int {
+	if index < 0 {
+		return 0
+	}
+
+	if index >= framesAmount {
+		return framesAmount - 1
+	}
+
+	return index
+}
+
+// buildFrames processes the whole recording at once, so moving backward is as cheap as moving forward.
+func buildFrames(recording simulation.Recording) []frame {
+	worldMatrix := worldMap{cities: make([]city, 0), citiesIndex: make(map[string]int), alive: len(recording.Header.Aliens)}
+
+	cityNames := make([]string, 0, len(recording.Header.Layout))
+	for cityName := range recording.Header.Layout {
+		cityNames = append(cityNames, cityName)
+	}
+	sort.Strings(cityNames)
+
+	for _, cityName := range cityNames {
+		worldMatrix.save(city{name: cityName})
+	}
+
+	positions := make(map[string]string)
+	for alien, cityName := range recording.Header.Aliens {
+		positions[alien] = cityName
+	}
+
+	// Same randomizer as the live run, so the weapons in the logs match
+	randomizer := rand.New(rand.NewSource(recording.Header.Seed))
+
+	frames := make([]frame, 0, len(recording.Events))
+	logs := make([]string, 0)
+
+	for _, event := range recording.Events {
+		worldMatrix.clear()
+
+		for _, move := range event.Moves {
+			positions[move.Alien] = move.To
+		}
+
+		for _, battle := range event.RoadBattles {
+			worldMatrix.bury(len(battle.Casualties))
+			logs = append(logs, roadKillLog(earth.RoadBattleReport{
+				InvolvedAliens: battle.Aliens,
+				From:           battle.From,
+				To:             battle.To,
+				Species:        battle.Species,
+				Winner:         battle.Winner,
+				Casualties:     battle.Casualties,
+			}))
+
+			for _, alien := range battle.Casualties {
+				delete(positions, alien)
+			}
+		}
+
+		for _, wave := range event.Waves {
+			worldMatrix.land(len(wave.Aliens))
+			logs = append(logs, waveLog(earth.WaveReport(wave)))
+
+			for i, alien := range wave.Aliens {
+				positions[alien] = wave.Cities[i]
+			}
+		}
+
+		for _, defense := range event.Defenses {
+			worldMatrix.defend(defense.City, defense.Casualties, defense.Losses, defense.Repelled)
+			logs = append(logs, defenseLog(earth.DefenseReport(defense)))
+
+			for _, alien := range defense.Casualties {
+				delete(positions, alien)
+			}
+		}
+
+		for _, battle := range event.Battles {
+			report := battleReport(battle, event.Destroyed)
+
+			worldMatrix.battle(report)
+			logs = append(logs, killLog(report, randomizer))
+
+			for _, alien := range report.Casualties {
+				delete(positions, alien)
+			}
+		}
+
+		if event.StopReason != "" {
+			logs = append(logs, stopLog(event.StopReason))
+		}
+
+		for cityName, aliens := range groupByCity(positions) {
+			worldMatrix.save(city{name: cityName, aliens: aliens})
+		}
+		worldMatrix.guard(event.Garrisons)
+
+		frames = append(frames, frame{
+			cities: worldMatrix.prettySlice(),
+			status: worldMatrix.status(event.Tick),
+			// Capped so the next appends don't write into the logs of this frame
+			logs: logs[:len(logs):len(logs)],
+		})
+	}
+
+	return frames
+}
+
+// battleReport rebuilds the report of a recorded battle, recordings without casualties are of invasions
+// in which every alien died.
+func battleReport(battle simulation.BattleEvent, destroyed []string) earth.BattleReport {
+	report := earth.BattleReport{
+		City:           battle.City,
+		InvolvedAliens: battle.Aliens,
+		Species:        battle.Species,
+		Winner:         battle.Winner,
+		Casualties:     battle.Casualties,
+
+		HumanCasualties: battle.HumanCasualties,
+	}
+
+	if report.Casualties == nil {
+		report.Casualties = battle.Aliens
+		report.CityDestroyed = true
+
+		return report
+	}
+
+	for _, city := range destroyed {
+		if city == battle.City {
+			report.CityDestroyed = true
+		}
+	}
+
+	return report
+}
+
+// groupByCity turns Alien:City positions into City:[Aliens] sorted by name.
+func groupByCity(positions map[string]string) map[string][]string {
+	aliensByCity := make(map[string][]string)
+	for alien, cityName := range positions {
+		aliensByCity[cityName] = append(aliensByCity[cityName], alien)
+	}
+
+	for _, aliens := range aliensByCity {
+		sort.Strings(aliens)
+	}
+
+	return aliensByCity
+}
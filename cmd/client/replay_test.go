@@ -0,0 +1,154 @@
+package client
+
+import (
+	"testing"
+
+	"github.com/jattento/alien-invasion-simulator/internal/interface/terminal"
+	"github.com/jattento/alien-invasion-simulator/internal/simulation"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildFrames(t *testing.T) {
+	recording := simulation.Recording{
+		Header: simulation.RecordingHeader{
+			Layout: map[string]map[string]string{
+				"Paris":  {"east": "Berlin"},
+				"Berlin": {"west": "Paris"},
+			},
+			Aliens: map[string]string{"Alien1": "Paris", "Alien2": "Berlin"},
+		},
+		Events: []simulation.Event{
+			{Tick: 0},
+			{Tick: 1, Moves: []simulation.MoveEvent{
+				{Alien: "Alien1", From: "Paris", To: "Paris", Direction: "stayed"},
+				{Alien: "Alien2", From: "Berlin", To: "Paris", Direction: "west"},
+			}, Battles: []simulation.BattleEvent{{City: "Paris", Aliens: []string{"Alien1", "Alien2"}}}},
+		},
+	}
+
+	frames := buildFrames(recording)
+	require.Len(t, frames, 2)
+
+	assert.Equal(t, []string{"🏠🌳Berlin🌳🏠(👽Alien2)", "🏠🌳Paris🌳🏠(👽Alien1)"}, frames[0].cities)
+	assert.Empty(t, frames[0].logs)
+	assert.Contains(t, frames[0].status, "👽  :  2")
+
+	assert.Equal(t, []string{"🏠🌳Berlin🌳🏠()", "🔥🔥Paris🔥🔥(💀️Alien1, 💀️Alien2)"}, frames[1].cities)
+	require.Len(t, frames[1].logs, 1)
+	assert.Contains(t, frames[1].logs[0], `killed each other in "Paris"`)
+	assert.Contains(t, frames[1].status, "💀  :  2")
+}
+
+func TestBuildFrames_RoadBattles(t *testing.T) {
+	recording := simulation.Recording{
+		Header: simulation.RecordingHeader{
+			Layout: map[string]map[string]string{"Paris": {"east": "Berlin"}, "Berlin": {"west": "Paris"}},
+			Aliens: map[string]string{"Alien1": "Paris", "Alien2": "Berlin"},
+		},
+		Events: []simulation.Event{
+			{Tick: 0},
+			{Tick: 1, Moves: []simulation.MoveEvent{
+				{Alien: "Alien2", From: "Berlin", To: "Paris", Direction: "west"},
+			}, RoadBattles: []simulation.RoadBattleEvent{
+				{From: "Paris", To: "Berlin", Aliens: []string{"Alien1", "Alien2"}, Winner: "Alien2", Casualties: []string{"Alien1"}},
+			}},
+		},
+	}
+
+	frames := buildFrames(recording)
+	require.Len(t, frames, 2)
+
+	assert.Equal(t, []string{"🏠🌳Berlin🌳🏠()", "🏠🌳Paris🌳🏠(👽Alien2)"}, frames[1].cities)
+	require.Len(t, frames[1].logs, 1)
+	assert.Contains(t, frames[1].logs[0], "ran over")
+	assert.Contains(t, frames[1].status, "👽  :  1")
+	assert.Contains(t, frames[1].status, "💀  :  1")
+}
+
+func TestBuildFrames_Waves(t *testing.T) {
+	recording := simulation.Recording{
+		Header: simulation.RecordingHeader{
+			Layout: map[string]map[string]string{"Paris": {"east": "Berlin"}, "Berlin": {"west": "Paris"}},
+			Aliens: map[string]string{"Alien1": "Paris"},
+		},
+		Events: []simulation.Event{
+			{Tick: 0},
+			{Tick: 1, Waves: []simulation.WaveEvent{
+				{Aliens: []string{"Alien2"}, Cities: []string{"Berlin"}, Species: []string{"scout"}},
+			}},
+		},
+	}
+
+	frames := buildFrames(recording)
+	require.Len(t, frames, 2)
+
+	assert.Equal(t, []string{"🏠🌳Berlin🌳🏠(👽Alien2)", "🏠🌳Paris🌳🏠(👽Alien1)"}, frames[1].cities)
+	require.Len(t, frames[1].logs, 1)
+	assert.Contains(t, frames[1].logs[0], `🛸 1 aliens landed: 👽 "Alien2" (scout) in "Berlin"`)
+	assert.Contains(t, frames[1].status, "👽  :  2")
+}
+
+func TestBuildFrames_StopReason(t *testing.T) {
+	recording := simulation.Recording{
+		Header: simulation.RecordingHeader{
+			Layout: map[string]map[string]string{"Paris": {}, "Berlin": {}},
+			Aliens: map[string]string{"Alien1": "Paris", "Alien2": "Berlin"},
+		},
+		Events: []simulation.Event{{Tick: 0}, {Tick: 1, StopReason: "no battle is possible anymore"}},
+	}
+
+	frames := buildFrames(recording)
+	require.Len(t, frames, 2)
+
+	assert.Empty(t, frames[0].logs)
+	assert.Equal(t, []string{"🏁 The invasion stopped: no battle is possible anymore"}, frames[1].logs)
+}
+
+func TestBattleReport(t *testing.T) {
+	battle := simulation.BattleEvent{City: "Paris", Aliens: []string{"Alien1", "Alien2"}, Winner: "Alien2", Casualties: []string{"Alien1"}}
+
+	report := battleReport(battle, []string{"Berlin"})
+	assert.Equal(t, []string{"Alien1"}, report.Casualties)
+	assert.False(t, report.CityDestroyed)
+
+	battle.HumanCasualties = 2000
+	report = battleReport(battle, []string{"Paris"})
+	assert.True(t, report.CityDestroyed)
+	assert.Equal(t, 2000, report.HumanCasualties)
+
+	// Recordings without casualties are of invasions in which every alien died
+	report = battleReport(simulation.BattleEvent{City: "Paris", Aliens: []string{"Alien1", "Alien2"}}, nil)
+	assert.Equal(t, []string{"Alien1", "Alien2"}, report.Casualties)
+	assert.True(t, report.CityDestroyed)
+}
+
+func TestApplyCommand(t *testing.T) {
+	testCases := []struct {
+		name           string
+		command        terminal.Command
+		current        int
+		paused         bool
+		expectedFrame  int
+		expectedPaused bool
+	}{
+		{"pause", terminal.CommandPause, 3, false, 3, true},
+		{"resume", terminal.CommandPause, 3, true, 3, false},
+		{"step forward", terminal.CommandStepForward, 3, false, 4, true},
+		{"step forward at the end", terminal.CommandStepForward, 19, false, 19, true},
+		{"step backward", terminal.CommandStepBackward, 3, false, 2, true},
+		{"step backward at the start", terminal.CommandStepBackward, 0, false, 0, true},
+		{"jump forward", terminal.CommandJumpForward, 3, false, 13, true},
+		{"jump backward", terminal.CommandJumpBackward, 3, false, 0, true},
+		{"jump start", terminal.CommandJumpStart, 7, false, 0, true},
+		{"jump end", terminal.CommandJumpEnd, 7, false, 19, true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			frame, paused := applyCommand(tc.command, tc.current, tc.paused, 20)
+			assert.Equal(t, tc.expectedFrame, frame)
+			assert.Equal(t, tc.expectedPaused, paused)
+		})
+	}
+}
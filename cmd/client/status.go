@@ -2,7 +2,10 @@ package client
 
 import (
 	"fmt"
+	"sort"
 	"strings"
+
+	"github.com/jattento/alien-invasion-simulator/internal/earth"
 )
 
 type worldMap struct {
@@ -12,12 +15,31 @@ type worldMap struct {
 	destroyed    int
 	alive        int
 	dead         int
+
+	// defenders is set once any garrison was seen, so the invasions without them show the same status
+	defenders      bool
+	defenderLosses int
+
+	// humanCasualties is the population of every destroyed city
+	humanCasualties int
+
+	// incoming are the aliens of the waves that didn't land yet
+	incoming int
 }
 
 type city struct {
 	name      string
 	aliens    []string
 	destroyed bool
+
+	// garrison is the strength of the defenders at the city
+	garrison int
+
+	// saved is set when the defenders repelled every alien that reached the city
+	saved bool
+
+	// critical is set when destroying the city would cut the map apart
+	critical bool
 }
 
 func (world *worldMap) prettySlice() []string {
@@ -47,14 +69,44 @@ func (c city) fmtName() string {
 		return fmt.Sprint("🔥🔥" + c.name + "🔥🔥")
 	}
 
-	return fmt.Sprint("🏠🌳" + c.name + "🌳🏠")
+	name := "🏠🌳" + c.name + "🌳🏠"
+	if c.saved {
+		name = "🏰🌳" + c.name + "🌳🏰"
+	}
+
+	if c.critical {
+		name += "⚠️"
+	}
+
+	if c.garrison > 0 {
+		name += fmt.Sprintf("🛡️%d", c.garrison)
+	}
+
+	return name
+}
+
+// status returns the counters line shown below the map.
+func (world *worldMap) status(tick int) string {
+	status := fmt.Sprintf("🕒  :  %v   |   👽  :  %v   |   💀  :  %v   |   🏡  :  %v   |   🔥  :  %v",
+		tick, world.alive, world.dead, world.notDestroyed, world.destroyed)
+
+	if world.defenders {
+		status += fmt.Sprintf("   |   🛡️  :  -%v   |   🏰  :  %v", world.defenderLosses, len(world.savedCities()))
+	}
+
+	if world.incoming > 0 {
+		status += fmt.Sprintf("   |   🛸  :  %v", world.incoming)
+	}
+
+	return status
 }
 
-// Clear aliens positions
+// Clear aliens and garrisons positions
 func (world *worldMap) clear() {
 	for i := 0; i < len(world.cities); i++ {
 		if !world.cities[i].destroyed {
 			world.cities[i].aliens = make([]string, 0)
+			world.cities[i].garrison = 0
 		}
 	}
 }
@@ -73,6 +125,12 @@ func (world *worldMap) save(c city) {
 			world.dead += len(c.aliens)
 		}
 
+		if !c.destroyed {
+			c.garrison, c.saved = existingCity.garrison, existingCity.saved
+		}
+
+		c.critical = existingCity.critical
+
 		world.cities[i] = c
 		return
 	}
@@ -82,3 +140,61 @@ func (world *worldMap) save(c city) {
 	world.cities = append(world.cities, c)
 	world.citiesIndex[c.name] = len(world.cities) - 1
 }
+
+// battle saves the casualties of a battle, the survivors are saved with the rest of the alien positions.
+func (world *worldMap) battle(report earth.BattleReport) {
+	world.humanCasualties += report.HumanCasualties
+
+	if report.CityDestroyed {
+		world.save(city{name: report.City, aliens: report.Casualties, destroyed: true})
+		return
+	}
+
+	world.bury(len(report.Casualties))
+}
+
+// land counts the aliens of a wave, their positions are saved with the rest.
+func (world *worldMap) land(aliens int) {
+	world.alive += aliens
+}
+
+// bury counts aliens that died without destroying a city.
+func (world *worldMap) bury(casualties int) {
+	world.alive -= casualties
+	world.dead += casualties
+}
+
+// guard saves the City:Strength of the garrisons at each city.
+func (world *worldMap) guard(garrisons map[string]int) {
+	for name, strength := range garrisons {
+		world.defenders = true
+
+		if i, exist := world.citiesIndex[name]; exist && !world.cities[i].destroyed {
+			world.cities[i].garrison = strength
+		}
+	}
+}
+
+// defend saves the outcome of the defenders fighting the aliens at a city.
+func (world *worldMap) defend(name string, casualties []string, losses int, repelled bool) {
+	world.defenders = true
+	world.defenderLosses += losses
+	world.bury(len(casualties))
+
+	if i, exist := world.citiesIndex[name]; exist && repelled && !world.cities[i].destroyed {
+		world.cities[i].saved = true
+	}
+}
+
+// savedCities returns the standing cities in which the defenders repelled the aliens, sorted by name.
+func (world *worldMap) savedCities() []string {
+	saved := make([]string, 0)
+	for _, c := range world.cities {
+		if c.saved && !c.destroyed {
+			saved = append(saved, c.name)
+		}
+	}
+	sort.Strings(saved)
+
+	return saved
+}
@@ -3,6 +3,7 @@ package client
 import (
 	"testing"
 
+	"github.com/jattento/alien-invasion-simulator/internal/earth"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -132,3 +133,66 @@ func TestSave(t *testing.T) {
 	assert.Equal(t, 1, world.destroyed)
 	assert.Equal(t, 0, world.alive)
 }
+
+func TestDefenders(t *testing.T) {
+	world := &worldMap{cities: make([]city, 0), citiesIndex: make(map[string]int), alive: 3}
+	world.save(city{name: "Paris"})
+	world.save(city{name: "Rome"})
+
+	assert.NotContains(t, world.status(0), "🛡️", "invasions without defenders keep the same status")
+
+	world.guard(map[string]int{"Paris": 3})
+	world.defend("Paris", []string{"Alien1", "Alien2"}, 2, true)
+	world.save(city{name: "Paris", aliens: []string{}})
+
+	assert.Equal(t, []string{"🏰🌳Paris🌳🏰🛡️3()", "🏠🌳Rome🌳🏠()"}, world.prettySlice())
+	assert.Equal(t, []string{"Paris"}, world.savedCities())
+	assert.Equal(t, 1, world.alive)
+	assert.Equal(t, 2, world.dead)
+	assert.Contains(t, world.status(1), "🛡️  :  -2   |   🏰  :  1")
+
+	world.clear()
+	assert.Equal(t, 0, world.cities[0].garrison)
+	assert.True(t, world.cities[0].saved, "saved cities stay saved")
+
+	world.save(city{name: "Paris", aliens: []string{"Alien3"}, destroyed: true})
+	assert.Empty(t, world.savedCities(), "destroyed cities aren't saved")
+}
+
+func TestBattle(t *testing.T) {
+	world := &worldMap{cities: make([]city, 0), citiesIndex: make(map[string]int), alive: 4}
+	world.save(city{name: "Paris"})
+	world.save(city{name: "Rome"})
+
+	world.battle(earth.BattleReport{City: "Paris", Casualties: []string{"Alien1"}, Winner: "Alien2"})
+	assert.Equal(t, 1, world.dead)
+	assert.Equal(t, 2, world.notDestroyed, "battles that don't destroy the city only kill aliens")
+
+	world.battle(earth.BattleReport{City: "Rome", Casualties: []string{"Alien3", "Alien4"}, CityDestroyed: true, HumanCasualties: 500})
+	assert.Equal(t, 3, world.dead)
+	assert.Equal(t, 1, world.destroyed)
+	assert.Equal(t, 500, world.humanCasualties)
+}
+
+func TestLand(t *testing.T) {
+	world := &worldMap{cities: make([]city, 0), citiesIndex: make(map[string]int), alive: 1, incoming: 3}
+	assert.Contains(t, world.status(0), "🛸  :  3")
+
+	world.land(3)
+	world.incoming = 0
+	assert.Equal(t, 4, world.alive)
+	assert.NotContains(t, world.status(1), "🛸", "the status only shows the aliens still coming")
+}
+
+func TestCriticalCities(t *testing.T) {
+	world := &worldMap{cities: make([]city, 0), citiesIndex: make(map[string]int), alive: 1}
+	world.save(city{name: "Paris", critical: true})
+	world.save(city{name: "Rome"})
+
+	// The mark is kept while the aliens move around
+	world.save(city{name: "Paris", aliens: []string{"Zog"}})
+	assert.Equal(t, []string{"🏠🌳Paris🌳🏠⚠️(👽Zog)", "🏠🌳Rome🌳🏠()"}, world.prettySlice())
+
+	world.save(city{name: "Paris", aliens: []string{"Zog"}, destroyed: true})
+	assert.Equal(t, "🔥🔥Paris🔥🔥", world.cities[0].fmtName())
+}
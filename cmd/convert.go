@@ -0,0 +1,63 @@
+package cmd
+
+import (
+	"log"
+	"os"
+
+	"github.com/jattento/alien-invasion-simulator/internal/platform/system"
+	"github.com/jattento/alien-invasion-simulator/internal/simulation"
+	"github.com/spf13/cobra"
+)
+
+var (
+	_to *string
+
+	convertCmd = &cobra.Command{
+		Use:   "convert <input> <output>",
+		Short: "Translate a city config file between the text, json and yaml formats",
+		Long: "Translate a city config file between the text, json and yaml formats. " +
+			"The input format is set with --format and the output one with --to, both are picked from the " +
+			"file extension if not set. Use - as output to write to stdout.",
+		Args: cobra.ExactArgs(2),
+		Run: func(cmd *cobra.Command, args []string) {
+			input, output := args[0], args[1]
+
+			loaded, err := fileManager(*_format).Load(input)
+			if err != nil {
+				log.Fatal("failed loading city config: ", err.Error())
+			}
+
+			loaded = simulation.SplitCityAttributes(loaded)
+
+			format, err := system.ParseFormat(*_to)
+			if err != nil {
+				log.Fatal("invalid --to: ", err.Error())
+			}
+
+			if format == system.FormatAuto {
+				format = system.FormatFromPath(output)
+			}
+
+			if output == "-" {
+				if err := simulation.WriteCityLayout(os.Stdout, loaded, format); err != nil {
+					log.Fatal("failed converting city config: ", err.Error())
+				}
+
+				return
+			}
+
+			outputFile, closeOutputFile := createFile(output)
+			defer closeOutputFile()
+
+			if err := simulation.WriteCityLayout(outputFile, loaded, format); err != nil {
+				log.Fatal("failed converting city config: ", err.Error())
+			}
+		},
+	}
+)
+
+func init() {
+	_to = convertCmd.Flags().String("to", "", "Format of the output: text, json or yaml, picked from the file extension if not set.")
+
+	rootCmd.AddCommand(convertCmd)
+}
@@ -0,0 +1,58 @@
+package cmd
+
+import (
+	"log"
+	"os"
+
+	"github.com/jattento/alien-invasion-simulator/internal/simulation"
+	"github.com/spf13/cobra"
+)
+
+// _dotFormat is the only format of the export, taken by --format along with the ones of the city config.
+const _dotFormat = "dot"
+
+var (
+	_exportOutput *string
+
+	exportCmd = &cobra.Command{
+		Use:   "export",
+		Short: "Export the city layout as a picture description, before any alien lands",
+		Long: "Export the city layout loaded from --city-config, or generated from --matrix, --cities and --seed, " +
+			"as a Graphviz DOT graph in which every city is placed following its roads. " +
+			"Render it with: dot -Tpng world.dot -o world.png. " +
+			"--format dot is the format of the export, with it the one of the city config is picked from the file extension.",
+		Run: func(cmd *cobra.Command, args []string) {
+			// Any other format is the one of the city config, the export is always dot
+			cityConfigFormat := *_format
+			if cityConfigFormat == _dotFormat {
+				cityConfigFormat = ""
+			}
+
+			directions := layoutDirections()
+
+			sim, err := simulation.NewInvasion(*_cityConfig, 0, systemManager(*_cityConfig, cityConfigFormat, directions),
+				*_days, *_cities, *_matrix, resolveSeed(cmd), simulation.WithDirections(directions))
+			if err != nil {
+				log.Fatal("failed loading city layout: ", err.Error())
+			}
+
+			output := os.Stdout
+			if *_exportOutput != "" {
+				outputFile, closeOutputFile := createFile(*_exportOutput)
+				defer closeOutputFile()
+
+				output = outputFile
+			}
+
+			if err := sim.WriteDOT(output); err != nil {
+				log.Fatal("failed exporting city layout: ", err.Error())
+			}
+		},
+	}
+)
+
+func init() {
+	_exportOutput = exportCmd.Flags().StringP("output", "o", "", "Path where the export is written, stdout if not set.")
+
+	rootCmd.AddCommand(exportCmd)
+}
@@ -0,0 +1,84 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/jattento/alien-invasion-simulator/internal/simulation"
+	"github.com/spf13/cobra"
+)
+
+var (
+	_fix   *bool
+	_write *bool
+
+	fmtCmd = &cobra.Command{
+		Use:   "fmt <city-config>",
+		Short: "Normalize a city config file, sorting cities and roads",
+		Long: "Normalize a city config file, sorting cities by name and roads in compass order. " +
+			"With --fix the missing reciprocal roads are inferred, the problems that can't be fixed are printed " +
+			"and the exit code is 1. With --write the file is left untouched while there are problems that can't be fixed.",
+		Args: cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			path := args[0]
+
+			manager := fileManager(*_format)
+			directions := layoutDirections()
+
+			loaded, err := manager.Load(path)
+			if err != nil {
+				log.Fatal("failed loading city config: ", err.Error())
+			}
+
+			loaded = simulation.SplitCityAttributes(loaded)
+
+			if *_fix {
+				var added []simulation.Road
+				loaded.Records, added = simulation.RepairCityLayout(loaded.Records, directions)
+
+				for _, road := range added {
+					fmt.Fprintf(os.Stderr, "%s: added road %s\n", path, road)
+				}
+			}
+
+			var formatted bytes.Buffer
+			if err := simulation.WriteCityLayout(&formatted, loaded, manager.FormatOf(path)); err != nil {
+				log.Fatal("failed formatting city config: ", err.Error())
+			}
+
+			// The problems are validated before writing, their positions point to the file as it was loaded
+			var layoutError *simulation.LayoutError
+			errors.As(simulation.ValidateCityFile(loaded, directions), &layoutError)
+
+			switch {
+			case !*_write:
+				if _, err := os.Stdout.Write(formatted.Bytes()); err != nil {
+					log.Fatal("failed writing city config: ", err.Error())
+				}
+			case layoutError == nil:
+				if err := os.WriteFile(path, formatted.Bytes(), 0o644); err != nil {
+					log.Fatal("failed writing city config: ", err.Error())
+				}
+			}
+
+			if layoutError != nil {
+				for _, problem := range layoutError.Problems {
+					fmt.Fprintf(os.Stderr, "%s:%d:%d: %s %s: %s\n",
+						path, problem.Line, problem.Column, problem.City, problem.Direction, problem.Reason)
+				}
+
+				os.Exit(1)
+			}
+		},
+	}
+)
+
+func init() {
+	_fix = fmtCmd.Flags().Bool("fix", false, "Infer the missing reciprocal roads.")
+	_write = fmtCmd.Flags().BoolP("write", "w", false, "Write the result to the file instead of stdout.")
+
+	rootCmd.AddCommand(fmtCmd)
+}
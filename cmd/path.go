@@ -0,0 +1,112 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/jattento/alien-invasion-simulator/internal/platform/datastructure"
+	"github.com/jattento/alien-invasion-simulator/internal/simulation"
+	"github.com/spf13/cobra"
+)
+
+var (
+	_pathLayout   *string
+	_pathSnapshot *string
+	_pathAfter    *int
+
+	pathCmd = &cobra.Command{
+		Use:   "path <from> <to>",
+		Short: "Print the shortest way between two cities through the ones still standing",
+		Long: "Print the shortest way between two cities of the city layout loaded from --layout or --city-config, or generated from " +
+			"--matrix, --cities and --seed, and how far the nearest alien is from the destination. " +
+			"--snapshot looks for it in a saved invasion instead, and --after runs the invasion that many days before looking, " +
+			"so destroyed cities are avoided. The exit code is 1 if no road leads there.",
+		Args: cobra.ExactArgs(2),
+		Run: func(cmd *cobra.Command, args []string) {
+			from, to := args[0], args[1]
+
+			var sim *simulation.Invasion
+			if *_pathSnapshot != "" {
+				restored, err := simulation.RestoreInvasion(readSnapshot(*_pathSnapshot))
+				if err != nil {
+					log.Fatal("failed restoring simulation: ", err.Error())
+				}
+
+				sim = restored
+			} else {
+				cityConfig := *_cityConfig
+				if *_pathLayout != "" {
+					cityConfig = *_pathLayout
+				}
+
+				sim = newInvasion(cmd, cityConfig)
+			}
+
+			for day := 0; day < *_pathAfter; day++ {
+				if keepTicking, _ := sim.Tick(); !keepTicking {
+					break
+				}
+			}
+
+			route, err := sim.Route(from, to)
+			switch {
+			case errors.Is(err, datastructure.ErrPathNotFound):
+				fmt.Printf("No road leads from %q to %q\n", from, to)
+			case err != nil:
+				log.Fatal("failed looking for the path: ", err.Error())
+			default:
+				fmt.Println(formatRoute(from, route))
+				fmt.Printf("%s from %q to %q\n", roadsAmount(len(route)), from, to)
+			}
+
+			if sim.AliensAlive() > 0 {
+				roads, reachable, err := sim.AlienDistance(to)
+				switch {
+				case err != nil:
+					log.Fatal("failed looking for the aliens: ", err.Error())
+				case reachable && roads == 0:
+					fmt.Printf("An alien is already at %q\n", to)
+				case reachable:
+					fmt.Printf("The nearest alien is %s away from %q\n", roadsAmount(roads), to)
+				default:
+					fmt.Printf("No alien can reach %q\n", to)
+				}
+			}
+
+			if route == nil {
+				os.Exit(1)
+			}
+		},
+	}
+)
+
+// formatRoute returns the route as: A -east-> B -south-> C
+func formatRoute(from string, route []simulation.Road) string {
+	var builder strings.Builder
+
+	builder.WriteString(from)
+	for _, road := range route {
+		builder.WriteString(" -" + road.Direction + "-> " + road.To)
+	}
+
+	return builder.String()
+}
+
+func roadsAmount(roads int) string {
+	if roads == 1 {
+		return "1 road"
+	}
+
+	return fmt.Sprintf("%d roads", roads)
+}
+
+func init() {
+	_pathLayout = pathCmd.Flags().String("layout", "", "Path of the city config to look for the path in, --city-config if not set.")
+	_pathSnapshot = pathCmd.Flags().String("snapshot", "", "Path of a snapshot saved with --snapshot-out to look for the path in.")
+	_pathAfter = pathCmd.Flags().Int("after", 0, "Days the invasion runs before looking for the path.")
+
+	rootCmd.AddCommand(pathCmd)
+}
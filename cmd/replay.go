@@ -0,0 +1,45 @@
+package cmd
+
+import (
+	"log"
+	"os"
+
+	"github.com/jattento/alien-invasion-simulator/cmd/client"
+	"github.com/jattento/alien-invasion-simulator/internal/simulation"
+	"github.com/spf13/cobra"
+)
+
+var (
+	_startTick *int
+
+	replayCmd = &cobra.Command{
+		Use:   "replay <events-file>",
+		Short: "Play back an invasion recorded with --events-out",
+		Long: "Play back an invasion recorded with --events-out in the terminal UI, " +
+			"it can be paused, moved day by day or 10 days at a time and jump to the first and last day. " +
+			"Use --tick to start at a chosen day, the playback can't go to a typed day once it started.",
+		Args: cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			file, err := os.Open(args[0])
+			if err != nil {
+				log.Fatal("failed opening recording: ", err.Error())
+			}
+
+			recording, err := simulation.ReadRecording(file)
+			_ = file.Close()
+			if err != nil {
+				log.Fatal("failed reading recording: ", err.Error())
+			}
+
+			if err := client.Replay(recording, *_startTick); err != nil {
+				log.Fatal("failed replaying: ", err.Error())
+			}
+		},
+	}
+)
+
+func init() {
+	_startTick = replayCmd.Flags().IntP("tick", "t", 0, "Day where the playback starts, paused. The only way to go to a chosen day.")
+
+	rootCmd.AddCommand(replayCmd)
+}
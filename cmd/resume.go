@@ -0,0 +1,90 @@
+package cmd
+
+import (
+	"log"
+	"os"
+
+	"github.com/jattento/alien-invasion-simulator/internal/simulation"
+	"github.com/spf13/cobra"
+)
+
+var resumeCmd = &cobra.Command{
+	Use:   "resume <snapshot-file>",
+	Short: "Resume an invasion saved with --snapshot-out",
+	Long: "Resume an invasion saved with --snapshot-out exactly where it was. " +
+		"Setting --seed branches a different future from the same state, --movement changes how aliens move, " +
+		"--battle how battles end, --collisions whether aliens fight on roads, --wave the aliens landing later and --days and --stop when it ends.",
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		snapshot := readSnapshot(args[0])
+
+		// The rules the snapshot was taken with are kept unless they are set again
+		var opts []simulation.Option
+		if cmd.Flags().Changed("movement") {
+			opts = append(opts, movementOption())
+		}
+
+		if cmd.Flags().Changed("battle") {
+			opts = append(opts, battleOption())
+		}
+
+		if cmd.Flags().Changed("collisions") {
+			opts = append(opts, simulation.WithEnRouteCollisions(*_collisions))
+		}
+
+		if cmd.Flags().Changed("defender-movement") {
+			opts = append(opts, defenderMovementOption())
+		}
+
+		if cmd.Flags().Changed("wave") {
+			opts = append(opts, wavesOption())
+		}
+
+		if cmd.Flags().Changed("stop") {
+			opts = append(opts, stopConditionsOption())
+		}
+
+		// The waves landing after resuming follow the species mix of the snapshot unless the flag replaces it
+		if cmd.Flags().Changed("species-mix") {
+			opts = append(opts, speciesMixOption())
+		}
+
+		sim, err := simulation.RestoreInvasion(snapshot, opts...)
+		if err != nil {
+			log.Fatal("failed restoring simulation: ", err.Error())
+		}
+
+		if cmd.Flags().Changed("seed") {
+			sim.Reseed(*_seed)
+		}
+
+		if cmd.Flags().Changed("days") {
+			sim.SetTickLimit(*_days)
+		}
+
+		os.Exit(run(sim))
+	},
+}
+
+// readSnapshot returns the snapshot of the file at path.
+func readSnapshot(path string) simulation.Snapshot {
+	file, err := os.Open(path)
+	if err != nil {
+		log.Fatal("failed opening snapshot: ", err.Error())
+	}
+
+	defer func() { _ = file.Close() }()
+
+	snapshot, err := simulation.ReadSnapshot(file)
+	if err != nil {
+		log.Fatal("failed reading snapshot: ", err.Error())
+	}
+
+	return snapshot
+}
+
+func init() {
+	addRunFlags(resumeCmd.Flags())
+
+	rootCmd.AddCommand(resumeCmd)
+}
@@ -1,51 +1,471 @@
 package cmd
 
 import (
+	"fmt"
 	"log"
+	"os"
+	"time"
 
 	"github.com/jattento/alien-invasion-simulator/cmd/client"
+	"github.com/jattento/alien-invasion-simulator/internal/earth"
 	"github.com/jattento/alien-invasion-simulator/internal/platform/system"
 	"github.com/jattento/alien-invasion-simulator/internal/simulation"
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 )
 
 var (
-	_aliens     *int
-	_days       *int
-	_cityConfig *string
-	_matrix     *int
-	_cities     *int
+	_aliens        *int
+	_days          *int
+	_cityConfig    *string
+	_matrix        *int
+	_cities        *int
+	_seed          *int64
+	_fixLayout     *bool
+	_directions    *string
+	_format        *string
+	_placement     *string
+	_placementFile *string
+	_movement      *string
+	_species       *string
+	_speciesMix    *string
+	_battle        *string
+	_collisions    *bool
+	_waves         *[]string
+	_stop          *[]string
+
+	_defenders        *string
+	_garrisons        *int
+	_garrisonStrength *int
+	_defenderMovement *string
+
+	// Shared by every command that runs a single invasion, see addRunFlags
+	_headless     bool
+	_output       string
+	_eventsOut    string
+	_snapshotOut  string
+	_snapshotTick int
+	_dotOut       string
+	_critical     bool
 
 	rootCmd = &cobra.Command{
 		Use:   "alien-sim",
 		Short: "An alien invasion simulator",
 		Long:  "An alien invasion simulator with 99% accuracy.",
 		Run: func(cmd *cobra.Command, args []string) {
-			sim, err := simulation.NewInvasion(*_cityConfig, *_aliens, system.NewManager(), *_days, *_cities, *_matrix)
-			if err != nil {
-				log.Fatal("failed creating simulation: ", err.Error())
-			}
-
-			if err := client.Run(sim, *_aliens); err != nil {
-				log.Fatal("failed creating client: ", err.Error())
-			}
+			os.Exit(run(newInvasion(cmd, *_cityConfig)))
 		},
 	}
 )
 
+// newInvasion returns the invasion described by the flags, over the layout of cityConfig or a generated one if empty.
+func newInvasion(cmd *cobra.Command, cityConfig string) *simulation.Invasion {
+	seed := resolveSeed(cmd)
+	directions := layoutDirections()
+
+	sim, err := simulation.NewInvasion(cityConfig, resolveAliens(cmd), systemManager(cityConfig, *_format, directions), *_days, *_cities, *_matrix, seed,
+		append(invasionOptions(), simulation.WithDirections(directions))...)
+	if err != nil {
+		log.Fatal("failed creating simulation: ", err.Error())
+	}
+
+	return sim
+}
+
+// layoutDirections returns the directions set by flag, every layout gets its own to register its labels.
+func layoutDirections() *earth.Directions {
+	directions, err := earth.ParseDirections(*_directions)
+	if err != nil {
+		log.Fatal("invalid --directions: ", err.Error())
+	}
+
+	return directions
+}
+
+// systemManager returns the manager used to load cityConfig in format, which repairs it with the inverses of
+// directions if it was asked by flag.
+func systemManager(cityConfig, format string, directions *earth.Directions) simulation.SystemManager {
+	manager := fileManager(format)
+
+	// The generated layout is always written in the text format
+	if cityConfig == "" {
+		manager.Format = system.FormatAuto
+	}
+
+	if *_fixLayout {
+		return simulation.NewLayoutFixer(manager, directions, os.Stderr)
+	}
+
+	return manager
+}
+
+// fileManager returns the manager used to read city config files in format, usually the one set by flag.
+func fileManager(format string) *system.Manager {
+	parsedFormat, err := system.ParseFormat(format)
+	if err != nil {
+		log.Fatal("invalid --format: ", err.Error())
+	}
+
+	manager := system.NewManager()
+	manager.Format = parsedFormat
+
+	return manager
+}
+
+// invasionOptions returns the rules of the invasion set by flags.
+func invasionOptions() []simulation.Option {
+	if *_garrisonStrength < 1 {
+		log.Fatalf("invalid --garrison-strength: %d, must be at least 1", *_garrisonStrength)
+	}
+
+	opts := []simulation.Option{
+		placementOption(),
+		movementOption(),
+		battleOption(),
+		simulation.WithEnRouteCollisions(*_collisions),
+		simulation.WithRandomGarrisons(*_garrisons, *_garrisonStrength),
+		defenderMovementOption(),
+	}
+
+	if *_defenders != "" {
+		opts = append(opts, simulation.WithGarrisons(readDefenders()))
+	}
+
+	if *_speciesMix != "" {
+		opts = append(opts, speciesMixOption())
+	}
+
+	if len(*_waves) > 0 {
+		opts = append(opts, wavesOption())
+	}
+
+	if len(*_stop) > 0 {
+		opts = append(opts, stopConditionsOption())
+	}
+
+	return opts
+}
+
+// speciesMixOption returns the species of the aliens as set by flag.
+func speciesMixOption() simulation.Option {
+	mix, err := simulation.ParseSpeciesMix(*_speciesMix, availableSpecies())
+	if err != nil {
+		log.Fatal("invalid --species-mix: ", err.Error())
+	}
+
+	return simulation.WithSpeciesMix(mix)
+}
+
+// wavesOption returns the waves of aliens set by flag.
+func wavesOption() simulation.Option {
+	waves := make([]earth.Wave, 0, len(*_waves))
+	for _, spec := range *_waves {
+		wave, err := earth.ParseWave(spec)
+		if err != nil {
+			log.Fatal("invalid --wave: ", err.Error())
+		}
+
+		waves = append(waves, wave)
+	}
+
+	return simulation.WithWaves(waves)
+}
+
+// placementOption returns where the aliens land when the invasion starts as set by flag.
+func placementOption() simulation.Option {
+	if *_placementFile != "" {
+		return simulation.WithPlacement(readPlacement())
+	}
+
+	placement, err := earth.ParsePlacement(*_placement)
+	if err != nil {
+		log.Fatal("invalid --placement: ", err.Error())
+	}
+
+	return simulation.WithPlacement(placement)
+}
+
+// stopConditionsOption returns the conditions that end the invasion before --days set by flag.
+func stopConditionsOption() simulation.Option {
+	conditions := make([]simulation.StopCondition, 0, len(*_stop))
+	for _, spec := range *_stop {
+		condition, err := simulation.ParseStopCondition(spec)
+		if err != nil {
+			log.Fatal("invalid --stop: ", err.Error())
+		}
+
+		conditions = append(conditions, condition)
+	}
+
+	return simulation.WithStopConditions(conditions)
+}
+
+// movementOption returns how aliens move as set by flag.
+func movementOption() simulation.Option {
+	movement, err := earth.ParseMovement(*_movement)
+	if err != nil {
+		log.Fatal("invalid --movement: ", err.Error())
+	}
+
+	return simulation.WithMovement(movement)
+}
+
+// battleOption returns how battles end as set by flag.
+func battleOption() simulation.Option {
+	battle, err := earth.ParseBattle(*_battle)
+	if err != nil {
+		log.Fatal("invalid --battle: ", err.Error())
+	}
+
+	return simulation.WithBattle(battle)
+}
+
+// defenderMovementOption returns how garrisons move as set by flag.
+func defenderMovementOption() simulation.Option {
+	movement, err := earth.ParseMovement(*_defenderMovement)
+	if err != nil {
+		log.Fatal("invalid --defender-movement: ", err.Error())
+	}
+
+	return simulation.WithDefenderMovement(movement)
+}
+
+// readPlacement returns the placement of the placement file set by flag.
+func readPlacement() earth.CitiesPlacement {
+	file, err := os.Open(*_placementFile)
+	if err != nil {
+		log.Fatal("failed opening placement file: ", err.Error())
+	}
+
+	defer func() { _ = file.Close() }()
+
+	placement, err := simulation.ReadPlacement(file)
+	if err != nil {
+		log.Fatal("failed reading placement file: ", err.Error())
+	}
+
+	return placement
+}
+
+// readDefenders returns the garrisons of the defenders file set by flag.
+func readDefenders() []earth.GarrisonPlacement {
+	file, err := os.Open(*_defenders)
+	if err != nil {
+		log.Fatal("failed opening defenders file: ", err.Error())
+	}
+
+	defer func() { _ = file.Close() }()
+
+	placements, err := simulation.ReadDefenders(file)
+	if err != nil {
+		log.Fatal("failed reading defenders file: ", err.Error())
+	}
+
+	return placements
+}
+
+// availableSpecies returns the builtin species plus the ones of the species file set by flag.
+func availableSpecies() map[string]earth.Species {
+	if *_species == "" {
+		return earth.BuiltinSpecies()
+	}
+
+	file, err := os.Open(*_species)
+	if err != nil {
+		log.Fatal("failed opening species file: ", err.Error())
+	}
+
+	defer func() { _ = file.Close() }()
+
+	species, err := simulation.ReadSpecies(file)
+	if err != nil {
+		log.Fatal("failed reading species file: ", err.Error())
+	}
+
+	return species
+}
+
+// resolveAliens returns the amount of aliens set by flag, or the one of the placement file if only it is set.
+func resolveAliens(cmd *cobra.Command) int {
+	if *_placementFile == "" || cmd.Flags().Changed("aliens") {
+		return *_aliens
+	}
+
+	return len(readPlacement().Cities)
+}
+
+// resolveSeed returns the seed set by flag, or a random one if it wasn't set.
+func resolveSeed(cmd *cobra.Command) int64 {
+	if !cmd.Flags().Changed("seed") {
+		return time.Now().UnixNano()
+	}
+
+	return *_seed
+}
+
+// run runs the simulation in the terminal UI or headless depending on the flags,
+// and returns the exit code matching the outcome of the simulation.
+func run(sim *simulation.Invasion) int {
+	if _output != "" && !_headless {
+		log.Fatal("--output requires --headless")
+	}
+
+	opts := []client.Option{client.WithIncomingAliens(sim.AliensIncoming())}
+
+	if _critical {
+		opts = append(opts, client.WithCriticalCities(sim.AnalyzeLayout(false).CriticalCities))
+	}
+
+	if _eventsOut != "" {
+		eventsFile, closeEventsFile := createFile(_eventsOut)
+		defer closeEventsFile()
+
+		eventWriter := simulation.NewEventWriter(eventsFile, sim.Directions())
+		if err := eventWriter.WriteHeader(sim.RecordingHeader()); err != nil {
+			log.Fatal("failed writing events: ", err.Error())
+		}
+
+		opts = append(opts, client.WithTickHook(eventWriter.Write))
+	}
+
+	if _snapshotOut != "" {
+		opts = append(opts, client.WithTickHook(func(report simulation.TickReport) error {
+			if report.Tick != _snapshotTick {
+				return nil
+			}
+
+			snapshotFile, closeSnapshotFile := createFile(_snapshotOut)
+			defer closeSnapshotFile()
+
+			return simulation.WriteSnapshot(snapshotFile, sim.Snapshot())
+		}))
+	}
+
+	if _headless {
+		output := os.Stdout
+		if _output != "" {
+			outputFile, closeOutputFile := createFile(_output)
+			defer closeOutputFile()
+
+			output = outputFile
+		}
+
+		summary, err := client.RunHeadless(sim, sim.AliensAlive(), output, opts...)
+		if err != nil {
+			log.Fatal("failed running simulation: ", err.Error())
+		}
+
+		writeDOT(sim)
+
+		return summary.ExitCode()
+	}
+
+	if err := client.Run(sim, sim.AliensAlive(), opts...); err != nil {
+		log.Fatal("failed running simulation: ", err.Error())
+	}
+
+	writeDOT(sim)
+
+	// The terminal is cleared when the simulation is closed, so the seed is printed again to be replayable
+	fmt.Printf("seed: %d\n", sim.Seed())
+
+	return client.ExitCodeWorldSaved
+}
+
+// writeDOT writes the outcome of the invasion as a Graphviz graph if it was asked by flag.
+func writeDOT(sim *simulation.Invasion) {
+	if _dotOut == "" {
+		return
+	}
+
+	dotFile, closeDOTFile := createFile(_dotOut)
+	defer closeDOTFile()
+
+	if err := sim.WriteDOT(dotFile); err != nil {
+		log.Fatal("failed writing dot graph: ", err.Error())
+	}
+}
+
+// createFile exits the program if the file can't be created, the returned function closes it.
+func createFile(path string) (*os.File, func()) {
+	file, err := os.Create(path)
+	if err != nil {
+		log.Fatal("failed creating file: ", err.Error())
+	}
+
+	return file, func() {
+		if err := file.Close(); err != nil {
+			log.Println("WARNING: failed to close file:", path)
+		}
+	}
+}
+
+// addRunFlags adds the flags that control how a single invasion is run.
+func addRunFlags(flags *pflag.FlagSet) {
+	flags.BoolVar(&_headless, "headless", false, "Run the simulation without the terminal UI, as fast as possible.")
+	flags.StringVarP(&_output, "output", "o", "", "Path where the headless logs are written, stdout if not set (requires --headless).")
+	flags.StringVar(&_eventsOut, "events-out", "", "Path where every tick is written as a JSON line.")
+	flags.StringVar(&_snapshotOut, "snapshot-out", "", "Path where the invasion is saved, to be resumed later.")
+	flags.IntVar(&_snapshotTick, "snapshot-tick", 0, "Day after which the snapshot is taken.")
+	flags.StringVar(&_dotOut, "dot-out", "", "Path where the world is written as a Graphviz DOT graph when the invasion ends.")
+	flags.BoolVar(&_critical, "critical", false,
+		"Mark in the city list the cities that would cut the map apart if they were destroyed when the invasion starts.")
+}
+
 // Execute executes the root command.
 func Execute() error {
 	return rootCmd.Execute()
 }
 
 func init() {
-	_aliens = rootCmd.Flags().IntP("aliens", "a", 15, "Amount of aliens to spawn")
-	_days = rootCmd.Flags().IntP("days", "d", 10000, "Days until simulation ends.")
+	// Persistent flags are shared with the subcommands that also run invasions
+	_aliens = rootCmd.PersistentFlags().IntP("aliens", "a", 15, "Amount of aliens to spawn")
+	_days = rootCmd.PersistentFlags().IntP("days", "d", 10000, "Days until simulation ends.")
+
+	_cityConfig = rootCmd.PersistentFlags().String("city-config", "", "path where to find the city config file.")
+	_matrix = rootCmd.PersistentFlags().IntP("matrix", "m", 5, "Matrix size where the value is N when N*N=total matrix size.")
+	_cities = rootCmd.PersistentFlags().IntP("cities", "c", 20, "Amount of cities deployed in the matrix.")
+	_seed = rootCmd.PersistentFlags().Int64("seed", 0, "Seed used for every random decision, a random one is used if not set.")
+	_fixLayout = rootCmd.PersistentFlags().Bool("fix-layout", false, "Infer the missing reciprocal roads of the city config.")
+	_directions = rootCmd.PersistentFlags().String("directions", "",
+		"Directions of the city config beyond north, east, south, west, the diagonals, up and down, with their inverses: "+
+			"<name>:<inverse>,... A name alone is its own inverse, as are the roads marked with label:<name>.")
+	_placement = rootCmd.PersistentFlags().String("placement", "random",
+		"Where the aliens land when the invasion starts: random, spread, farthest, clustered, region[:radius], largest, "+
+			"degree, population, avoid-battles or cities:<name,...>.")
+	_placementFile = rootCmd.PersistentFlags().String("placement-file", "",
+		"Path of a YAML or JSON file with the city of each alien, --aliens is the amount of aliens of the file if not set.")
+	_movement = rootCmd.PersistentFlags().String("movement", "uniform",
+		"How aliens move: uniform, walk, lazy[:stay probability], momentum[:persistence], hunter or hold.")
+	_battle = rootCmd.PersistentFlags().String("battle", "annihilation",
+		"How battles end: annihilation, strength, threshold[:aliens] or chance[:destroy probability].")
+	_collisions = rootCmd.PersistentFlags().Bool("collisions", false,
+		"Aliens crossing the same road in opposite directions fight on it, following the --battle rules.")
+	_defenders = rootCmd.PersistentFlags().String("defenders", "", "Path of a YAML or JSON file with the garrisons defending the cities.")
+	_garrisons = rootCmd.PersistentFlags().Int("garrisons", 0, "Amount of garrisons placed at random cities.")
+	_garrisonStrength = rootCmd.PersistentFlags().Int("garrison-strength", 3, "Strength of the garrisons placed by --garrisons.")
+	_defenderMovement = rootCmd.PersistentFlags().String("defender-movement", "hold",
+		"How garrisons move, same values as --movement plus hold.")
+	_species = rootCmd.PersistentFlags().String("species", "", "Path of a YAML or JSON file with more species for --species-mix.")
+	_speciesMix = rootCmd.PersistentFlags().String("species-mix", "",
+		"Species of the aliens with their weights, like scout=50,brute=30,hive=20. Every alien is common if not set.")
+	_waves = rootCmd.PersistentFlags().StringArray("wave", nil,
+		"Aliens landing during the invasion as <aliens>@<day>[:placement], placement is random, spread, farthest, "+
+			"clustered, region[:radius], largest, degree, population, avoid-battles or cities:<name,...>. Can be repeated.")
+	_stop = rootCmd.PersistentFlags().StringArray("stop", nil,
+		"Ends the invasion before --days once every alien is isolated, no battle is possible anymore, a percent of "+
+			"the cities is destroyed or a city is destroyed: isolated, no-battles, destroyed:<percent> or city:<name>. Can be repeated.")
+	_format = rootCmd.PersistentFlags().String("format", "", "Format of the city config: text, json or yaml, picked from the file extension if not set. "+
+		"The export command also takes dot, the format of the export.")
 
-	_cityConfig = rootCmd.Flags().String("city-config", "", "path where to find the city config file.")
-	_matrix = rootCmd.Flags().IntP("matrix", "m", 5, "Matrix size where the value is N when N*N=total matrix size.")
-	_cities = rootCmd.Flags().IntP("cities", "c", 20, "Amount of cities deployed in the matrix.")
+	addRunFlags(rootCmd.Flags())
 
 	rootCmd.MarkFlagsMutuallyExclusive("city-config", "matrix")
 	rootCmd.MarkFlagsMutuallyExclusive("city-config", "cities")
+	rootCmd.MarkFlagsMutuallyExclusive("placement", "placement-file")
+
+	// path is added by now, its init runs first
+	for _, layoutFlag := range []string{"city-config", "matrix", "cities", "snapshot"} {
+		pathCmd.MarkFlagsMutuallyExclusive("layout", layoutFlag)
+	}
 }
@@ -0,0 +1,152 @@
+package cmd
+
+import (
+	"log"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/jattento/alien-invasion-simulator/internal/simulation"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// _scenarioGroups are flags that describe the same thing, a scenario doesn't set any flag of a group
+// if one of them is set explicitly
+var _scenarioGroups = [][]string{
+	{"city-config", "format", "fix-layout", "matrix", "cities"},
+	{"placement", "placement-file"},
+}
+
+var runCmd = &cobra.Command{
+	Use:   "run <scenario-file>",
+	Short: "Run the invasion described by a scenario file",
+	Long: "Run the invasion described by a YAML or JSON scenario file with the map, the aliens, the rules and " +
+		"when it ends, so the same experiment can be versioned and shared. Flags set explicitly override the scenario.",
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		applyScenario(cmd.Flags(), readScenario(args[0]), filepath.Dir(args[0]))
+
+		os.Exit(run(newInvasion(cmd, *_cityConfig)))
+	},
+}
+
+// readScenario returns the scenario of the file at path.
+func readScenario(path string) simulation.Scenario {
+	file, err := os.Open(path)
+	if err != nil {
+		log.Fatal("failed opening scenario: ", err.Error())
+	}
+
+	defer func() { _ = file.Close() }()
+
+	scenario, err := simulation.ReadScenario(file)
+	if err != nil {
+		log.Fatal("failed reading scenario: ", err.Error())
+	}
+
+	return scenario
+}
+
+// applyScenario sets the flags of the scenario that weren't set explicitly. A map or placement set by flag
+// replaces the whole one of the scenario, so they are never mixed.
+func applyScenario(flags *pflag.FlagSet, scenario simulation.Scenario, dir string) {
+	skipped := make(map[string]bool)
+	for _, group := range _scenarioGroups {
+		for _, name := range group {
+			if !flags.Changed(name) {
+				continue
+			}
+
+			for _, groupName := range group {
+				skipped[groupName] = true
+			}
+		}
+	}
+
+	for name, values := range scenarioFlags(scenario, dir) {
+		if flags.Changed(name) || skipped[name] {
+			continue
+		}
+
+		for _, value := range values {
+			if err := flags.Set(name, value); err != nil {
+				log.Fatalf("invalid scenario %s: %s", name, err.Error())
+			}
+		}
+	}
+}
+
+// scenarioFlags returns the values of the flags set by the scenario, its paths are relative to dir.
+func scenarioFlags(scenario simulation.Scenario, dir string) map[string][]string {
+	flags := make(map[string][]string)
+
+	set := func(name, value string) {
+		if value != "" {
+			flags[name] = append(flags[name], value)
+		}
+	}
+
+	setInt := func(name string, value *int) {
+		if value != nil {
+			set(name, strconv.Itoa(*value))
+		}
+	}
+
+	setBool := func(name string, value bool) {
+		if value {
+			set(name, "true")
+		}
+	}
+
+	setPath := func(name, path string) {
+		if path != "" && !filepath.IsAbs(path) {
+			path = filepath.Join(dir, path)
+		}
+
+		set(name, path)
+	}
+
+	if scenario.Seed != nil {
+		set("seed", strconv.FormatInt(*scenario.Seed, 10))
+	}
+
+	setPath("city-config", scenario.Map.File)
+	set("format", scenario.Map.Format)
+	setBool("fix-layout", scenario.Map.Fix)
+	setInt("matrix", scenario.Map.Matrix)
+	setInt("cities", scenario.Map.Cities)
+
+	setInt("aliens", scenario.Aliens.Count)
+	set("placement", scenario.Aliens.Placement)
+	setPath("placement-file", scenario.Aliens.PlacementFile)
+	set("species-mix", scenario.Aliens.SpeciesMix)
+	setPath("species", scenario.Aliens.SpeciesFile)
+
+	for _, wave := range scenario.Waves {
+		set("wave", wave)
+	}
+
+	set("movement", scenario.Rules.Movement)
+	set("battle", scenario.Rules.Battle)
+	setBool("collisions", scenario.Rules.Collisions)
+
+	setPath("defenders", scenario.Defenders.File)
+	setInt("garrisons", scenario.Defenders.Garrisons)
+	setInt("garrison-strength", scenario.Defenders.Strength)
+	set("defender-movement", scenario.Defenders.Movement)
+
+	setInt("days", scenario.Days)
+
+	for _, condition := range scenario.Stop {
+		set("stop", condition)
+	}
+
+	return flags
+}
+
+func init() {
+	addRunFlags(runCmd.Flags())
+
+	rootCmd.AddCommand(runCmd)
+}
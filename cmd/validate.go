@@ -0,0 +1,56 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/jattento/alien-invasion-simulator/internal/platform/system"
+	"github.com/jattento/alien-invasion-simulator/internal/simulation"
+	"github.com/spf13/cobra"
+)
+
+var validateCmd = &cobra.Command{
+	Use:   "validate <city-config>",
+	Short: "Check a city config file and print every problem found",
+	Long: "Check a city config file and print every problem found with its line and column, " +
+		"the exit code is 1 if the file is not valid.",
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		path := args[0]
+
+		loaded, err := fileManager(*_format).Load(path)
+		if err == nil {
+			loaded = simulation.SplitCityAttributes(loaded)
+			err = simulation.ValidateCityFile(loaded, layoutDirections())
+		}
+
+		var (
+			lineErrors  system.LineErrors
+			layoutError *simulation.LayoutError
+		)
+
+		switch {
+		case err == nil:
+			fmt.Printf("%s: valid city layout with %d cities\n", path, len(loaded.Records))
+			return
+		case errors.As(err, &lineErrors):
+			for _, lineErr := range lineErrors {
+				fmt.Printf("%s:%d:%d: %s\n", path, lineErr.Line, lineErr.Column, lineErr.Err.Error())
+			}
+		case errors.As(err, &layoutError):
+			for _, problem := range layoutError.Problems {
+				fmt.Printf("%s:%d:%d: %s %s: %s\n", path, problem.Line, problem.Column, problem.City, problem.Direction, problem.Reason)
+			}
+		default:
+			log.Fatal("failed loading city config: ", err.Error())
+		}
+
+		os.Exit(1)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(validateCmd)
+}
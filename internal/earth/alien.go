@@ -6,35 +6,42 @@ import (
 	"math/rand"
 )
 
+var (
+	_alienPrefixes = []string{"Zorg", "Vort", "Gork", "Gorbl", "Borg", "Krel", "Mort", "Snag", "Thrag", "Zug"}
+	_alienSuffixes = []string{"on", "ax", "ik", "ar", "or", "ul", "ith", "ol", "arx", "ath"}
+)
+
 // Generates a slice with `amount` random alien names.
 // If a name is repeated, it appends a roman numeral counter to the end.
 func randomAlienNames(amount int, randomizer *rand.Rand) []string {
-	prefixes := []string{"Zorg", "Vort", "Gork", "Gorbl", "Borg", "Krel", "Mort", "Snag", "Thrag", "Zug"}
-	suffixes := []string{"on", "ax", "ik", "ar", "or", "ul", "ith", "ol", "arx", "ath"}
-
-	// A map to keep track of the number of occurrences of each name
-	nameCounts := make(map[string]int)
+	return nextAlienNames(make(map[string]int), nil, amount, randomizer)
+}
 
+// nextAlienNames works as randomAlienNames but keeps counting the names in nameCounts,
+// so the aliens that land later never share a name with the previous ones. Names in taken are skipped.
+func nextAlienNames(nameCounts map[string]int, taken map[string]*Alien, amount int, randomizer *rand.Rand) []string {
 	// A slice to store the generated names
 	names := make([]string, amount)
 
 	for i := 0; i < amount; i++ {
 		name := fmt.Sprintf("%s %s",
-			prefixes[randomizer.Intn(len(prefixes))],
-			suffixes[randomizer.Intn(len(suffixes))],
+			_alienPrefixes[randomizer.Intn(len(_alienPrefixes))],
+			_alienSuffixes[randomizer.Intn(len(_alienSuffixes))],
 		)
 
-		count, alreadyTaken := nameCounts[name]
-		if alreadyTaken {
-			// If the name has already been generated, append a roman numeral counter
+		// If the name has already been generated, append a roman numeral counter
+		count := nameCounts[name] + 1
+		names[i] = name
+		if count > 1 {
+			names[i] = fmt.Sprintf("%s %s", name, numeric.ToRomanSystem(count))
+		}
+
+		for taken[names[i]] != nil {
 			count++
-			nameCounts[name] = count
 			names[i] = fmt.Sprintf("%s %s", name, numeric.ToRomanSystem(count))
-			continue
 		}
 
-		nameCounts[name] = 1
-		names[i] = name
+		nameCounts[name] = count
 	}
 
 	return names
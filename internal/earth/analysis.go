@@ -0,0 +1,96 @@
+package earth
+
+import "github.com/jattento/alien-invasion-simulator/internal/platform/datastructure"
+
+// Analysis describes the shape of the roads between the standing cities.
+type Analysis struct {
+	Cities int
+
+	// Pairs of standing cities joined by a road in any direction, a road and its way back count once
+	Roads int
+
+	Components int
+
+	// Independent cycles of roads, zero when the cities are joined like the branches of a tree
+	Cycles int
+
+	// Cities that would split their component if they were destroyed, sorted by name
+	CriticalCities []string
+
+	// Roads that would split their component if they were cut, along with their way back.
+	// Each one is given from the city with the lowest name when it goes that way.
+	CriticalRoads []Road
+
+	// Roads leaving a city:Amount of cities with that many roads to standing cities
+	Degrees map[int]int
+}
+
+// Analyze returns the analysis of the standing cities. It takes linear time in the amount of cities and roads.
+func (planet *Planet) Analyze() Analysis {
+	analysis := Analysis{
+		Components:     planet.graph.ComponentCount(),
+		CriticalCities: make([]string, 0),
+		CriticalRoads:  make([]Road, 0),
+		Degrees:        make(map[int]int),
+	}
+
+	for _, city := range planet.graph.Vertices() {
+		if !city.Enabled() {
+			continue
+		}
+
+		analysis.Cities++
+		analysis.Degrees[len(city.AllEdges())]++
+
+		for _, adjacent := range city.Edges() {
+			// Roads are counted from the city with the lowest name, or from the only one they leave
+			if adjacent.Enabled() && adjacent != city &&
+				(city.Id < adjacent.Id || !leadsTo(adjacent, city)) {
+				analysis.Roads++
+			}
+		}
+	}
+
+	analysis.Cycles = analysis.Roads - analysis.Cities + analysis.Components
+
+	// The graph holds the cities sorted by name
+	for _, city := range planet.graph.ArticulationPoints() {
+		analysis.CriticalCities = append(analysis.CriticalCities, city.Id)
+	}
+
+	for _, edge := range planet.graph.Bridges() {
+		analysis.CriticalRoads = append(analysis.CriticalRoads, Road{From: edge.From, To: edge.To, Direction: edge.Id})
+	}
+
+	return analysis
+}
+
+// Diameter returns the most roads that have to be taken to go from a standing city to another one it can reach.
+// It goes through the whole planet from every city, so it takes quadratic time in the amount of cities.
+func (planet *Planet) Diameter() int {
+	diameter := 0
+	for _, city := range planet.graph.Vertices() {
+		if !city.Enabled() {
+			continue
+		}
+
+		for _, roads := range planet.graph.Distances([]*datastructure.Vertex{city}) {
+			if roads > diameter {
+				diameter = roads
+			}
+		}
+	}
+
+	return diameter
+}
+
+// leadsTo returns whether there is a road from one city to another.
+func leadsTo(from, to *datastructure.Vertex) bool {
+	for _, adjacent := range from.Edges() {
+		if adjacent == to {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,74 @@
+package earth
+
+import (
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+func TestPlanet_Analyze(t *testing.T) {
+	planet := line(t)
+
+	expected := Analysis{
+		Cities:         4,
+		Roads:          3,
+		Components:     1,
+		CriticalCities: []string{"B", "C"},
+		CriticalRoads:  []Road{{From: "A", To: "B", Direction: East}, {From: "B", To: "C", Direction: East}, {From: "C", To: "D", Direction: East}},
+		Degrees:        map[int]int{1: 2, 2: 2},
+	}
+	if analysis := planet.Analyze(); !reflect.DeepEqual(analysis, expected) {
+		t.Errorf("Analyze() = %+v, expected %+v", analysis, expected)
+	}
+
+	// Roads to destroyed cities don't count anymore
+	planet.graph.GetVertex("B").Disable()
+
+	expected = Analysis{
+		Cities:         3,
+		Roads:          1,
+		Components:     2,
+		CriticalCities: []string{},
+		CriticalRoads:  []Road{{From: "C", To: "D", Direction: East}},
+		Degrees:        map[int]int{0: 1, 1: 2},
+	}
+	if analysis := planet.Analyze(); !reflect.DeepEqual(analysis, expected) {
+		t.Errorf("Analyze() = %+v, expected %+v", analysis, expected)
+	}
+}
+
+func TestPlanet_Analyze_Grid(t *testing.T) {
+	planet, err := New(gridLayout(3), 0, rand.New(rand.NewSource(0)))
+	if err != nil {
+		t.Fatalf("error while creating the planet: %v", err)
+	}
+
+	expected := Analysis{
+		Cities:         9,
+		Roads:          12,
+		Components:     1,
+		Cycles:         4,
+		CriticalCities: []string{},
+		CriticalRoads:  []Road{},
+		Degrees:        map[int]int{2: 4, 3: 4, 4: 1},
+	}
+	if analysis := planet.Analyze(); !reflect.DeepEqual(analysis, expected) {
+		t.Errorf("Analyze() = %+v, expected %+v", analysis, expected)
+	}
+
+	if diameter := planet.Diameter(); diameter != 4 {
+		t.Errorf("Diameter() = %d, expected 4", diameter)
+	}
+}
+
+func TestPlanet_Diameter(t *testing.T) {
+	planet := line(t)
+	if diameter := planet.Diameter(); diameter != 3 {
+		t.Errorf("Diameter() = %d, expected 3", diameter)
+	}
+
+	planet.graph.GetVertex("C").Disable()
+	if diameter := planet.Diameter(); diameter != 1 {
+		t.Errorf("Diameter() = %d, expected 1", diameter)
+	}
+}
@@ -0,0 +1,159 @@
+package earth
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"strconv"
+	"strings"
+
+	"github.com/jattento/alien-invasion-simulator/internal/platform/datastructure"
+)
+
+// BattleContext is everything a BattleResolver knows when two or more aliens meet at a city.
+type BattleContext struct {
+	// City is nil for the battles fought on a road, see WithEnRouteCollisions
+	City *datastructure.Vertex
+
+	// Aliens are the ones fighting, at least two
+	Aliens []*Alien
+
+	Randomizer *rand.Rand
+}
+
+// BattleOutcome Winner is the only alien surviving the battle, empty if every alien died.
+type BattleOutcome struct {
+	Winner        string
+	CityDestroyed bool
+}
+
+// BattleResolver decides the outcome of every battle. Resolvers must not keep state between calls,
+// snapshots only save their spec so a resumed invasion would lose it.
+type BattleResolver interface {
+	Resolve(ctx BattleContext) BattleOutcome
+
+	// String returns the spec that ParseBattle turns into this resolver.
+	String() string
+}
+
+var ErrUnknownBattle = errors.New("unknown battle")
+
+// Defaults used by ParseBattle when the spec doesn't have a parameter.
+const (
+	_defaultThreshold          = 3
+	_defaultDestroyProbability = 0.5
+)
+
+// ParseBattle returns the resolver described by spec, which is its name optionally followed by a parameter:
+// annihilation, strength, threshold[:aliens] or chance[:destroy probability]. An empty spec is annihilation.
+func ParseBattle(spec string) (BattleResolver, error) {
+	name, parameter, hasParameter := strings.Cut(spec, ":")
+
+	switch name {
+	case "", "annihilation":
+		return AnnihilationBattle{}, nil
+	case "strength":
+		return StrengthBattle{}, nil
+	case "threshold":
+		if !hasParameter {
+			return ThresholdBattle{Threshold: _defaultThreshold}, nil
+		}
+
+		threshold, err := strconv.Atoi(parameter)
+		if err != nil || threshold < 2 {
+			return nil, fmt.Errorf("%w: %q parameter must be an amount of aliens of at least 2", ErrUnknownBattle, spec)
+		}
+
+		return ThresholdBattle{Threshold: threshold}, nil
+	case "chance":
+		if !hasParameter {
+			return ChanceBattle{DestroyProbability: _defaultDestroyProbability}, nil
+		}
+
+		probability, err := strconv.ParseFloat(parameter, 64)
+		if err != nil || probability < 0 || probability > 1 {
+			return nil, fmt.Errorf("%w: %q parameter must be a probability between 0 and 1", ErrUnknownBattle, spec)
+		}
+
+		return ChanceBattle{DestroyProbability: probability}, nil
+	default:
+		return nil, fmt.Errorf("%w: %q, must be annihilation, strength, threshold or chance", ErrUnknownBattle, spec)
+	}
+}
+
+// AnnihilationBattle kills every alien and destroys the city.
+type AnnihilationBattle struct{}
+
+func (AnnihilationBattle) Resolve(BattleContext) BattleOutcome {
+	return BattleOutcome{CityDestroyed: true}
+}
+
+func (AnnihilationBattle) String() string {
+	return "annihilation"
+}
+
+// StrengthBattle is won by the strongest alien, ties are solved randomly. The city is destroyed.
+type StrengthBattle struct{}
+
+func (StrengthBattle) Resolve(ctx BattleContext) BattleOutcome {
+	strongest := make([]*Alien, 0, 1)
+	for _, alien := range ctx.Aliens {
+		switch {
+		case len(strongest) == 0 || alien.strength() > strongest[0].strength():
+			strongest = append(strongest[:0], alien)
+		case alien.strength() == strongest[0].strength():
+			strongest = append(strongest, alien)
+		}
+	}
+
+	return BattleOutcome{Winner: strongest[ctx.Randomizer.Intn(len(strongest))].Name, CityDestroyed: true}
+}
+
+func (StrengthBattle) String() string {
+	return "strength"
+}
+
+// ThresholdBattle only destroys the city when at least Threshold aliens meet,
+// smaller battles kill every alien but leave the city standing.
+type ThresholdBattle struct {
+	Threshold int
+}
+
+func (battle ThresholdBattle) Resolve(ctx BattleContext) BattleOutcome {
+	return BattleOutcome{CityDestroyed: len(ctx.Aliens) >= battle.Threshold}
+}
+
+func (battle ThresholdBattle) String() string {
+	return "threshold:" + strconv.Itoa(battle.Threshold)
+}
+
+// ChanceBattle is won by a random alien, the stronger the alien the more likely it wins.
+// The city is destroyed with DestroyProbability.
+type ChanceBattle struct {
+	DestroyProbability float64
+}
+
+func (battle ChanceBattle) Resolve(ctx BattleContext) BattleOutcome {
+	totalStrength := 0
+	for _, alien := range ctx.Aliens {
+		totalStrength += alien.strength()
+	}
+
+	pick := ctx.Randomizer.Intn(totalStrength)
+	winner := ctx.Aliens[0]
+
+	for _, alien := range ctx.Aliens {
+		if pick < alien.strength() {
+			winner = alien
+			break
+		}
+
+		pick -= alien.strength()
+	}
+
+	return BattleOutcome{Winner: winner.Name, CityDestroyed: ctx.Randomizer.Float64() < battle.DestroyProbability}
+}
+
+func (battle ChanceBattle) String() string {
+	return "chance:" + strconv.FormatFloat(battle.DestroyProbability, 'g', -1, 64)
+}
@@ -0,0 +1,122 @@
+package earth
+
+import (
+	"errors"
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+func TestParseBattle(t *testing.T) {
+	for spec, expected := range map[string]BattleResolver{
+		"":             AnnihilationBattle{},
+		"annihilation": AnnihilationBattle{},
+		"strength":     StrengthBattle{},
+		"threshold":    ThresholdBattle{Threshold: 3},
+		"threshold:5":  ThresholdBattle{Threshold: 5},
+		"chance":       ChanceBattle{DestroyProbability: 0.5},
+		"chance:0.1":   ChanceBattle{DestroyProbability: 0.1},
+	} {
+		battle, err := ParseBattle(spec)
+		if err != nil {
+			t.Fatalf("ParseBattle(%q) error: %v", spec, err)
+		}
+
+		if battle != expected {
+			t.Errorf("ParseBattle(%q) = %v, expected %v", spec, battle, expected)
+		}
+
+		// String must be parsed back into the same resolver, since it is used to save it
+		if parsed, _ := ParseBattle(battle.String()); parsed != battle {
+			t.Errorf("ParseBattle(%q) = %v, expected %v", battle.String(), parsed, battle)
+		}
+	}
+
+	for _, spec := range []string{"duel", "threshold:1", "threshold:many", "chance:2"} {
+		if _, err := ParseBattle(spec); !errors.Is(err, ErrUnknownBattle) {
+			t.Errorf("ParseBattle(%q) error = %v, expected %v", spec, err, ErrUnknownBattle)
+		}
+	}
+}
+
+func TestBattleResolvers(t *testing.T) {
+	weak := &Alien{Name: "weak", Species: DefaultSpecies}
+	strong := &Alien{Name: "strong", Species: Species{Name: "brute", Strength: 3}}
+	third := &Alien{Name: "third", Species: DefaultSpecies}
+
+	ctx := func(aliens ...*Alien) BattleContext {
+		return BattleContext{Aliens: aliens, Randomizer: rand.New(rand.NewSource(0))}
+	}
+
+	testCases := []struct {
+		name     string
+		battle   BattleResolver
+		ctx      BattleContext
+		expected BattleOutcome
+	}{
+		{"annihilation", AnnihilationBattle{}, ctx(weak, strong), BattleOutcome{CityDestroyed: true}},
+		{"strongest wins", StrengthBattle{}, ctx(weak, strong, third), BattleOutcome{Winner: "strong", CityDestroyed: true}},
+		{"below threshold", ThresholdBattle{Threshold: 3}, ctx(weak, strong), BattleOutcome{}},
+		{"at threshold", ThresholdBattle{Threshold: 3}, ctx(weak, strong, third), BattleOutcome{CityDestroyed: true}},
+		{"chance never destroys", ChanceBattle{DestroyProbability: 0}, ctx(weak, strong), BattleOutcome{Winner: "strong"}},
+	}
+
+	for _, tc := range testCases {
+		if outcome := tc.battle.Resolve(tc.ctx); outcome != tc.expected {
+			t.Errorf("%s: Resolve() = %+v, expected %+v", tc.name, outcome, tc.expected)
+		}
+	}
+
+	// Ties are solved randomly, but one of the tied aliens always wins
+	for seed := int64(0); seed < 10; seed++ {
+		outcome := StrengthBattle{}.Resolve(BattleContext{Aliens: []*Alien{weak, third}, Randomizer: rand.New(rand.NewSource(seed))})
+		if outcome.Winner != "weak" && outcome.Winner != "third" {
+			t.Errorf("StrengthBattle tie winner = %q, expected weak or third", outcome.Winner)
+		}
+	}
+
+	// The stronger alien wins three times as often
+	wins := make(map[string]int)
+	randomizer := rand.New(rand.NewSource(0))
+	for i := 0; i < 4000; i++ {
+		wins[ChanceBattle{}.Resolve(BattleContext{Aliens: []*Alien{weak, strong}, Randomizer: randomizer}).Winner]++
+	}
+
+	if wins["strong"] < 2800 || wins["strong"] > 3200 {
+		t.Errorf("ChanceBattle strong alien won %d of 4000 battles, expected about 3000", wins["strong"])
+	}
+}
+
+func TestPlanet_NextDay_Battle(t *testing.T) {
+	brute := Species{Name: "brute", Speed: 1, Strength: 3}
+	planet, err := New(map[string]map[Direction]string{"A": {}}, 3, rand.New(rand.NewSource(0)),
+		WithBattle(StrengthBattle{}), WithSpeciesMix([]SpeciesShare{{Species: DefaultSpecies, Weight: 2}, {Species: brute, Weight: 1}}))
+	if err != nil {
+		t.Fatalf("error while creating the planet: %v", err)
+	}
+
+	var winner string
+	for name, alien := range planet.Aliens {
+		if alien.Species == brute {
+			winner = name
+		}
+	}
+
+	report := planet.NextDay()
+	if len(report.Battles) != 1 {
+		t.Fatalf("NextDay() battles = %v, expected a single one", report.Battles)
+	}
+
+	battle := report.Battles[0]
+	if battle.Winner != winner || !battle.CityDestroyed || len(battle.Casualties) != 2 {
+		t.Errorf("NextDay() battle = %+v, expected %s to win over 2 casualties destroying the city", battle, winner)
+	}
+
+	if expected := []string{winner}; !reflect.DeepEqual(planet.alienNames(), expected) {
+		t.Errorf("alive aliens = %v, expected %v", planet.alienNames(), expected)
+	}
+
+	if !planet.CityDestroyed("A") {
+		t.Errorf("city A should be destroyed")
+	}
+}
@@ -0,0 +1,67 @@
+package earth
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/jattento/alien-invasion-simulator/internal/platform/datastructure"
+)
+
+// Terrain changes how the aliens move through a city.
+type Terrain string
+
+const (
+	// TerrainPlains doesn't change anything, it is the terrain of the cities without one.
+	TerrainPlains Terrain = "plains"
+
+	// TerrainMountain ends the day of the aliens that reach the city, whatever their speed.
+	TerrainMountain Terrain = "mountain"
+
+	// TerrainSwamp lets the aliens leaving the city take a single road that day, whatever their speed.
+	TerrainSwamp Terrain = "swamp"
+)
+
+var ErrUnknownTerrain = errors.New("unknown terrain")
+
+// ParseTerrain returns the terrain with the given name, an empty name is TerrainPlains.
+func ParseTerrain(name string) (Terrain, error) {
+	switch terrain := Terrain(name); terrain {
+	case "":
+		return TerrainPlains, nil
+	case TerrainPlains, TerrainMountain, TerrainSwamp:
+		return terrain, nil
+	default:
+		return "", fmt.Errorf("%w: %q, must be plains, mountain or swamp", ErrUnknownTerrain, name)
+	}
+}
+
+// CityAttributes are the optional features of a city, the zero value is an empty city of plains.
+type CityAttributes struct {
+	// Population is the amount of humans killed when the city is destroyed
+	Population int
+
+	// Defense is the total alien strength the city withstands, battles of weaker aliens don't destroy it
+	Defense int
+
+	Terrain Terrain
+}
+
+// attributes returns the attributes of the city, the zero value if it has none.
+func (planet *Planet) attributes(city *datastructure.Vertex) CityAttributes {
+	return planet.cityAttributes[city.Id]
+}
+
+// withstands reports whether the defenses of the city hold against the aliens fighting at it.
+func (planet *Planet) withstands(city *datastructure.Vertex, aliens []*Alien) bool {
+	defense := planet.attributes(city).Defense
+	if defense <= 0 {
+		return false
+	}
+
+	strength := 0
+	for _, alien := range aliens {
+		strength += alien.strength()
+	}
+
+	return strength <= defense
+}
@@ -0,0 +1,110 @@
+package earth
+
+import (
+	"errors"
+	"math/rand"
+	"testing"
+)
+
+// attributed works as placed but with the given attributes, which go through a snapshot since Restore ignores
+// WithCityAttributes.
+func attributed(t *testing.T, aliens map[string]string, species map[string]SpeciesSnapshot, attributes map[string]CityAttributes,
+	opts ...Option) *Planet {
+	snapshot := placed(t, aliens, species).Snapshot()
+	for i, city := range snapshot.Cities {
+		snapshot.Cities[i].Population = attributes[city.Name].Population
+		snapshot.Cities[i].Defense = attributes[city.Name].Defense
+		snapshot.Cities[i].Terrain = attributes[city.Name].Terrain
+	}
+
+	planet, err := Restore(snapshot, rand.New(rand.NewSource(0)), opts...)
+	if err != nil {
+		t.Fatalf("error while restoring the planet: %v", err)
+	}
+
+	return planet
+}
+
+func TestParseTerrain(t *testing.T) {
+	for name, expected := range map[string]Terrain{"": TerrainPlains, "plains": TerrainPlains, "mountain": TerrainMountain, "swamp": TerrainSwamp} {
+		terrain, err := ParseTerrain(name)
+		if err != nil || terrain != expected {
+			t.Errorf("ParseTerrain(%q) = %q, %v, expected %q", name, terrain, err, expected)
+		}
+	}
+
+	if _, err := ParseTerrain("desert"); !errors.Is(err, ErrUnknownTerrain) {
+		t.Errorf("ParseTerrain(desert) error = %v, expected ErrUnknownTerrain", err)
+	}
+}
+
+func TestPlanet_NextDay_Terrain(t *testing.T) {
+	// fast hunts the target at D, taking up to 3 roads a day from A
+	species := map[string]SpeciesSnapshot{
+		"fast":   {Movement: "hunter", Speed: 3, Strength: 1},
+		"target": {Movement: "hold", Speed: 1, Strength: 1},
+	}
+	aliens := map[string]string{"fast": "A", "target": "D"}
+
+	tests := map[string]struct {
+		attributes map[string]CityAttributes
+		expected   string
+	}{
+		"plains":   {attributes: nil, expected: "D"},
+		"mountain": {attributes: map[string]CityAttributes{"C": {Terrain: TerrainMountain}}, expected: "C"},
+		"swamp":    {attributes: map[string]CityAttributes{"A": {Terrain: TerrainSwamp}}, expected: "B"},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			planet := attributed(t, aliens, species, test.attributes)
+			planet.NextDay()
+
+			// At D fast fights the target and both die
+			alien, alive := planet.Aliens["fast"]
+			if test.expected == "D" {
+				if alive {
+					t.Errorf("fast ended the day at %q, expected it to die at D", alien.City.Id)
+				}
+
+				return
+			}
+
+			if !alive || alien.City.Id != test.expected {
+				t.Errorf("fast ended the day at %+v, expected %q", alien, test.expected)
+			}
+		})
+	}
+}
+
+func TestPlanet_NextDay_CityAttributes(t *testing.T) {
+	species := map[string]SpeciesSnapshot{
+		"x": {Movement: "hold", Speed: 1, Strength: 1},
+		"y": {Movement: "hold", Speed: 1, Strength: 2},
+	}
+	aliens := map[string]string{"x": "B", "y": "B"}
+
+	// The city withstands the 3 strength of both aliens
+	planet := attributed(t, aliens, species, map[string]CityAttributes{"B": {Population: 100, Defense: 3}})
+	report := planet.NextDay()
+
+	if len(report.Battles) != 1 || report.Battles[0].CityDestroyed || report.Battles[0].HumanCasualties != 0 {
+		t.Errorf("NextDay() battles = %+v, expected B to withstand the battle", report.Battles)
+	}
+
+	if planet.CityDestroyed("B") || len(planet.Aliens) != 0 {
+		t.Errorf("B must stand and both aliens must die, got destroyed %v and alive %v", planet.CityDestroyed("B"), planet.Aliens)
+	}
+
+	planet = attributed(t, aliens, species, map[string]CityAttributes{"B": {Population: 100, Defense: 2}})
+	report = planet.NextDay()
+
+	if len(report.Battles) != 1 || !report.Battles[0].CityDestroyed || report.Battles[0].HumanCasualties != 100 {
+		t.Errorf("NextDay() battles = %+v, expected B destroyed with 100 human casualties", report.Battles)
+	}
+
+	snapshot := planet.Snapshot()
+	if city := snapshot.Cities[1]; city.Name != "B" || city.Population != 100 || city.Defense != 2 || city.Terrain != TerrainPlains {
+		t.Errorf("Snapshot() city = %+v, expected B with its attributes", city)
+	}
+}
@@ -0,0 +1,135 @@
+package earth
+
+import "sort"
+
+// RoadBattleReport describes a battle between two aliens that crossed the same road in opposite directions,
+// From and To are the road ends as taken by the first of the InvolvedAliens.
+type RoadBattleReport struct {
+	InvolvedAliens []string
+	From           string
+	To             string
+
+	// Species of each of the InvolvedAliens, in the same order
+	Species []string
+
+	// Winner is the alien that survived the battle and kept going, empty if both died
+	Winner string
+
+	// Casualties are the InvolvedAliens that died on the road, in the same order
+	Casualties []string
+}
+
+// crossing is a pair of aliens taking the same road in opposite directions at the same time of the day.
+type crossing struct {
+	aliens [2]string
+	steps  [2]int
+
+	// start is the time of the day, from 0 to 1, in which both aliens are on the road
+	start float64
+}
+
+// collide resolves the battles of the aliens that crossed each other on a road during the day.
+// An alien taking speed roads per day takes its road i between i/speed and (i+1)/speed, so fast aliens
+// can cross several slow ones. The movements of the aliens that died are cut at the road where they died,
+// and they are removed from the planet.
+func (planet *Planet) collide(paths map[string][]Movement) []RoadBattleReport {
+	type roadStep struct {
+		alien string
+		step  int
+	}
+
+	roads := make(map[[2]string][]roadStep)
+	for _, alien := range planet.alienNames() {
+		for step, movement := range paths[alien] {
+			if !movement.Stayed {
+				road := [2]string{movement.From, movement.To}
+				roads[road] = append(roads[road], roadStep{alien: alien, step: step})
+			}
+		}
+	}
+
+	crossings := make([]crossing, 0)
+	for road, steps := range roads {
+		// Each pair of opposite directions is visited once
+		if road[0] > road[1] {
+			continue
+		}
+
+		for _, forward := range steps {
+			for _, backward := range roads[[2]string{road[1], road[0]}] {
+				forwardSpeed, backwardSpeed := planet.Aliens[forward.alien].speed(), planet.Aliens[backward.alien].speed()
+
+				// Both aliens are on the road at the same time if each one enters it before the other leaves
+				if forward.step*backwardSpeed >= (backward.step+1)*forwardSpeed ||
+					backward.step*forwardSpeed >= (forward.step+1)*backwardSpeed {
+					continue
+				}
+
+				start := float64(forward.step) / float64(forwardSpeed)
+				if backwardStart := float64(backward.step) / float64(backwardSpeed); backwardStart > start {
+					start = backwardStart
+				}
+
+				crossings = append(crossings, crossing{
+					aliens: [2]string{forward.alien, backward.alien},
+					steps:  [2]int{forward.step, backward.step},
+					start:  start,
+				})
+			}
+		}
+	}
+
+	// Crossings are resolved in the order they happen, so an alien killed on a road doesn't fight on the next ones
+	sort.Slice(crossings, func(i, j int) bool {
+		if crossings[i].start != crossings[j].start {
+			return crossings[i].start < crossings[j].start
+		}
+
+		if crossings[i].aliens[0] != crossings[j].aliens[0] {
+			return crossings[i].aliens[0] < crossings[j].aliens[0]
+		}
+
+		return crossings[i].aliens[1] < crossings[j].aliens[1]
+	})
+
+	dead := make(map[string]bool)
+	reports := make([]RoadBattleReport, 0)
+
+	for _, c := range crossings {
+		if dead[c.aliens[0]] || dead[c.aliens[1]] {
+			continue
+		}
+
+		aliens := []string{c.aliens[0], c.aliens[1]}
+		outcome := planet.battle.Resolve(BattleContext{
+			Aliens:     []*Alien{planet.Aliens[aliens[0]], planet.Aliens[aliens[1]]},
+			Randomizer: planet.randomizer,
+		})
+
+		movement := paths[aliens[0]][c.steps[0]]
+		report := RoadBattleReport{
+			InvolvedAliens: aliens,
+			From:           movement.From,
+			To:             movement.To,
+			Species:        planet.speciesOf(aliens),
+			Winner:         outcome.Winner,
+			Casualties:     make([]string, 0, 1),
+		}
+
+		for i, alien := range aliens {
+			if alien != outcome.Winner {
+				dead[alien] = true
+				paths[alien] = paths[alien][:c.steps[i]]
+				report.Casualties = append(report.Casualties, alien)
+			}
+		}
+
+		reports = append(reports, report)
+	}
+
+	for alien := range dead {
+		delete(planet.Aliens, alien)
+	}
+
+	return reports
+}
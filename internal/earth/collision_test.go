@@ -0,0 +1,94 @@
+package earth
+
+import (
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+// placed restores the planet A - B - C - D with every alien at the given city.
+func placed(t *testing.T, aliens map[string]string, species map[string]SpeciesSnapshot, opts ...Option) *Planet {
+	snapshot := Snapshot{
+		Cities: []CitySnapshot{
+			{Name: "A", Roads: map[Direction]string{East: "B"}},
+			{Name: "B", Roads: map[Direction]string{West: "A", East: "C"}},
+			{Name: "C", Roads: map[Direction]string{West: "B", East: "D"}},
+			{Name: "D", Roads: map[Direction]string{West: "C"}},
+		},
+		Aliens:       aliens,
+		AlienSpecies: make(map[string]string),
+		Species:      species,
+	}
+
+	for alien := range aliens {
+		if _, exists := species[alien]; exists {
+			snapshot.AlienSpecies[alien] = alien
+		}
+	}
+
+	planet, err := Restore(snapshot, rand.New(rand.NewSource(0)), opts...)
+	if err != nil {
+		t.Fatalf("error while restoring the planet: %v", err)
+	}
+
+	return planet
+}
+
+func TestPlanet_NextDay_EnRouteCollisions(t *testing.T) {
+	aliens := map[string]string{"x": "B", "y": "C"}
+
+	// Hunters swap cities without meeting unless they can collide on the road
+	report := placed(t, aliens, nil, WithMovement(HunterMovement{})).NextDay()
+	if len(report.RoadBattles) != 0 || len(report.Battles) != 0 {
+		t.Errorf("NextDay() without collisions = %+v, expected no battles", report)
+	}
+
+	planet := placed(t, aliens, nil, WithMovement(HunterMovement{}), WithEnRouteCollisions(true))
+	report = planet.NextDay()
+
+	expected := []RoadBattleReport{{
+		InvolvedAliens: []string{"x", "y"},
+		From:           "B",
+		To:             "C",
+		Species:        []string{DefaultSpecies.Name, DefaultSpecies.Name},
+		Casualties:     []string{"x", "y"},
+	}}
+
+	if !reflect.DeepEqual(report.RoadBattles, expected) {
+		t.Errorf("NextDay() road battles = %+v, expected %+v", report.RoadBattles, expected)
+	}
+
+	if len(report.Movements) != 0 || len(planet.Aliens) != 0 {
+		t.Errorf("aliens killed on their first road shouldn't move, got %+v and alive %v", report.Movements, planet.Aliens)
+	}
+
+	if planet.CityDestroyed("B") || planet.CityDestroyed("C") {
+		t.Errorf("road battles must not destroy cities")
+	}
+}
+
+func TestPlanet_NextDay_EnRouteCollisions_Speed(t *testing.T) {
+	species := map[string]SpeciesSnapshot{
+		"fast": {Movement: "hunter", Speed: 3, Strength: 3},
+		"slow": {Movement: "hunter", Speed: 1, Strength: 1},
+	}
+
+	// fast takes A-B, B-C and C-D during the day, while slow takes D-C the whole day,
+	// so they meet on the last road of fast
+	planet := placed(t, map[string]string{"fast": "A", "slow": "D"}, species, WithBattle(StrengthBattle{}), WithEnRouteCollisions(true))
+	report := planet.NextDay()
+
+	if len(report.RoadBattles) != 1 {
+		t.Fatalf("NextDay() road battles = %+v, expected a single one", report.RoadBattles)
+	}
+
+	battle := report.RoadBattles[0]
+	if battle.From != "C" || battle.To != "D" || battle.Winner != "fast" || !reflect.DeepEqual(battle.Casualties, []string{"slow"}) {
+		t.Errorf("NextDay() road battle = %+v, expected fast to win on the road from C to D", battle)
+	}
+
+	// The winner keeps going
+	if len(report.Movements) != 3 || planet.Aliens["fast"].City.Id != "D" {
+		t.Errorf("fast should take 3 roads and end at D, got %+v", report.Movements)
+	}
+}
@@ -0,0 +1,216 @@
+package earth
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/jattento/alien-invasion-simulator/internal/platform/datastructure"
+)
+
+// Garrison is a group of human defenders, its strength is the amount of alien strength it can still kill.
+type Garrison struct {
+	Name     string
+	City     *datastructure.Vertex
+	Strength int
+}
+
+// GarrisonPlacement is where a garrison starts, the name is generated from the city if empty.
+type GarrisonPlacement struct {
+	Name     string
+	City     string
+	Strength int
+}
+
+// ErrDuplicateGarrison is returned when two garrisons are placed with the same name.
+var ErrDuplicateGarrison = errors.New("duplicate garrison")
+
+// DefenseReport describes the fight between the garrisons of a city and the aliens that reached it.
+type DefenseReport struct {
+	City      string
+	Garrisons []string
+	Aliens    []string
+
+	// Casualties are the Aliens killed by the garrisons, in the same order
+	Casualties []string
+
+	// Fallen are the Garrisons wiped out, in the same order
+	Fallen []string
+
+	// Losses is the strength the garrisons lost
+	Losses int
+
+	// Repelled is set when every alien was killed, so the city was saved
+	Repelled bool
+}
+
+// placeGarrisons adds the garrisons at their cities, plus the random ones at cities without a garrison.
+// The named garrisons are added first so the generated names never take theirs.
+func (planet *Planet) placeGarrisons(cities []*datastructure.Vertex) error {
+	placements := make([]GarrisonPlacement, 0, len(planet.garrisonPlacements))
+	for _, placement := range planet.garrisonPlacements {
+		if placement.Name != "" {
+			placements = append(placements, placement)
+		}
+	}
+	for _, placement := range planet.garrisonPlacements {
+		if placement.Name == "" {
+			placements = append(placements, placement)
+		}
+	}
+
+	for _, placement := range placements {
+		city := planet.graph.GetVertex(placement.City)
+		if city == nil {
+			return fmt.Errorf("%w: garrison at %q", datastructure.ErrVertexNotFound, placement.City)
+		}
+
+		if planet.Garrisons[placement.Name] != nil {
+			return fmt.Errorf("%w: %q", ErrDuplicateGarrison, placement.Name)
+		}
+
+		planet.addGarrison(placement.Name, city, placement.Strength)
+	}
+
+	if planet.randomGarrisons <= 0 {
+		return nil
+	}
+
+	guarded := make(map[*datastructure.Vertex]bool, len(planet.Garrisons))
+	for _, garrison := range planet.Garrisons {
+		guarded[garrison.City] = true
+	}
+
+	free := make([]*datastructure.Vertex, 0, len(cities))
+	for _, city := range cities {
+		if !guarded[city] {
+			free = append(free, city)
+		}
+	}
+
+	for i, position := range planet.randomizer.Perm(len(free)) {
+		if i == planet.randomGarrisons {
+			break
+		}
+
+		planet.addGarrison("", free[position], planet.randomGarrisonStrength)
+	}
+
+	return nil
+}
+
+// addGarrison names unnamed garrisons after their city, numbered if the city already has one.
+func (planet *Planet) addGarrison(name string, city *datastructure.Vertex, strength int) {
+	if name == "" {
+		name = city.Id + " guard"
+		for i := 2; planet.Garrisons[name] != nil; i++ {
+			name = fmt.Sprintf("%s guard %d", city.Id, i)
+		}
+	}
+
+	planet.Garrisons[name] = &Garrison{Name: name, City: city, Strength: strength}
+}
+
+// garrisonNames returns the names of the garrisons sorted, for the same reason as alienNames.
+func (planet *Planet) garrisonNames() []string {
+	names := make([]string, 0, len(planet.Garrisons))
+	for name := range planet.Garrisons {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// moveGarrisons moves every garrison a road at most, deciding with the alien positions before anyone moved.
+func (planet *Planet) moveGarrisons(positions map[string]*datastructure.Vertex) {
+	for _, name := range planet.garrisonNames() {
+		garrison := planet.Garrisons[name]
+
+		direction := planet.defenderMovement.Move(MovementContext{
+			Alien:         name,
+			City:          garrison.City,
+			LastDirection: Stay,
+			Aliens:        positions,
+			Randomizer:    planet.randomizer,
+		})
+
+		if direction != Stay {
+			garrison.City = garrison.City.GetAdjacent(direction)
+		}
+	}
+}
+
+// defend makes the garrisons fight the aliens at their cities, the aliens killed are removed from citiesCache.
+// Garrisons fight the aliens one by one, killing the ones they have strength left for, and are wiped out
+// if any alien survives.
+func (planet *Planet) defend(citiesCache map[*datastructure.Vertex][]string) []DefenseReport {
+	garrisonsByCity := make(map[*datastructure.Vertex][]*Garrison)
+	for _, name := range planet.garrisonNames() {
+		garrison := planet.Garrisons[name]
+		garrisonsByCity[garrison.City] = append(garrisonsByCity[garrison.City], garrison)
+	}
+
+	defendedCities := make([]*datastructure.Vertex, 0)
+	for city := range garrisonsByCity {
+		if len(citiesCache[city]) > 0 {
+			defendedCities = append(defendedCities, city)
+		}
+	}
+
+	sort.Slice(defendedCities, func(i, j int) bool { return defendedCities[i].Id < defendedCities[j].Id })
+
+	reports := make([]DefenseReport, 0, len(defendedCities))
+
+	for _, city := range defendedCities {
+		garrisons, aliens := garrisonsByCity[city], citiesCache[city]
+		report := DefenseReport{City: city.Id, Aliens: aliens, Casualties: make([]string, 0), Fallen: make([]string, 0)}
+
+		strength := 0
+		for _, garrison := range garrisons {
+			report.Garrisons = append(report.Garrisons, garrison.Name)
+			strength += garrison.Strength
+		}
+
+		survivors := make([]string, 0)
+		for _, alien := range aliens {
+			if alienStrength := planet.Aliens[alien].strength(); alienStrength <= strength-report.Losses {
+				report.Casualties = append(report.Casualties, alien)
+				report.Losses += alienStrength
+				continue
+			}
+
+			survivors = append(survivors, alien)
+		}
+
+		report.Repelled = len(survivors) == 0
+		if !report.Repelled {
+			report.Losses = strength
+		}
+
+		// Losses are taken by the garrisons in name order
+		for losses, i := report.Losses, 0; i < len(garrisons); i++ {
+			taken := garrisons[i].Strength
+			if taken > losses {
+				taken = losses
+			}
+
+			garrisons[i].Strength -= taken
+			losses -= taken
+
+			if garrisons[i].Strength <= 0 {
+				report.Fallen = append(report.Fallen, garrisons[i].Name)
+				delete(planet.Garrisons, garrisons[i].Name)
+			}
+		}
+
+		for _, alien := range report.Casualties {
+			delete(planet.Aliens, alien)
+		}
+
+		citiesCache[city] = survivors
+		reports = append(reports, report)
+	}
+
+	return reports
+}
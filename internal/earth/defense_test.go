@@ -0,0 +1,168 @@
+package earth
+
+import (
+	"errors"
+	"math/rand"
+	"reflect"
+	"testing"
+
+	"github.com/jattento/alien-invasion-simulator/internal/platform/datastructure"
+)
+
+func TestPlanet_Defend(t *testing.T) {
+	brute := SpeciesSnapshot{Speed: 1, Strength: 3}
+
+	testCases := []struct {
+		name      string
+		aliens    map[string]string
+		garrisons []GarrisonSnapshot
+		expected  DefenseReport
+		remaining map[string]int
+	}{
+		{
+			name:      "repelled",
+			aliens:    map[string]string{"a1": "A", "a2": "A"},
+			garrisons: []GarrisonSnapshot{{Name: "g", City: "A", Strength: 3}},
+			expected: DefenseReport{
+				City: "A", Garrisons: []string{"g"}, Aliens: []string{"a1", "a2"},
+				Casualties: []string{"a1", "a2"}, Fallen: []string{}, Losses: 2, Repelled: true,
+			},
+			remaining: map[string]int{"g": 1},
+		},
+		{
+			name:      "repelled with every soldier",
+			aliens:    map[string]string{"a1": "A"},
+			garrisons: []GarrisonSnapshot{{Name: "g", City: "A", Strength: 1}},
+			expected: DefenseReport{
+				City: "A", Garrisons: []string{"g"}, Aliens: []string{"a1"},
+				Casualties: []string{"a1"}, Fallen: []string{"g"}, Losses: 1, Repelled: true,
+			},
+			remaining: map[string]int{},
+		},
+		{
+			name:      "fallen taking down the weak alien",
+			aliens:    map[string]string{"brute": "A", "weak": "A"},
+			garrisons: []GarrisonSnapshot{{Name: "g1", City: "A", Strength: 1}, {Name: "g2", City: "A", Strength: 1}},
+			expected: DefenseReport{
+				City: "A", Garrisons: []string{"g1", "g2"}, Aliens: []string{"brute", "weak"},
+				Casualties: []string{"weak"}, Fallen: []string{"g1", "g2"}, Losses: 2,
+			},
+			remaining: map[string]int{},
+		},
+	}
+
+	for _, tc := range testCases {
+		planet, err := Restore(Snapshot{
+			Cities:       []CitySnapshot{{Name: "A"}},
+			Aliens:       tc.aliens,
+			AlienSpecies: map[string]string{"brute": "brute"},
+			Species:      map[string]SpeciesSnapshot{"brute": brute},
+			Garrisons:    tc.garrisons,
+		}, rand.New(rand.NewSource(0)))
+		if err != nil {
+			t.Fatalf("%s: error while restoring the planet: %v", tc.name, err)
+		}
+
+		city := planet.graph.GetVertex("A")
+		cache := map[*datastructure.Vertex][]string{city: planet.alienNames()}
+
+		reports := planet.defend(cache)
+		if !reflect.DeepEqual(reports, []DefenseReport{tc.expected}) {
+			t.Errorf("%s: defend() = %+v, expected %+v", tc.name, reports, tc.expected)
+		}
+
+		remaining := make(map[string]int)
+		for name, garrison := range planet.Garrisons {
+			remaining[name] = garrison.Strength
+		}
+
+		if !reflect.DeepEqual(remaining, tc.remaining) {
+			t.Errorf("%s: remaining garrisons = %v, expected %v", tc.name, remaining, tc.remaining)
+		}
+
+		if len(cache[city])+len(tc.expected.Casualties) != len(tc.aliens) || len(planet.Aliens) != len(cache[city]) {
+			t.Errorf("%s: surviving aliens = %v, planet aliens = %v", tc.name, cache[city], planet.alienNames())
+		}
+	}
+}
+
+func TestNew_Garrisons(t *testing.T) {
+	layout := map[string]map[Direction]string{
+		"A": {East: "B"},
+		"B": {West: "A", East: "C"},
+		"C": {West: "B"},
+	}
+
+	planet, err := New(layout, 0, rand.New(rand.NewSource(0)),
+		WithGarrisons([]GarrisonPlacement{{City: "A", Strength: 2}, {Name: "militia", City: "A", Strength: 1}}),
+		WithRandomGarrisons(5, 4))
+	if err != nil {
+		t.Fatalf("error while creating the planet: %v", err)
+	}
+
+	garrisons := make(map[string]string)
+	for name, garrison := range planet.Garrisons {
+		garrisons[name] = garrison.City.Id
+	}
+
+	// Random garrisons only go to cities without one, so there is room for two of them
+	expected := map[string]string{"A guard": "A", "militia": "A", "B guard": "B", "C guard": "C"}
+	if !reflect.DeepEqual(garrisons, expected) {
+		t.Errorf("garrisons = %v, expected %v", garrisons, expected)
+	}
+
+	if planet.Garrisons["B guard"].Strength != 4 {
+		t.Errorf("random garrisons strength = %d, expected 4", planet.Garrisons["B guard"].Strength)
+	}
+
+	// A generated name never takes the one of a named garrison, whatever the order
+	planet, err = New(layout, 0, rand.New(rand.NewSource(0)),
+		WithGarrisons([]GarrisonPlacement{{City: "A", Strength: 2}, {Name: "A guard", City: "B", Strength: 1}}))
+	if err != nil {
+		t.Fatalf("error while creating the planet: %v", err)
+	}
+
+	if len(planet.Garrisons) != 2 || planet.Garrisons["A guard"].City.Id != "B" || planet.Garrisons["A guard 2"].City.Id != "A" {
+		t.Errorf("garrisons = %v, expected the one at A to be A guard 2", planet.Garrisons)
+	}
+
+	_, err = New(layout, 0, rand.New(rand.NewSource(0)),
+		WithGarrisons([]GarrisonPlacement{{Name: "militia", City: "A", Strength: 1}, {Name: "militia", City: "B", Strength: 1}}))
+	if !errors.Is(err, ErrDuplicateGarrison) {
+		t.Errorf("garrisons with the same name error = %v, expected %v", err, ErrDuplicateGarrison)
+	}
+
+	_, err = New(layout, 0, rand.New(rand.NewSource(0)), WithGarrisons([]GarrisonPlacement{{City: "Z", Strength: 1}}))
+	if !errors.Is(err, datastructure.ErrVertexNotFound) {
+		t.Errorf("garrison at an unknown city error = %v, expected %v", err, datastructure.ErrVertexNotFound)
+	}
+}
+
+func TestPlanet_NextDay_Defenders(t *testing.T) {
+	// The garrison hunts the alien holding B, reaching it on the second day
+	planet := placed(t, map[string]string{"alien": "B"}, map[string]SpeciesSnapshot{"alien": {Movement: "hold", Speed: 1, Strength: 1}},
+		WithDefenderMovement(HunterMovement{}))
+	planet.Garrisons["g"] = &Garrison{Name: "g", City: planet.graph.GetVertex("D"), Strength: 2}
+
+	if report := planet.NextDay(); len(report.Defenses) != 0 || planet.Garrisons["g"].City.Id != "C" {
+		t.Fatalf("the garrison should walk from D to C without fighting, got %+v at %s", report.Defenses, planet.Garrisons["g"].City.Id)
+	}
+
+	report := planet.NextDay()
+	if len(report.Defenses) != 1 || !report.Defenses[0].Repelled || report.Defenses[0].City != "B" {
+		t.Fatalf("the garrison should repel the alien at B, got %+v", report.Defenses)
+	}
+
+	if len(planet.Aliens) != 0 || planet.Garrisons["g"].Strength != 1 {
+		t.Errorf("the alien should be dead and the garrison should have strength 1, got %v and %d", planet.Aliens, planet.Garrisons["g"].Strength)
+	}
+
+	restored, err := Restore(planet.Snapshot(), rand.New(rand.NewSource(0)))
+	if err != nil {
+		t.Fatalf("error while restoring the planet: %v", err)
+	}
+
+	if garrison := restored.Garrisons["g"]; garrison == nil || garrison.City.Id != "B" || garrison.Strength != 1 {
+		t.Errorf("restored garrison = %+v, expected g at B with strength 1", garrison)
+	}
+}
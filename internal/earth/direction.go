@@ -0,0 +1,215 @@
+package earth
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Direction identifies the roads leaving a city, each one has a name and an inverse in the Directions of the layout.
+// The built-in ones have the same values in every layout, the free-form ones get theirs as they are registered.
+type Direction = int
+
+const (
+	North Direction = iota
+	East
+	South
+	West
+	Northeast
+	Southeast
+	Southwest
+	Northwest
+	Up
+	Down
+)
+
+// _builtinDirections is the amount of built-in directions, the free-form ones come after them.
+const _builtinDirections = Down + 1
+
+// _builtinPairs are the name and the inverse of the built-in directions, in the order of their values.
+var _builtinPairs = [_builtinDirections][2]string{
+	{"north", "south"},
+	{"east", "west"},
+	{"south", "north"},
+	{"west", "east"},
+	{"northeast", "southwest"},
+	{"southeast", "northwest"},
+	{"southwest", "northeast"},
+	{"northwest", "southeast"},
+	{"up", "down"},
+	{"down", "up"},
+}
+
+// LabelPrefix marks the roads of a city file in a direction that is only a label, its own inverse, e.g. label:ferry=Isle.
+const LabelPrefix = "label:"
+
+var (
+	ErrDirectionConflict = errors.New("direction conflicts with a registered one")
+	ErrUnknownDirection  = errors.New("unknown direction")
+)
+
+// Directions holds the name and the inverse of every direction of a city layout, a road from A to B in a direction
+// is expected to have a road from B to A in its inverse. Each layout has its own, so the values of its free-form
+// directions only depend on the order in which they were registered for it.
+// It can be read by many planets at the same time, but not while registering.
+type Directions struct {
+	// Direction:Name and Direction:Inverse
+	names    []string
+	inverses []Direction
+
+	// Name:Direction
+	values map[string]Direction
+}
+
+// NewDirections returns the built-in directions.
+func NewDirections() *Directions {
+	directions := &Directions{values: make(map[string]Direction, _builtinDirections)}
+	for _, pair := range _builtinPairs {
+		directions.add(pair[0])
+	}
+
+	for direction, pair := range _builtinPairs {
+		directions.inverses[direction] = directions.values[pair[1]]
+	}
+
+	return directions
+}
+
+// ParseDirections returns the built-in directions and the ones of spec, a comma separated list of name:inverse pairs.
+// A name without inverse is a label that is its own inverse.
+func ParseDirections(spec string) (*Directions, error) {
+	directions := NewDirections()
+	if spec == "" {
+		return directions, nil
+	}
+
+	for _, pair := range strings.Split(spec, ",") {
+		name, inverse, hasInverse := strings.Cut(strings.TrimSpace(pair), ":")
+		if !hasInverse {
+			inverse = name
+		}
+
+		if _, err := directions.Register(name, inverse); err != nil {
+			return nil, err
+		}
+	}
+
+	return directions, nil
+}
+
+// Register registers a free-form direction and its inverse, both the same name for a road that is labeled
+// the same from both ends. Registering a direction again with the same inverse returns its value,
+// with another inverse returns ErrDirectionConflict.
+func (directions *Directions) Register(name, inverse string) (Direction, error) {
+	if name == "" || inverse == "" {
+		return 0, fmt.Errorf("%w: a direction must have a name and an inverse", ErrDirectionConflict)
+	}
+
+	direction, nameKnown := directions.values[name]
+	inverseDirection, inverseKnown := directions.values[inverse]
+
+	switch {
+	case nameKnown && inverseKnown && directions.inverses[direction] == inverseDirection:
+		return direction, nil
+	case nameKnown:
+		return 0, fmt.Errorf("%w: %q is the inverse of %q", ErrDirectionConflict, directions.names[directions.inverses[direction]], name)
+	case inverseKnown:
+		return 0, fmt.Errorf("%w: %q is the inverse of %q", ErrDirectionConflict,
+			directions.names[directions.inverses[inverseDirection]], inverse)
+	}
+
+	direction = directions.add(name)
+	inverseDirection = direction
+	if inverse != name {
+		inverseDirection = directions.add(inverse)
+	}
+
+	directions.inverses[direction], directions.inverses[inverseDirection] = inverseDirection, direction
+
+	return direction, nil
+}
+
+// add appends a direction that is its own inverse until told otherwise.
+func (directions *Directions) add(name string) Direction {
+	direction := len(directions.names)
+	directions.values[name] = direction
+	directions.names = append(directions.names, name)
+	directions.inverses = append(directions.inverses, direction)
+
+	return direction
+}
+
+// Label returns the direction with the name, registering it as a label that is its own inverse if it is unknown.
+// Only the names marked with LabelPrefix can be registered, the rest return ErrUnknownDirection.
+func (directions *Directions) Label(name string) (Direction, error) {
+	if direction, known := directions.values[name]; known {
+		return direction, nil
+	}
+
+	if !IsLabel(name) {
+		return 0, fmt.Errorf("%w: %q", ErrUnknownDirection, name)
+	}
+
+	return directions.add(name), nil
+}
+
+// IsLabel returns whether the name is marked with LabelPrefix.
+func IsLabel(name string) bool {
+	return len(name) > len(LabelPrefix) && strings.HasPrefix(name, LabelPrefix)
+}
+
+// Lookup returns the direction with the name, and whether it is registered.
+func (directions *Directions) Lookup(name string) (Direction, bool) {
+	direction, known := directions.values[name]
+
+	return direction, known
+}
+
+// Known returns whether the direction is registered.
+func (directions *Directions) Known(direction Direction) bool {
+	return direction >= 0 && direction < len(directions.names)
+}
+
+// Name returns the name of the direction, empty if it isn't registered.
+func (directions *Directions) Name(direction Direction) string {
+	if !directions.Known(direction) {
+		return ""
+	}
+
+	return directions.names[direction]
+}
+
+// Inverse returns the inverse of the direction, and whether it is registered.
+func (directions *Directions) Inverse(direction Direction) (Direction, bool) {
+	if !directions.Known(direction) {
+		return 0, false
+	}
+
+	return directions.inverses[direction], true
+}
+
+// InverseName returns the name of the inverse of the direction with the name, and whether it is registered.
+// The labels are their own inverse even before they are registered.
+func (directions *Directions) InverseName(name string) (string, bool) {
+	direction, known := directions.values[name]
+	if !known {
+		return name, IsLabel(name)
+	}
+
+	return directions.names[directions.inverses[direction]], true
+}
+
+// Names returns the names of the registered directions, in the order of their values.
+func (directions *Directions) Names() []string {
+	return append([]string{}, directions.names...)
+}
+
+// BuiltinDirectionNames returns the names of the built-in directions, in the order of their values.
+func BuiltinDirectionNames() []string {
+	names := make([]string, 0, _builtinDirections)
+	for _, pair := range _builtinPairs {
+		names = append(names, pair[0])
+	}
+
+	return names
+}
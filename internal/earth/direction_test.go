@@ -0,0 +1,223 @@
+package earth
+
+import (
+	"errors"
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+func TestDirections_Name(t *testing.T) {
+	directions := NewDirections()
+
+	for direction, name := range map[Direction]string{North: "north", West: "west", Northeast: "northeast", Down: "down"} {
+		if actual := directions.Name(direction); actual != name {
+			t.Errorf("Name(%d) = %q, expected %q", direction, actual, name)
+		}
+
+		if actual, known := directions.Lookup(name); !known || actual != direction {
+			t.Errorf("Lookup(%q) = %d, %v, expected %d", name, actual, known, direction)
+		}
+	}
+
+	if name := directions.Name(Stay); name != "" {
+		t.Errorf("Name(Stay) = %q, expected no name", name)
+	}
+
+	if directions.Known(_builtinDirections) {
+		t.Errorf("Known(%d) = true without free-form directions", _builtinDirections)
+	}
+
+	expected := []string{"north", "east", "south", "west", "northeast", "southeast", "southwest", "northwest", "up", "down"}
+	if names := BuiltinDirectionNames(); !reflect.DeepEqual(names, expected) {
+		t.Errorf("BuiltinDirectionNames() = %v, expected %v", names, expected)
+	}
+}
+
+func TestDirections_Inverse(t *testing.T) {
+	directions := NewDirections()
+
+	for direction, inverse := range map[Direction]Direction{North: South, East: West, Southeast: Northwest, Up: Down, Down: Up} {
+		if actual, known := directions.Inverse(direction); !known || actual != inverse {
+			t.Errorf("Inverse(%d) = %d, %v, expected %d", direction, actual, known, inverse)
+		}
+	}
+
+	if _, known := directions.Inverse(Stay); known {
+		t.Errorf("Inverse(Stay) should be unknown")
+	}
+
+	// Labels are their own inverse, the rest of the unknown names have none
+	for name, inverse := range map[string]string{"northwest": "southeast", "label:ferry": "label:ferry"} {
+		if actual, known := directions.InverseName(name); !known || actual != inverse {
+			t.Errorf("InverseName(%q) = %q, %v, expected %q", name, actual, known, inverse)
+		}
+	}
+
+	for _, name := range []string{"ferry", "nrth", "label:"} {
+		if _, known := directions.InverseName(name); known {
+			t.Errorf("InverseName(%q) should be unknown", name)
+		}
+	}
+}
+
+func TestDirections_Register(t *testing.T) {
+	directions := NewDirections()
+
+	inside, err := directions.Register("inside", "outside")
+	if err != nil {
+		t.Fatalf("Register() failed with error %v", err)
+	}
+
+	if inside != _builtinDirections {
+		t.Errorf("Register() = %d, expected the first free-form value %d", inside, _builtinDirections)
+	}
+
+	if inverse, _ := directions.Inverse(inside); directions.Name(inverse) != "outside" {
+		t.Errorf("the inverse of %q is %q, expected %q", "inside", directions.Name(inverse), "outside")
+	}
+
+	// Test case: registering the same pair again, from any side
+	if again, err := directions.Register("inside", "outside"); err != nil || again != inside {
+		t.Errorf("Register() again = %d, %v, expected %d", again, err, inside)
+	}
+
+	if _, err := directions.Register("outside", "inside"); err != nil {
+		t.Errorf("Register() of the inverse failed with error %v", err)
+	}
+
+	for _, pair := range [][2]string{{"inside", "inside"}, {"north", "east"}, {"other", "south"}, {"", "up"}} {
+		if _, err := directions.Register(pair[0], pair[1]); !errors.Is(err, ErrDirectionConflict) {
+			t.Errorf("Register(%q, %q) should return %v, but returned %v", pair[0], pair[1], ErrDirectionConflict, err)
+		}
+	}
+
+	// Every layout has its own directions
+	if _, known := NewDirections().Lookup("inside"); known {
+		t.Errorf("the directions of a layout leaked to a new one")
+	}
+}
+
+func TestParseDirections(t *testing.T) {
+	directions, err := ParseDirections("upstream:downstream, ferry")
+	if err != nil {
+		t.Fatalf("ParseDirections() failed with error %v", err)
+	}
+
+	for name, inverse := range map[string]string{"downstream": "upstream", "ferry": "ferry"} {
+		if actual, known := directions.InverseName(name); !known || actual != inverse {
+			t.Errorf("InverseName(%q) = %q, %v, expected %q", name, actual, known, inverse)
+		}
+	}
+
+	if _, err := ParseDirections("ferry, ferry:bridge"); !errors.Is(err, ErrDirectionConflict) {
+		t.Errorf("ParseDirections() should return %v, but returned %v", ErrDirectionConflict, err)
+	}
+
+	if directions, err := ParseDirections(""); err != nil || directions.Known(_builtinDirections) {
+		t.Errorf("ParseDirections() of an empty spec = %v, %v, expected the built-in directions", directions, err)
+	}
+}
+
+func TestDirections_Label(t *testing.T) {
+	directions := NewDirections()
+
+	if direction, err := directions.Label("east"); err != nil || direction != East {
+		t.Errorf("Label(%q) = %d, %v, expected %d", "east", direction, err, East)
+	}
+
+	portal, err := directions.Label("label:portal")
+	if err != nil || directions.Name(portal) != "label:portal" || portal != _builtinDirections {
+		t.Errorf("Label(%q) = %d, %v, expected a new direction", "label:portal", portal, err)
+	}
+
+	if again, _ := directions.Label("label:portal"); again != portal {
+		t.Errorf("Label(%q) again = %d, expected %d", "label:portal", again, portal)
+	}
+
+	// Only the names marked as labels are registered
+	for _, name := range []string{"portal", "nrth", "label:"} {
+		if _, err := directions.Label(name); !errors.Is(err, ErrUnknownDirection) {
+			t.Errorf("Label(%q) should return %v, but returned %v", name, ErrUnknownDirection, err)
+		}
+	}
+
+	expected := append(BuiltinDirectionNames(), "label:portal")
+	if names := directions.Names(); !reflect.DeepEqual(names, expected) {
+		t.Errorf("Names() = %v, expected %v", names, expected)
+	}
+}
+
+func TestNew_Directions(t *testing.T) {
+	directions := NewDirections()
+	portal, _ := directions.Label("label:portal")
+
+	// Two floors joined by a staircase, with a diagonal and a portal
+	layout := map[string]map[Direction]string{
+		"A":  {Northeast: "B", Up: "A2", portal: "B2"},
+		"B":  {Southwest: "A"},
+		"A2": {Down: "A"},
+		"B2": {portal: "A"},
+	}
+
+	planet, err := New(layout, 0, rand.New(rand.NewSource(0)), WithDirections(directions))
+	if err != nil {
+		t.Fatalf("error while creating the planet: %v", err)
+	}
+
+	roads, err := planet.Route("B", "B2")
+	if err != nil {
+		t.Fatalf("Route() failed with error %v", err)
+	}
+
+	expected := []Road{{From: "B", To: "A", Direction: Southwest}, {From: "A", To: "B2", Direction: portal}}
+	if !reflect.DeepEqual(roads, expected) {
+		t.Errorf("Route() = %v, expected %v", roads, expected)
+	}
+
+	if _, err := New(layout, 0, rand.New(rand.NewSource(0))); err == nil {
+		t.Errorf("New() should fail with a direction that isn't in its directions")
+	}
+}
+
+func TestRestore_Directions(t *testing.T) {
+	directions := NewDirections()
+	inside, _ := directions.Register("inside", "outside")
+	outside, _ := directions.Lookup("outside")
+	portal, _ := directions.Label("label:portal")
+
+	planet, err := New(map[string]map[Direction]string{
+		"A": {East: "C", portal: "B", inside: "D"},
+		"B": {portal: "A"},
+		"C": {West: "A"},
+		"D": {outside: "A"},
+	}, 1, rand.New(rand.NewSource(0)), WithDirections(directions))
+	if err != nil {
+		t.Fatalf("error while creating the planet: %v", err)
+	}
+
+	snapshot := planet.Snapshot()
+	expected := []DirectionSnapshot{{Name: "inside", Inverse: "outside"}, {Name: "outside", Inverse: "inside"}, {Name: "label:portal", Inverse: "label:portal"}}
+	if !reflect.DeepEqual(snapshot.Directions, expected) {
+		t.Errorf("Snapshot().Directions = %v, expected %v", snapshot.Directions, expected)
+	}
+
+	restored, err := Restore(snapshot, rand.New(rand.NewSource(0)))
+	if err != nil {
+		t.Fatalf("error while restoring the planet: %v", err)
+	}
+
+	if name := restored.Directions().Name(portal); name != "label:portal" {
+		t.Errorf("restored direction %d = %q, expected %q", portal, name, "label:portal")
+	}
+
+	if !reflect.DeepEqual(restored.Snapshot(), snapshot) {
+		t.Errorf("restored planet snapshot = %v, expected %v", restored.Snapshot(), snapshot)
+	}
+
+	// The directions must keep their values
+	snapshot.Directions = []DirectionSnapshot{expected[0], expected[2], expected[1]}
+	if _, err := Restore(snapshot, rand.New(rand.NewSource(0))); !errors.Is(err, ErrDirectionConflict) {
+		t.Errorf("Restore() should return %v, but returned %v", ErrDirectionConflict, err)
+	}
+}
@@ -0,0 +1,150 @@
+package earth
+
+import (
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/jattento/alien-invasion-simulator/internal/platform/datastructure"
+)
+
+// _compassOffsets is the grid step taken by each road, north goes up and east goes right.
+// Up, down and the free-form directions don't place the cities they lead to.
+var _compassOffsets = map[Direction][2]int{
+	North:     {0, 1},
+	East:      {1, 0},
+	South:     {0, -1},
+	West:      {-1, 0},
+	Northeast: {1, 1},
+	Southeast: {1, -1},
+	Southwest: {-1, -1},
+	Northwest: {-1, 1},
+}
+
+// _compassPorts are the sides of a city in which each road starts and ends.
+var _compassPorts = map[Direction][2]string{
+	North:     {"n", "s"},
+	East:      {"e", "w"},
+	South:     {"s", "n"},
+	West:      {"w", "e"},
+	Northeast: {"ne", "sw"},
+	Southeast: {"se", "nw"},
+	Southwest: {"sw", "ne"},
+	Northwest: {"nw", "se"},
+}
+
+// _dotScale is the distance in inches between two neighbour cities.
+const _dotScale = 1.5
+
+// WriteDOT writes the planet as a Graphviz graph, meant to be rendered with neato since every city
+// has a fixed position following its roads. Destroyed cities are styled differently
+// and battles is City:Amount of battles fought at it, added to the labels.
+func (planet *Planet) WriteDOT(output io.Writer, battles map[string]int) error {
+	positions := compassPositions(planet.graph.Vertices())
+
+	return planet.graph.WriteDOT(output, datastructure.DOTStyle{
+		Name:  "earth",
+		Graph: datastructure.DOTAttributes{"layout": "neato"},
+		Node:  datastructure.DOTAttributes{"shape": "box", "style": "rounded"},
+		VertexAttributes: func(vertex *datastructure.Vertex) datastructure.DOTAttributes {
+			position := positions[vertex]
+			attributes := datastructure.DOTAttributes{
+				"pos": fmt.Sprintf("%g,%g!", float64(position[0])*_dotScale, float64(position[1])*_dotScale),
+			}
+
+			switch amount := battles[vertex.Id]; {
+			case amount == 1:
+				attributes["label"] = vertex.Id + "\n1 battle"
+			case amount > 1:
+				attributes["label"] = fmt.Sprintf("%s\n%d battles", vertex.Id, amount)
+			}
+
+			if !vertex.Enabled() {
+				attributes["style"] = "rounded,filled,dashed"
+				attributes["color"] = "red"
+				attributes["fillcolor"] = "mistyrose"
+			}
+
+			return attributes
+		},
+		EdgeAttributes: func(from *datastructure.Vertex, direction int, to *datastructure.Vertex) datastructure.DOTAttributes {
+			attributes := datastructure.DOTAttributes{}
+			if ports, known := _compassPorts[direction]; known {
+				attributes["tailport"], attributes["headport"] = ports[0], ports[1]
+			} else {
+				attributes["label"] = planet.directions.Name(direction)
+			}
+
+			if !from.Enabled() || !to.Enabled() {
+				attributes["style"] = "dashed"
+				attributes["color"] = "gray"
+			}
+
+			return attributes
+		},
+	})
+}
+
+// compassPositions places every city on a grid following its roads, starting from the first city of each
+// group of connected cities. Each group is placed at the right of the previous one.
+// Inconsistent layouts may place two cities at the same position.
+func compassPositions(vertices []*datastructure.Vertex) map[*datastructure.Vertex][2]int {
+	positions := make(map[*datastructure.Vertex][2]int, len(vertices))
+	offsetX := 0
+
+	for _, root := range vertices {
+		if _, placed := positions[root]; placed {
+			continue
+		}
+
+		group := map[*datastructure.Vertex][2]int{root: {0, 0}}
+		queue := []*datastructure.Vertex{root}
+
+		for len(queue) > 0 {
+			vertex := queue[0]
+			queue = queue[1:]
+
+			edges := vertex.Edges()
+			directions := make([]int, 0, len(edges))
+			for direction := range edges {
+				directions = append(directions, direction)
+			}
+			sort.Ints(directions)
+
+			for _, direction := range directions {
+				adjacent := edges[direction]
+				offset, known := _compassOffsets[direction]
+
+				_, placed := group[adjacent]
+				_, placedByOtherGroup := positions[adjacent]
+
+				if placed || placedByOtherGroup || !known {
+					continue
+				}
+
+				position := group[vertex]
+				group[adjacent] = [2]int{position[0] + offset[0], position[1] + offset[1]}
+				queue = append(queue, adjacent)
+			}
+		}
+
+		minX, maxX := 0, 0
+		for _, position := range group {
+			if position[0] < minX {
+				minX = position[0]
+			}
+
+			if position[0] > maxX {
+				maxX = position[0]
+			}
+		}
+
+		for vertex, position := range group {
+			positions[vertex] = [2]int{position[0] - minX + offsetX, position[1]}
+		}
+
+		offsetX += maxX - minX + 2
+	}
+
+	return positions
+}
@@ -0,0 +1,94 @@
+package earth
+
+import (
+	"math/rand"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestCompassPositions(t *testing.T) {
+	layout := map[string]map[Direction]string{
+		"A": {East: "B", South: "C"},
+		"B": {West: "A", South: "D"},
+		"C": {North: "A", East: "D"},
+		"D": {North: "B", West: "C"},
+		"E": {West: "F"},
+		"F": {East: "E"},
+	}
+
+	planet, err := New(layout, 0, rand.New(rand.NewSource(0)))
+	if err != nil {
+		t.Fatalf("error while creating the planet: %v", err)
+	}
+
+	positions := make(map[string][2]int)
+	for vertex, position := range compassPositions(planet.graph.Vertices()) {
+		positions[vertex.Id] = position
+	}
+
+	// E and F aren't connected to the rest, so they are placed at the right leaving a gap
+	expected := map[string][2]int{
+		"A": {0, 0}, "B": {1, 0},
+		"C": {0, -1}, "D": {1, -1},
+		"F": {3, 0}, "E": {4, 0},
+	}
+
+	if !reflect.DeepEqual(positions, expected) {
+		t.Errorf("compassPositions() = %v, expected %v", positions, expected)
+	}
+}
+
+func TestPlanet_WriteDOT(t *testing.T) {
+	planet, err := New(map[string]map[Direction]string{
+		"A": {North: "B"},
+		"B": {South: "A"},
+	}, 0, rand.New(rand.NewSource(0)))
+	if err != nil {
+		t.Fatalf("error while creating the planet: %v", err)
+	}
+
+	planet.graph.GetVertex("B").Disable()
+
+	var output strings.Builder
+	if err := planet.WriteDOT(&output, map[string]int{"B": 2}); err != nil {
+		t.Fatalf("WriteDOT() error: %v", err)
+	}
+
+	for _, expected := range []string{
+		`"A" [pos="0,0!"]`,
+		`"B" [color="red", fillcolor="mistyrose", label="B\n2 battles", pos="0,1.5!", style="rounded,filled,dashed"]`,
+		`"A" -- "B" [color="gray", headport="s", style="dashed", tailport="n"]`,
+	} {
+		if !strings.Contains(output.String(), expected) {
+			t.Errorf("WriteDOT() = %s, expected it to contain %s", output.String(), expected)
+		}
+	}
+}
+
+func TestPlanet_WriteDOT_Directions(t *testing.T) {
+	planet, err := New(map[string]map[Direction]string{
+		"A": {Northeast: "B", Up: "C"},
+		"B": {Southwest: "A"},
+		"C": {Down: "A"},
+	}, 0, rand.New(rand.NewSource(0)))
+	if err != nil {
+		t.Fatalf("error while creating the planet: %v", err)
+	}
+
+	var output strings.Builder
+	if err := planet.WriteDOT(&output, nil); err != nil {
+		t.Fatalf("WriteDOT() error: %v", err)
+	}
+
+	// Diagonals place the cities, the rest of the directions are written on the road
+	for _, expected := range []string{
+		`"B" [pos="1.5,1.5!"]`,
+		`"A" -- "B" [headport="sw", tailport="ne"]`,
+		`"A" -- "C" [label="up"]`,
+	} {
+		if !strings.Contains(output.String(), expected) {
+			t.Errorf("WriteDOT() = %s, expected it to contain %s", output.String(), expected)
+		}
+	}
+}
@@ -1,10 +1,9 @@
 package earth
 
 import (
-	"errors"
 	"fmt"
 	"math/rand"
-	"time"
+	"sort"
 
 	"github.com/jattento/alien-invasion-simulator/internal/platform/datastructure"
 )
@@ -12,107 +11,231 @@ import (
 type BattleReport struct {
 	InvolvedAliens []string
 	City           string
+
+	// Species of each of the InvolvedAliens, in the same order
+	Species []string
+
+	// Winner is the alien that survived the battle, empty if every alien died
+	Winner string
+
+	// Casualties are the InvolvedAliens that died, in the same order
+	Casualties []string
+
+	CityDestroyed bool
+
+	// HumanCasualties is the population of the city if it was destroyed
+	HumanCasualties int
+}
+
+// Movement describes a road taken by an alien during a day, aliens faster than one road per day
+// have a movement for each road they took. If the alien stayed at the same city, From and To are equal
+// and Direction is Stay.
+type Movement struct {
+	Alien     string
+	From      string
+	To        string
+	Direction Direction
+	Stayed    bool
+}
+
+// DayReport describes everything that happened in the planet during a day.
+type DayReport struct {
+	Movements   []Movement
+	Battles     []BattleReport
+	RoadBattles []RoadBattleReport
+	Defenses    []DefenseReport
+	Waves       []WaveReport
 }
 
 type Planet struct {
 	graph *datastructure.Graph
 
-	// Name:Position
-	Aliens map[string]*datastructure.Vertex
+	// directions name the roads of the graph, whose edge ids are their values
+	directions *Directions
+
+	// Name:Alien
+	Aliens map[string]*Alien
+
+	// Name:Garrison
+	Garrisons map[string]*Garrison
 
 	// This cache saves the state of the cities and Aliens at the moment the Planet is created
 	// to be used at day zero without the need to process it again
 	dayZeroCacheData map[*datastructure.Vertex][]string
 
 	randomizer *rand.Rand
-}
 
-type Direction = int
+	// placement decides where the aliens spawned by New land, restored planets already have theirs
+	placement PlacementStrategy
 
-const (
-	North Direction = iota
-	East
-	South
-	West
-)
+	// movement is used by the species without their own one
+	movement MovementStrategy
 
-// New input looks like: <Bar:1:Foo>
-// This function isn't designed to be performant but to give a nice interface,
-// and since this function is going to be called once at the beginning it is acceptable.
-func New(citiesAndAdjacent map[string]map[Direction]string, aliensAmount int) (*Planet, error) {
-	randomizer := rand.New(rand.NewSource(time.Now().UnixNano()))
+	speciesMix []SpeciesShare
+
+	battle BattleResolver
 
+	// enRouteCollisions makes the aliens crossing the same road in opposite directions fight
+	enRouteCollisions bool
+
+	// Garrisons placed by New, restored planets already have theirs
+	garrisonPlacements     []GarrisonPlacement
+	randomGarrisons        int
+	randomGarrisonStrength int
+
+	defenderMovement MovementStrategy
+
+	// City:Attributes, the cities without attributes aren't included
+	cityAttributes map[string]CityAttributes
+
+	waves []Wave
+
+	// day is the amount of days that already passed, the waves land by it
+	day int
+
+	// Name:Amount of aliens that got it, to keep the names of the aliens that land unique
+	nameCounts map[string]int
+}
+
+// New input looks like: <Bar:1:Foo>
+// Building the planet takes linear time in the amount of cities and roads, so maps of a million cities load in seconds.
+//
+// The randomizer is the only source of randomness of the planet: alien names, spawn positions
+// and movements are all derived from it, so the same seed always produces the same invasion.
+func New(citiesAndAdjacent map[string]map[Direction]string, aliensAmount int, randomizer *rand.Rand, opts ...Option) (*Planet, error) {
 	p := Planet{
-		graph:            new(datastructure.Graph),
-		Aliens:           make(map[string]*datastructure.Vertex),
+		Aliens:           make(map[string]*Alien),
+		Garrisons:        make(map[string]*Garrison),
 		randomizer:       randomizer,
 		dayZeroCacheData: make(map[*datastructure.Vertex][]string),
+		nameCounts:       make(map[string]int),
+		placement:        RandomPlacement{},
+		movement:         UniformMovement{},
+		battle:           AnnihilationBattle{},
+		defenderMovement: HoldMovement{},
+		directions:       NewDirections(),
 	}
 
-	// Since we need to make first all the AddVertex calls, we are going to save the AddEdge calls
-	addEdgeCalls := make([]func() error, 0)
+	for _, opt := range opts {
+		opt.apply(&p)
+	}
 
 	// This slice is going to be used to generate the random alien positions.
-	cities := make([]*datastructure.Vertex, 0)
+	cities, err := p.buildGraph(citiesAndAdjacent)
+	if err != nil {
+		return nil, err
+	}
 
-	// Execute AddVertex calls and prepare AddEdge ones
-	for city, adjacent := range citiesAndAdjacent {
-		cityRef, err := p.graph.AddVertex(city)
-		if err != nil {
-			return nil, err
-		}
+	for _, city := range cities {
+		p.dayZeroCacheData[city] = make([]string, 0)
+	}
 
-		cities = append(cities, cityRef)
-		p.dayZeroCacheData[cityRef] = make([]string, 0)
+	if err := p.checkPlacement(p.placement, "placement "+p.placement.String()); err != nil {
+		return nil, err
+	}
 
-		cityAddEdgeCalls, err := p.buildAddEdgeCalls(city, adjacent)
-		if err != nil {
-			return nil, err
+	alienNames := nextAlienNames(p.nameCounts, nil, aliensAmount, randomizer)
+
+	// The cities are chosen after the names, a planet without cities has no place for the aliens
+	var spawnCities []*datastructure.Vertex
+	if len(cities) > 0 {
+		spawnCities = p.placement.Place(PlacementContext{
+			Cities:     cities,
+			Graph:      p.graph,
+			Amount:     len(alienNames),
+			Aliens:     make(map[string]*datastructure.Vertex),
+			Attributes: p.cityAttributes,
+			Randomizer: randomizer,
+		})
+	}
+
+	alienNames = alienNames[:len(spawnCities)]
+
+	for i, alienName := range alienNames {
+		city := spawnCities[i]
+		p.Aliens[alienName] = &Alien{Name: alienName, Species: DefaultSpecies, City: city, lastDirection: Stay}
+		p.dayZeroCacheData[city] = append(p.dayZeroCacheData[city], alienName)
+	}
+
+	// Species are assigned after the spawn positions, so planets without a mix spawn as they always did
+	if len(p.speciesMix) > 0 {
+		for i, species := range assignSpecies(len(alienNames), p.speciesMix, randomizer) {
+			p.Aliens[alienNames[i]].Species = species
 		}
+	}
+
+	if err := p.placeGarrisons(cities); err != nil {
+		return nil, err
+	}
 
-		addEdgeCalls = append(addEdgeCalls, cityAddEdgeCalls...)
+	if err := p.checkWaves(); err != nil {
+		return nil, err
 	}
 
-	// Execute AddEdge calls
-	for _, addEdgeCall := range addEdgeCalls {
-		if err := addEdgeCall(); err != nil {
+	return &p, nil
+}
+
+// buildGraph builds the planet graph with every city and road, returning the cities sorted by name.
+func (planet *Planet) buildGraph(citiesAndAdjacent map[string]map[Direction]string) ([]*datastructure.Vertex, error) {
+	// Cities are visited in a fixed order so the same randomizer always yields the same spawn positions
+	cityNames := make([]string, 0, len(citiesAndAdjacent))
+	for city := range citiesAndAdjacent {
+		cityNames = append(cityNames, city)
+	}
+	sort.Strings(cityNames)
+
+	// Every road needs both of its cities, so all of them are added before the first road
+	planet.graph = datastructure.NewGraph(len(cityNames))
+
+	cities, err := planet.graph.AddVertices(cityNames)
+	if err != nil {
+		return nil, err
+	}
+
+	roads := make([]datastructure.Edge, 0, len(cityNames)*4)
+	for _, city := range cityNames {
+		if roads, err = appendRoads(roads, city, citiesAndAdjacent[city], planet.directions); err != nil {
 			return nil, err
 		}
 	}
 
-	// This function priority to cities which were not selected already
-	randomCitySelectorFunc := newRandomSelector(randomizer, cities)
+	if err := planet.graph.AddEdges(roads); err != nil {
+		return nil, err
+	}
 
-	alienNames := randomAlienNames(aliensAmount, randomizer)
+	return cities, nil
+}
 
-	for _, alienName := range alienNames {
-		city := randomCitySelectorFunc()
-		p.Aliens[alienName] = city
-		p.dayZeroCacheData[city] = append(p.dayZeroCacheData[city], alienName)
+// appendRoads appends the roads leaving city to roads, in any of the directions. A city with several roads
+// to the same destination keeps the one of the lowest direction.
+func appendRoads(roads []datastructure.Edge, city string, adjacentCities map[Direction]string,
+	directions *Directions) ([]datastructure.Edge, error) {
+	sorted := make([]Direction, 0, len(adjacentCities))
+	for direction := range adjacentCities {
+		sorted = append(sorted, direction)
 	}
+	sort.Ints(sorted)
 
-	return &p, nil
-}
+	first := len(roads)
 
-func (planet *Planet) buildAddEdgeCalls(city string, adjacentCities map[Direction]string) ([]func() error, error) {
-	calls := make([]func() error, 0)
+	for _, direction := range sorted {
+		adjacentCity := adjacentCities[direction]
 
-	for direction, adjacentCity := range adjacentCities {
-		if direction > 3 {
-			return nil, fmt.Errorf("invalid direction: %q -> %q -> %q", city, direction, adjacentCity)
+		if !directions.Known(direction) {
+			return nil, fmt.Errorf("invalid direction: %q -> %d -> %q", city, direction, adjacentCity)
 		}
 
-		calls = append(calls, func() error {
-			err := planet.graph.AddEdge(direction, city, adjacentCity)
-			if err != nil && errors.Is(err, datastructure.ErrEdgeDuplicated) {
-				return nil
-			}
+		duplicated := false
+		for _, road := range roads[first:] {
+			duplicated = duplicated || road.To == adjacentCity
+		}
 
-			return err
-		})
+		if !duplicated {
+			roads = append(roads, datastructure.Edge{Id: direction, From: city, To: adjacentCity})
+		}
 	}
 
-	return calls, nil
+	return roads, nil
 }
 
 func newRandomSelector[T comparable](randomizer *rand.Rand, items []T) func() T {
@@ -139,62 +262,207 @@ func newRandomSelector[T comparable](randomizer *rand.Rand, items []T) func() T
 	}
 }
 
-func (planet *Planet) NextDay() []BattleReport {
+// NextDay moves every alien and resolves the battles, the first call only resolves the battles
+// between the aliens that spawned at the same city. The aliens of the waves of the day land after
+// everyone moved, and fight at the cities where they land.
+func (planet *Planet) NextDay() DayReport {
+	defer func() { planet.day++ }()
+
 	if planet.dayZeroCacheData != nil {
-		report := planet.processDay(planet.dayZeroCacheData)
+		report := DayReport{Movements: make([]Movement, 0), RoadBattles: make([]RoadBattleReport, 0)}
+		report.Waves = planet.land(planet.dayZeroCacheData)
+		report.Defenses = planet.defend(planet.dayZeroCacheData)
+		report.Battles = planet.processDay(planet.dayZeroCacheData)
 		planet.dayZeroCacheData = nil
 
 		return report
 	}
 
 	updatedData := make(map[*datastructure.Vertex][]string)
+	movements := make([]Movement, 0, len(planet.Aliens))
+
+	// Every alien decides looking at the positions before anyone moved
+	positions := make(map[string]*datastructure.Vertex, len(planet.Aliens))
+	for alienId, alien := range planet.Aliens {
+		positions[alienId] = alien.City
+	}
+
+	alienNames := planet.alienNames()
 
 	// Alien movements...
-	for alienId, alienLocation := range planet.Aliens {
-		edges := append(alienLocation.AllEdges(), 4)
+	paths := make(map[string][]Movement, len(alienNames))
+	for _, alienId := range alienNames {
+		paths[alienId] = planet.move(planet.Aliens[alienId], positions)
+	}
+
+	roadBattles := make([]RoadBattleReport, 0)
+	if planet.enRouteCollisions {
+		roadBattles = planet.collide(paths)
+	}
+
+	planet.moveGarrisons(positions)
 
-		destinationEdge := edges[planet.randomizer.Intn(len(edges))]
+	for _, alienId := range alienNames {
+		movements = append(movements, paths[alienId]...)
 
-		// Stay at the same place
-		var newDestination *datastructure.Vertex
-		if destinationEdge == 4 {
-			newDestination = alienLocation
-		} else {
-			newDestination = alienLocation.GetAdjacent(destinationEdge)
+		if alien, alive := planet.Aliens[alienId]; alive {
+			updatedData[alien.City] = append(updatedData[alien.City], alienId)
 		}
+	}
+
+	waves := planet.land(updatedData)
+	defenses := planet.defend(updatedData)
+
+	return DayReport{
+		Movements:   movements,
+		Battles:     planet.processDay(updatedData),
+		RoadBattles: roadBattles,
+		Defenses:    defenses,
+		Waves:       waves,
+	}
+}
+
+// move takes up to the alien speed roads chosen by its movement strategy, returning a movement for each of them.
+// Aliens leaving a swamp take a single road, and reaching a mountain ends their day.
+// If the alien doesn't leave the city the only movement returned has Stayed set.
+func (planet *Planet) move(alien *Alien, positions map[string]*datastructure.Vertex) []Movement {
+	movementStrategy := alien.Species.Movement
+	if movementStrategy == nil {
+		movementStrategy = planet.movement
+	}
 
-		planet.Aliens[alienId] = newDestination
+	movements := make([]Movement, 0, 1)
 
-		updatedDataAliens, updatedDataExistForCity := updatedData[newDestination]
-		if !updatedDataExistForCity {
-			updatedData[newDestination] = make([]string, 0)
+	steps := alien.speed()
+	if planet.attributes(alien.City).Terrain == TerrainSwamp {
+		steps = 1
+	}
+
+	for step := 0; step < steps; step++ {
+		direction := movementStrategy.Move(MovementContext{
+			Alien:         alien.Name,
+			City:          alien.City,
+			LastDirection: alien.lastDirection,
+			Aliens:        positions,
+			Randomizer:    planet.randomizer,
+		})
+
+		if direction == Stay {
+			break
+		}
+
+		destination := alien.City.GetAdjacent(direction)
+		movements = append(movements, Movement{Alien: alien.Name, From: alien.City.Id, To: destination.Id, Direction: direction})
+
+		alien.City = destination
+		alien.lastDirection = direction
+
+		if planet.attributes(destination).Terrain == TerrainMountain {
+			break
 		}
+	}
 
-		updatedData[newDestination] = append(updatedDataAliens, alienId)
+	if len(movements) == 0 {
+		alien.lastDirection = Stay
+
+		return []Movement{{Alien: alien.Name, From: alien.City.Id, To: alien.City.Id, Direction: Stay, Stayed: true}}
 	}
 
-	return planet.processDay(updatedData)
+	return movements
+}
+
+// Directions returns the directions of the roads of the planet.
+func (planet *Planet) Directions() *Directions {
+	return planet.directions
+}
+
+// CityDestroyed reports whether the city exists and was destroyed.
+func (planet *Planet) CityDestroyed(city string) bool {
+	vertex := planet.graph.GetVertex(city)
+
+	return vertex != nil && !vertex.Enabled()
+}
+
+// alienNames returns the names of the alive aliens sorted, since iterating the Aliens map directly
+// would consume the randomizer in a different order on every run.
+func (planet *Planet) alienNames() []string {
+	names := make([]string, 0, len(planet.Aliens))
+	for name := range planet.Aliens {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+func (planet *Planet) speciesOf(aliens []string) []string {
+	species := make([]string, 0, len(aliens))
+	for _, alien := range aliens {
+		species = append(species, planet.Aliens[alien].Species.Name)
+	}
+
+	return species
 }
 
 // citiesCache: city:[alien1Id,alien2Id]
 func (planet *Planet) processDay(citiesCache map[*datastructure.Vertex][]string) []BattleReport {
-	destroyedCities := make(map[*datastructure.Vertex]struct{})
-	destroyedAliens := make([]string, 0)
-	reports := make([]BattleReport, 0)
-
+	battleCities := make([]*datastructure.Vertex, 0)
 	for city, aliens := range citiesCache {
 		if len(aliens) > 1 {
-			destroyedAliens = append(destroyedAliens, aliens...)
-			destroyedCities[city] = struct{}{}
-			reports = append(reports, BattleReport{City: city.Id, InvolvedAliens: aliens})
+			battleCities = append(battleCities, city)
+		}
+	}
+
+	// Battles are resolved in a fixed order so the same randomizer always yields the same outcomes
+	sort.Slice(battleCities, func(i, j int) bool { return battleCities[i].Id < battleCities[j].Id })
+
+	destroyedCities := make([]*datastructure.Vertex, 0)
+	destroyedAliens := make([]string, 0)
+	reports := make([]BattleReport, 0, len(battleCities))
+
+	for _, city := range battleCities {
+		aliens := citiesCache[city]
+
+		fighters := make([]*Alien, 0, len(aliens))
+		for _, alien := range aliens {
+			fighters = append(fighters, planet.Aliens[alien])
+		}
+
+		outcome := planet.battle.Resolve(BattleContext{City: city, Aliens: fighters, Randomizer: planet.randomizer})
+		if outcome.CityDestroyed && planet.withstands(city, fighters) {
+			outcome.CityDestroyed = false
+		}
+
+		casualties := make([]string, 0, len(aliens))
+		for _, alien := range aliens {
+			if alien != outcome.Winner {
+				casualties = append(casualties, alien)
+			}
+		}
+
+		report := BattleReport{
+			City:           city.Id,
+			InvolvedAliens: aliens,
+			Species:        planet.speciesOf(aliens),
+			Winner:         outcome.Winner,
+			Casualties:     casualties,
+			CityDestroyed:  outcome.CityDestroyed,
 		}
+
+		destroyedAliens = append(destroyedAliens, casualties...)
+		if outcome.CityDestroyed {
+			destroyedCities = append(destroyedCities, city)
+			report.HumanCasualties = planet.attributes(city).Population
+		}
+
+		reports = append(reports, report)
 	}
 
 	for _, alien := range destroyedAliens {
 		delete(planet.Aliens, alien)
 	}
 
-	for city := range destroyedCities {
+	for _, city := range destroyedCities {
 		city.Disable()
 	}
 
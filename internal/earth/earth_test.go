@@ -2,6 +2,8 @@ package earth
 
 import (
 	"math/rand"
+	"reflect"
+	"strconv"
 	"testing"
 )
 
@@ -21,13 +23,11 @@ func TestPlanet_NextDay(t *testing.T) {
 		"San Francisco": {
 			East: "Washington",
 		},
-	}, 2)
+	}, 2, rand.New(rand.NewSource(0)))
 	if err != nil {
 		t.Errorf("error while creating the planet: %v", err)
 	}
 
-	planet.randomizer = rand.New(rand.NewSource(0))
-
 	if len(planet.Aliens) != 2 {
 		t.Errorf("the planet should have 2 aliens, but has %d", len(planet.Aliens))
 	}
@@ -38,12 +38,134 @@ func TestPlanet_NextDay(t *testing.T) {
 		t.Errorf("there should still be 2 aliens, but there are %d", len(planet.Aliens))
 	}
 
+	if len(reports.Movements) != 0 {
+		t.Errorf("no alien should move at day zero, but %d moved", len(reports.Movements))
+	}
+
 	reports = planet.NextDay()
-	for _, report := range reports {
+
+	if len(reports.Movements) != 2 {
+		t.Errorf("both aliens should have a movement, but there are %d", len(reports.Movements))
+	}
+
+	for _, movement := range reports.Movements {
+		if movement.Stayed != (movement.From == movement.To) {
+			t.Errorf("alien %s movement %v is inconsistent", movement.Alien, movement)
+		}
+	}
+
+	for _, report := range reports.Battles {
 		for _, alien := range report.InvolvedAliens {
 			if planet.Aliens[alien] != nil {
-				t.Errorf("alien %s should be in a destroyed city, but it is in %s", alien, planet.Aliens[alien].Id)
+				t.Errorf("alien %s should be in a destroyed city, but it is in %s", alien, planet.Aliens[alien].City.Id)
+			}
+		}
+	}
+}
+
+func TestNew_SameSeedSameInvasion(t *testing.T) {
+	layout := map[string]map[Direction]string{
+		"A": {East: "B", South: "C"},
+		"B": {West: "A", South: "D"},
+		"C": {North: "A", East: "D"},
+		"D": {North: "B", West: "C"},
+	}
+
+	run := func() []BattleReport {
+		planet, err := New(layout, 3, rand.New(rand.NewSource(7)))
+		if err != nil {
+			t.Fatalf("error while creating the planet: %v", err)
+		}
+
+		reports := make([]BattleReport, 0)
+		for i := 0; i < 20; i++ {
+			reports = append(reports, planet.NextDay().Battles...)
+		}
+
+		return reports
+	}
+
+	if first, second := run(), run(); !reflect.DeepEqual(first, second) {
+		t.Errorf("same seed should produce the same battles, got %v and %v", first, second)
+	}
+}
+
+func TestNew_AllRoadsAdded(t *testing.T) {
+	planet, err := New(map[string]map[Direction]string{
+		"A": {North: "B", East: "C", South: "D", West: "E"},
+		"B": {South: "A"},
+		"C": {West: "A"},
+		"D": {North: "A"},
+		"E": {East: "A"},
+	}, 0, rand.New(rand.NewSource(0)))
+	if err != nil {
+		t.Fatalf("error while creating the planet: %v", err)
+	}
+
+	if edges := planet.graph.GetVertex("A").AllEdges(); !reflect.DeepEqual(edges, []int{North, East, South, West}) {
+		t.Errorf("all the roads of A should exist, got %v", edges)
+	}
+}
+
+func TestNew_SameDestinationRoads(t *testing.T) {
+	planet, err := New(map[string]map[Direction]string{
+		"A": {West: "B", East: "B", South: "C"},
+		"B": {East: "A"},
+		"C": {North: "A"},
+	}, 0, rand.New(rand.NewSource(0)))
+	if err != nil {
+		t.Fatalf("error while creating the planet: %v", err)
+	}
+
+	// Only the road of the lowest direction to B is kept
+	if edges := planet.graph.GetVertex("A").AllEdges(); !reflect.DeepEqual(edges, []int{East, South}) {
+		t.Errorf("A should keep one road to each city, got %v", edges)
+	}
+
+	if _, err := New(map[string]map[Direction]string{"A": {Down + 1: "B"}, "B": {}}, 0, rand.New(rand.NewSource(0))); err == nil {
+		t.Errorf("New() should fail with a road in an unknown direction")
+	}
+}
+
+// gridLayout returns a layout of side*side cities, each one with roads to its neighbors.
+func gridLayout(side int) map[string]map[Direction]string {
+	name := func(x, y int) string { return "city-" + strconv.Itoa(x) + "-" + strconv.Itoa(y) }
+
+	layout := make(map[string]map[Direction]string, side*side)
+	for x := 0; x < side; x++ {
+		for y := 0; y < side; y++ {
+			roads := make(map[Direction]string, 4)
+			if y > 0 {
+				roads[North] = name(x, y-1)
+			}
+			if x < side-1 {
+				roads[East] = name(x+1, y)
 			}
+			if y < side-1 {
+				roads[South] = name(x, y+1)
+			}
+			if x > 0 {
+				roads[West] = name(x-1, y)
+			}
+
+			layout[name(x, y)] = roads
 		}
 	}
+
+	return layout
+}
+
+func BenchmarkNew(b *testing.B) {
+	// Up to a million cities
+	for _, side := range []int{32, 316, 1000} {
+		layout := gridLayout(side)
+
+		b.Run(strconv.Itoa(len(layout)), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				if _, err := New(layout, 100, rand.New(rand.NewSource(int64(i)))); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
 }
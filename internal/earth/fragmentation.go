@@ -0,0 +1,58 @@
+package earth
+
+import (
+	"sort"
+
+	"github.com/jattento/alien-invasion-simulator/internal/platform/datastructure"
+)
+
+// Fragmentation is how the destroyed cities split the planet into components,
+// groups of standing cities connected by roads.
+type Fragmentation struct {
+	Components int
+
+	// Cities of the largest component
+	Largest int
+
+	// Aliens of every component with aliens at its cities, from the one with the most cities to the one with the least.
+	// Aliens at destroyed cities aren't in any component.
+	Aliens []ComponentAliens
+}
+
+// ComponentAliens are the aliens at the cities of a component.
+type ComponentAliens struct {
+	Cities int
+	Aliens int
+}
+
+// Fragmentation returns how the planet is split at the moment. The components are kept up to date
+// as cities are destroyed, so it can be called after every day without going through the whole planet.
+func (planet *Planet) Fragmentation() Fragmentation {
+	// Component:Aliens at its cities
+	aliens := make(map[*datastructure.Component]int)
+	for _, alien := range planet.Aliens {
+		if component := planet.graph.ComponentOf(alien.City); component != nil {
+			aliens[component]++
+		}
+	}
+
+	fragmentation := Fragmentation{
+		Components: planet.graph.ComponentCount(),
+		Largest:    planet.graph.LargestComponent(),
+		Aliens:     make([]ComponentAliens, 0, len(aliens)),
+	}
+
+	for component, amount := range aliens {
+		fragmentation.Aliens = append(fragmentation.Aliens, ComponentAliens{Cities: component.Size(), Aliens: amount})
+	}
+
+	sort.Slice(fragmentation.Aliens, func(i, j int) bool {
+		if fragmentation.Aliens[i].Cities != fragmentation.Aliens[j].Cities {
+			return fragmentation.Aliens[i].Cities > fragmentation.Aliens[j].Cities
+		}
+
+		return fragmentation.Aliens[i].Aliens > fragmentation.Aliens[j].Aliens
+	})
+
+	return fragmentation
+}
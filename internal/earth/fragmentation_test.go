@@ -0,0 +1,35 @@
+package earth
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestPlanet_Fragmentation(t *testing.T) {
+	planet := line(t)
+	planet.Aliens["x"] = &Alien{Name: "x", City: planet.graph.GetVertex("A")}
+	planet.Aliens["y"] = &Alien{Name: "y", City: planet.graph.GetVertex("D")}
+
+	expected := Fragmentation{Components: 1, Largest: 4, Aliens: []ComponentAliens{{Cities: 4, Aliens: 2}}}
+	if fragmentation := planet.Fragmentation(); !reflect.DeepEqual(fragmentation, expected) {
+		t.Errorf("Fragmentation() = %+v, expected %+v", fragmentation, expected)
+	}
+
+	// The line splits in A and C - D, the alien at the destroyed B isn't in any component
+	planet.Aliens["z"] = &Alien{Name: "z", City: planet.graph.GetVertex("B")}
+	planet.graph.GetVertex("B").Disable()
+
+	expected = Fragmentation{Components: 2, Largest: 2, Aliens: []ComponentAliens{{Cities: 2, Aliens: 1}, {Cities: 1, Aliens: 1}}}
+	if fragmentation := planet.Fragmentation(); !reflect.DeepEqual(fragmentation, expected) {
+		t.Errorf("Fragmentation() = %+v, expected %+v", fragmentation, expected)
+	}
+
+	for _, city := range []string{"A", "C", "D"} {
+		planet.graph.GetVertex(city).Disable()
+	}
+
+	expected = Fragmentation{Aliens: []ComponentAliens{}}
+	if fragmentation := planet.Fragmentation(); !reflect.DeepEqual(fragmentation, expected) {
+		t.Errorf("Fragmentation() = %+v, expected %+v", fragmentation, expected)
+	}
+}
@@ -0,0 +1,219 @@
+package earth
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"strconv"
+	"strings"
+
+	"github.com/jattento/alien-invasion-simulator/internal/platform/datastructure"
+)
+
+// Stay is the direction returned by a MovementStrategy when the alien remains at its city.
+const Stay Direction = -1
+
+// MovementContext is everything a MovementStrategy knows when an alien has to move.
+type MovementContext struct {
+	Alien string
+	City  *datastructure.Vertex
+
+	// LastDirection is the road taken the previous day, Stay if the alien didn't move.
+	LastDirection Direction
+
+	// Name:City of every alive alien before anyone moved this day.
+	Aliens map[string]*datastructure.Vertex
+
+	Randomizer *rand.Rand
+}
+
+// MovementStrategy decides the road each alien takes every day. Strategies must not keep state between calls,
+// since the same one may be shared by many planets at the same time.
+type MovementStrategy interface {
+	// Move returns one of the enabled edges of the alien city, or Stay.
+	Move(ctx MovementContext) Direction
+
+	// String returns the spec that ParseMovement turns into this strategy.
+	String() string
+}
+
+var ErrUnknownMovement = errors.New("unknown movement")
+
+// Defaults used by ParseMovement when the spec doesn't have a parameter.
+const (
+	_defaultStayProbability = 0.5
+	_defaultPersistence     = 0.75
+)
+
+// ParseMovement returns the strategy described by spec, which is its name optionally followed by a parameter:
+// uniform, walk, lazy[:stay probability], momentum[:persistence], hunter or hold. An empty spec is uniform.
+func ParseMovement(spec string) (MovementStrategy, error) {
+	name, parameter, hasParameter := strings.Cut(spec, ":")
+
+	probability := func(defaultValue float64) (float64, error) {
+		if !hasParameter {
+			return defaultValue, nil
+		}
+
+		value, err := strconv.ParseFloat(parameter, 64)
+		if err != nil || value < 0 || value > 1 {
+			return 0, fmt.Errorf("%w: %q parameter must be a probability between 0 and 1", ErrUnknownMovement, spec)
+		}
+
+		return value, nil
+	}
+
+	switch name {
+	case "", "uniform":
+		return UniformMovement{}, nil
+	case "walk":
+		return RandomWalkMovement{}, nil
+	case "lazy":
+		stayProbability, err := probability(_defaultStayProbability)
+
+		return LazyMovement{StayProbability: stayProbability}, err
+	case "momentum":
+		persistence, err := probability(_defaultPersistence)
+
+		return MomentumMovement{Persistence: persistence}, err
+	case "hunter":
+		return HunterMovement{}, nil
+	case "hold":
+		return HoldMovement{}, nil
+	default:
+		return nil, fmt.Errorf("%w: %q, must be uniform, walk, lazy, momentum, hunter or hold", ErrUnknownMovement, spec)
+	}
+}
+
+// UniformMovement picks any road or staying with the same probability.
+type UniformMovement struct{}
+
+func (UniformMovement) Move(ctx MovementContext) Direction {
+	edges := append(ctx.City.AllEdges(), Stay)
+
+	return edges[ctx.Randomizer.Intn(len(edges))]
+}
+
+func (UniformMovement) String() string {
+	return "uniform"
+}
+
+// RandomWalkMovement always picks one of the roads, aliens only stay at cities without roads.
+type RandomWalkMovement struct{}
+
+func (RandomWalkMovement) Move(ctx MovementContext) Direction {
+	return randomEdge(ctx)
+}
+
+func (RandomWalkMovement) String() string {
+	return "walk"
+}
+
+// LazyMovement stays with StayProbability, otherwise it picks one of the roads.
+type LazyMovement struct {
+	StayProbability float64
+}
+
+func (movement LazyMovement) Move(ctx MovementContext) Direction {
+	if ctx.Randomizer.Float64() < movement.StayProbability {
+		return Stay
+	}
+
+	return randomEdge(ctx)
+}
+
+func (movement LazyMovement) String() string {
+	return "lazy:" + strconv.FormatFloat(movement.StayProbability, 'g', -1, 64)
+}
+
+// MomentumMovement keeps going in the direction of the previous day with Persistence probability
+// if that road exists, otherwise it picks one of the roads.
+type MomentumMovement struct {
+	Persistence float64
+}
+
+func (movement MomentumMovement) Move(ctx MovementContext) Direction {
+	if ctx.LastDirection != Stay && ctx.Randomizer.Float64() < movement.Persistence {
+		if adjacent := ctx.City.GetAdjacent(ctx.LastDirection); adjacent != nil && adjacent.Enabled() {
+			return ctx.LastDirection
+		}
+	}
+
+	return randomEdge(ctx)
+}
+
+func (movement MomentumMovement) String() string {
+	return "momentum:" + strconv.FormatFloat(movement.Persistence, 'g', -1, 64)
+}
+
+// HunterMovement takes the first road of the shortest path to the nearest city with another alien,
+// it walks randomly when no other alien can be reached.
+type HunterMovement struct{}
+
+func (HunterMovement) Move(ctx MovementContext) Direction {
+	targets := make(map[*datastructure.Vertex]bool, len(ctx.Aliens))
+	for alien, city := range ctx.Aliens {
+		if alien != ctx.Alien {
+			targets[city] = true
+		}
+	}
+
+	if targets[ctx.City] {
+		return Stay
+	}
+
+	// Breadth first search remembering the first road taken to reach each city,
+	// roads are visited in ascending order so ties are always solved the same way
+	firstRoad := map[*datastructure.Vertex]Direction{ctx.City: Stay}
+	queue := []*datastructure.Vertex{ctx.City}
+
+	for len(queue) > 0 {
+		city := queue[0]
+		queue = queue[1:]
+
+		for _, edge := range city.AllEdges() {
+			adjacent := city.GetAdjacent(edge)
+			if _, visited := firstRoad[adjacent]; visited {
+				continue
+			}
+
+			firstRoad[adjacent] = firstRoad[city]
+			if city == ctx.City {
+				firstRoad[adjacent] = edge
+			}
+
+			if targets[adjacent] {
+				return firstRoad[adjacent]
+			}
+
+			queue = append(queue, adjacent)
+		}
+	}
+
+	return randomEdge(ctx)
+}
+
+func (HunterMovement) String() string {
+	return "hunter"
+}
+
+// HoldMovement never leaves the city.
+type HoldMovement struct{}
+
+func (HoldMovement) Move(MovementContext) Direction {
+	return Stay
+}
+
+func (HoldMovement) String() string {
+	return "hold"
+}
+
+// randomEdge picks one of the enabled roads of the alien city, or Stay if there is none.
+func randomEdge(ctx MovementContext) Direction {
+	edges := ctx.City.AllEdges()
+	if len(edges) == 0 {
+		return Stay
+	}
+
+	return edges[ctx.Randomizer.Intn(len(edges))]
+}
@@ -0,0 +1,154 @@
+package earth
+
+import (
+	"errors"
+	"math/rand"
+	"testing"
+
+	"github.com/jattento/alien-invasion-simulator/internal/platform/datastructure"
+)
+
+// line builds the planet A - B - C - D from west to east.
+func line(t *testing.T, opts ...Option) *Planet {
+	planet, err := New(map[string]map[Direction]string{
+		"A": {East: "B"},
+		"B": {West: "A", East: "C"},
+		"C": {West: "B", East: "D"},
+		"D": {West: "C"},
+	}, 0, rand.New(rand.NewSource(0)), opts...)
+	if err != nil {
+		t.Fatalf("error while creating the planet: %v", err)
+	}
+
+	return planet
+}
+
+func TestParseMovement(t *testing.T) {
+	for spec, expected := range map[string]MovementStrategy{
+		"":             UniformMovement{},
+		"uniform":      UniformMovement{},
+		"walk":         RandomWalkMovement{},
+		"lazy":         LazyMovement{StayProbability: 0.5},
+		"lazy:0.9":     LazyMovement{StayProbability: 0.9},
+		"momentum":     MomentumMovement{Persistence: 0.75},
+		"momentum:0.2": MomentumMovement{Persistence: 0.2},
+		"hunter":       HunterMovement{},
+		"hold":         HoldMovement{},
+	} {
+		movement, err := ParseMovement(spec)
+		if err != nil {
+			t.Fatalf("ParseMovement(%q) error: %v", spec, err)
+		}
+
+		if movement != expected {
+			t.Errorf("ParseMovement(%q) = %v, expected %v", spec, movement, expected)
+		}
+
+		// String must be parsed back into the same strategy, since it is used to save it
+		if parsed, _ := ParseMovement(movement.String()); parsed != movement {
+			t.Errorf("ParseMovement(%q) = %v, expected %v", movement.String(), parsed, movement)
+		}
+	}
+
+	for _, spec := range []string{"teleport", "lazy:2", "momentum:fast"} {
+		if _, err := ParseMovement(spec); !errors.Is(err, ErrUnknownMovement) {
+			t.Errorf("ParseMovement(%q) error = %v, expected %v", spec, err, ErrUnknownMovement)
+		}
+	}
+}
+
+func TestMovementStrategies(t *testing.T) {
+	planet := line(t)
+	b := planet.graph.GetVertex("B")
+
+	moves := func(movement MovementStrategy, ctx MovementContext) map[Direction]int {
+		ctx.City, ctx.Randomizer = b, rand.New(rand.NewSource(1))
+
+		counts := make(map[Direction]int)
+		for i := 0; i < 1000; i++ {
+			counts[movement.Move(ctx)]++
+		}
+
+		return counts
+	}
+
+	if counts := moves(UniformMovement{}, MovementContext{}); counts[Stay] == 0 || counts[East] == 0 || counts[West] == 0 {
+		t.Errorf("uniform movement should stay and take every road, got %v", counts)
+	}
+
+	if counts := moves(RandomWalkMovement{}, MovementContext{}); counts[Stay] != 0 {
+		t.Errorf("random walk should never stay, got %v", counts)
+	}
+
+	if counts := moves(LazyMovement{StayProbability: 1}, MovementContext{}); counts[Stay] != 1000 {
+		t.Errorf("lazy movement with stay probability 1 should always stay, got %v", counts)
+	}
+
+	if counts := moves(HoldMovement{}, MovementContext{}); counts[Stay] != 1000 {
+		t.Errorf("hold movement should always stay, got %v", counts)
+	}
+
+	if counts := moves(MomentumMovement{Persistence: 1}, MovementContext{LastDirection: East}); counts[East] != 1000 {
+		t.Errorf("momentum movement with persistence 1 should keep going east, got %v", counts)
+	}
+
+	// A destroyed city can't be entered, so the momentum is lost
+	planet.graph.GetVertex("C").Disable()
+	if counts := moves(MomentumMovement{Persistence: 1}, MovementContext{LastDirection: East}); counts[West] != 1000 {
+		t.Errorf("momentum movement should go west when east is destroyed, got %v", counts)
+	}
+}
+
+func TestHunterMovement(t *testing.T) {
+	planet := line(t)
+	vertex := planet.graph.GetVertex
+
+	hunt := func(from string, aliens map[string]*datastructure.Vertex) Direction {
+		return HunterMovement{}.Move(MovementContext{
+			Alien:      "hunter",
+			City:       vertex(from),
+			Aliens:     aliens,
+			Randomizer: rand.New(rand.NewSource(0)),
+		})
+	}
+
+	if direction := hunt("B", map[string]*datastructure.Vertex{"hunter": vertex("B"), "prey": vertex("D")}); direction != East {
+		t.Errorf("hunter at B should go east to reach D, got %v", direction)
+	}
+
+	// The nearest prey is chased
+	aliens := map[string]*datastructure.Vertex{"hunter": vertex("C"), "far": vertex("A"), "near": vertex("D")}
+	if direction := hunt("C", aliens); direction != East {
+		t.Errorf("hunter at C should go east to the nearest prey, got %v", direction)
+	}
+
+	// Destroyed cities block the way, so it walks randomly
+	vertex("C").Disable()
+	if direction := hunt("A", map[string]*datastructure.Vertex{"hunter": vertex("A"), "prey": vertex("D")}); direction != East {
+		t.Errorf("hunter at A can only walk east, got %v", direction)
+	}
+}
+
+func TestPlanet_NextDay_Movement(t *testing.T) {
+	planet, err := New(map[string]map[Direction]string{
+		"A": {East: "B"},
+		"B": {West: "A"},
+	}, 1, rand.New(rand.NewSource(0)), WithMovement(RandomWalkMovement{}))
+	if err != nil {
+		t.Fatalf("error while creating the planet: %v", err)
+	}
+
+	planet.NextDay()
+
+	for day := 1; day < 10; day++ {
+		for _, movement := range planet.NextDay().Movements {
+			if movement.Stayed {
+				t.Fatalf("day %d: alien %s should never stay", day, movement.Alien)
+			}
+
+			if alien := planet.Aliens[movement.Alien]; alien.lastDirection != movement.Direction {
+				t.Errorf("day %d: last direction of %s = %v, expected %v", day, movement.Alien, alien.lastDirection, movement.Direction)
+			}
+		}
+	}
+}
@@ -0,0 +1,147 @@
+package earth
+
+// Option configures the rules of a planet.
+type Option interface {
+	apply(*Planet)
+}
+
+type movementOption struct {
+	movement MovementStrategy
+}
+
+func (opt movementOption) apply(planet *Planet) {
+	planet.movement = opt.movement
+}
+
+// WithMovement sets how the aliens move every day, UniformMovement is used by default.
+func WithMovement(movement MovementStrategy) Option {
+	return movementOption{movement: movement}
+}
+
+type speciesMixOption []SpeciesShare
+
+func (mix speciesMixOption) apply(planet *Planet) {
+	planet.speciesMix = mix
+}
+
+// WithSpeciesMix spawns aliens of every species following the weights of the mix,
+// every alien is of DefaultSpecies by default.
+func WithSpeciesMix(mix []SpeciesShare) Option {
+	return speciesMixOption(mix)
+}
+
+type battleOption struct {
+	battle BattleResolver
+}
+
+func (opt battleOption) apply(planet *Planet) {
+	planet.battle = opt.battle
+}
+
+// WithBattle sets how battles end, AnnihilationBattle is used by default.
+func WithBattle(battle BattleResolver) Option {
+	return battleOption{battle: battle}
+}
+
+type enRouteCollisionsOption bool
+
+func (enabled enRouteCollisionsOption) apply(planet *Planet) {
+	planet.enRouteCollisions = bool(enabled)
+}
+
+// WithEnRouteCollisions makes the aliens crossing the same road in opposite directions fight on it,
+// the battle rules decide who survives. Aliens can't meet on roads by default.
+func WithEnRouteCollisions(enabled bool) Option {
+	return enRouteCollisionsOption(enabled)
+}
+
+type garrisonsOption []GarrisonPlacement
+
+func (placements garrisonsOption) apply(planet *Planet) {
+	planet.garrisonPlacements = placements
+}
+
+// WithGarrisons places human defenders at the given cities when the planet is created.
+func WithGarrisons(placements []GarrisonPlacement) Option {
+	return garrisonsOption(placements)
+}
+
+type randomGarrisonsOption struct {
+	amount   int
+	strength int
+}
+
+func (opt randomGarrisonsOption) apply(planet *Planet) {
+	planet.randomGarrisons, planet.randomGarrisonStrength = opt.amount, opt.strength
+}
+
+// WithRandomGarrisons places amount garrisons of the given strength at random cities without one
+// when the planet is created, there are never more garrisons than cities.
+func WithRandomGarrisons(amount, strength int) Option {
+	return randomGarrisonsOption{amount: amount, strength: strength}
+}
+
+type defenderMovementOption struct {
+	movement MovementStrategy
+}
+
+func (opt defenderMovementOption) apply(planet *Planet) {
+	planet.defenderMovement = opt.movement
+}
+
+// WithDefenderMovement sets how the garrisons move every day, they hold their cities by default.
+// Garrisons see every alien as a target, so HunterMovement chases the nearest one.
+func WithDefenderMovement(movement MovementStrategy) Option {
+	return defenderMovementOption{movement: movement}
+}
+
+type cityAttributesOption map[string]CityAttributes
+
+func (attributes cityAttributesOption) apply(planet *Planet) {
+	planet.cityAttributes = attributes
+}
+
+// WithCityAttributes sets the population, defense and terrain of the cities by name,
+// the cities without attributes are empty plains.
+func WithCityAttributes(attributes map[string]CityAttributes) Option {
+	return cityAttributesOption(attributes)
+}
+
+type placementOption struct {
+	placement PlacementStrategy
+}
+
+func (opt placementOption) apply(planet *Planet) {
+	planet.placement = opt.placement
+}
+
+// WithPlacement sets where the aliens spawned by New land, RandomPlacement is used by default.
+func WithPlacement(placement PlacementStrategy) Option {
+	return placementOption{placement: placement}
+}
+
+type wavesOption []Wave
+
+func (waves wavesOption) apply(planet *Planet) {
+	planet.waves = waves
+}
+
+// WithWaves lands more aliens during the invasion, every alien is spawned by New by default.
+// The aliens of the waves follow the species mix of the planet.
+func WithWaves(waves []Wave) Option {
+	return wavesOption(waves)
+}
+
+type directionsOption struct {
+	directions *Directions
+}
+
+func (opt directionsOption) apply(planet *Planet) {
+	planet.directions = opt.directions
+}
+
+// WithDirections sets the directions the roads of the layout may take, NewDirections is used by default.
+// Restored planets use the ones of their snapshot.
+func WithDirections(directions *Directions) Option {
+	return directionsOption{directions: directions}
+}
@@ -0,0 +1,423 @@
+package earth
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/jattento/alien-invasion-simulator/internal/platform/datastructure"
+)
+
+// PlacementContext is everything a PlacementStrategy knows when aliens land.
+type PlacementContext struct {
+	// Cities are the cities still standing sorted by name, there is always at least one.
+	Cities []*datastructure.Vertex
+
+	// Graph the Cities belong to, to follow the roads between them.
+	Graph *datastructure.Graph
+
+	// Amount of aliens landing.
+	Amount int
+
+	// Name:City of every alive alien.
+	Aliens map[string]*datastructure.Vertex
+
+	// City:Attributes, the cities without attributes aren't included.
+	Attributes map[string]CityAttributes
+
+	Randomizer *rand.Rand
+}
+
+// PlacementStrategy decides where the aliens of a wave land. Strategies must not keep state between calls,
+// every wave of an invasion may use the same one and each must land as if it were the first.
+type PlacementStrategy interface {
+	// Place returns one of the ctx.Cities for each alien, fewer cities if some aliens can't land.
+	Place(ctx PlacementContext) []*datastructure.Vertex
+
+	// String returns the spec that ParsePlacement turns into this strategy.
+	String() string
+}
+
+var ErrUnknownPlacement = errors.New("unknown placement")
+
+// ParsePlacement returns the strategy described by spec: random, spread, farthest, clustered, region[:radius],
+// largest, degree, population, avoid-battles or cities:<name,...>. An empty spec is random.
+func ParsePlacement(spec string) (PlacementStrategy, error) {
+	name, parameter, hasParameter := strings.Cut(spec, ":")
+
+	switch name {
+	case "", "random":
+		return RandomPlacement{}, nil
+	case "spread":
+		return SpreadPlacement{}, nil
+	case "farthest":
+		return FarthestPlacement{}, nil
+	case "clustered":
+		return ClusteredPlacement{}, nil
+	case "region":
+		if !hasParameter {
+			return RegionPlacement{Radius: 2}, nil
+		}
+
+		radius, err := strconv.Atoi(parameter)
+		if err != nil || radius < 0 {
+			return nil, fmt.Errorf("%w: %q parameter must be an amount of roads of at least 0", ErrUnknownPlacement, spec)
+		}
+
+		return RegionPlacement{Radius: radius}, nil
+	case "largest":
+		return LargestComponentPlacement{}, nil
+	case "degree":
+		return DegreePlacement{}, nil
+	case "population":
+		return PopulationPlacement{}, nil
+	case "avoid-battles":
+		return AvoidBattlesPlacement{}, nil
+	case "cities":
+		if !hasParameter || parameter == "" {
+			return nil, fmt.Errorf("%w: %q parameter must be a list of cities separated by commas", ErrUnknownPlacement, spec)
+		}
+
+		return CitiesPlacement{Cities: strings.Split(parameter, ",")}, nil
+	default:
+		return nil, fmt.Errorf("%w: %q, must be random, spread, farthest, clustered, region, largest, degree, population, "+
+			"avoid-battles or cities", ErrUnknownPlacement, spec)
+	}
+}
+
+// RandomPlacement lands the aliens at random cities, every city gets one before any gets a second one.
+type RandomPlacement struct{}
+
+func (RandomPlacement) Place(ctx PlacementContext) []*datastructure.Vertex {
+	return selectCities(ctx.Randomizer, ctx.Cities, ctx.Amount)
+}
+
+func (RandomPlacement) String() string {
+	return "random"
+}
+
+// SpreadPlacement lands the aliens as far from each other as it can: the cities with fewer aliens are filled first,
+// in random order.
+type SpreadPlacement struct{}
+
+func (SpreadPlacement) Place(ctx PlacementContext) []*datastructure.Vertex {
+	occupation := make(map[*datastructure.Vertex]int)
+	for _, city := range ctx.Aliens {
+		occupation[city]++
+	}
+
+	cities := make([]*datastructure.Vertex, 0, len(ctx.Cities))
+	for _, i := range ctx.Randomizer.Perm(len(ctx.Cities)) {
+		cities = append(cities, ctx.Cities[i])
+	}
+
+	sort.SliceStable(cities, func(i, j int) bool { return occupation[cities[i]] < occupation[cities[j]] })
+
+	placed := make([]*datastructure.Vertex, 0, ctx.Amount)
+	for i := 0; i < ctx.Amount; i++ {
+		placed = append(placed, cities[i%len(cities)])
+	}
+
+	return placed
+}
+
+func (SpreadPlacement) String() string {
+	return "spread"
+}
+
+// FarthestPlacement lands each alien at the city farthest by roads from every alien, picking randomly between
+// the cities at the same distance. Cities that no alien can reach are the farthest ones.
+type FarthestPlacement struct{}
+
+func (FarthestPlacement) Place(ctx PlacementContext) []*datastructure.Vertex {
+	unreachable := len(ctx.Cities)
+
+	// City:Roads to the nearest alien
+	distance := make(map[*datastructure.Vertex]int, len(ctx.Cities))
+	for _, city := range ctx.Cities {
+		distance[city] = unreachable
+	}
+
+	// Aliens at destroyed cities are left out, the distances are only measured between standing cities
+	occupied := make([]*datastructure.Vertex, 0, len(ctx.Aliens))
+	for _, city := range ctx.Aliens {
+		if city.Enabled() {
+			occupied = append(occupied, city)
+		}
+	}
+
+	approach := func(sources []*datastructure.Vertex) {
+		for city, roads := range ctx.Graph.Distances(sources) {
+			if roads < distance[city] {
+				distance[city] = roads
+			}
+		}
+	}
+
+	approach(occupied)
+
+	placed := make([]*datastructure.Vertex, 0, ctx.Amount)
+	for i := 0; i < ctx.Amount; i++ {
+		farthest := make([]*datastructure.Vertex, 0)
+		for _, city := range ctx.Cities {
+			switch {
+			case len(farthest) == 0 || distance[city] > distance[farthest[0]]:
+				farthest = []*datastructure.Vertex{city}
+			case distance[city] == distance[farthest[0]]:
+				farthest = append(farthest, city)
+			}
+		}
+
+		city := farthest[ctx.Randomizer.Intn(len(farthest))]
+		placed = append(placed, city)
+		approach([]*datastructure.Vertex{city})
+	}
+
+	return placed
+}
+
+func (FarthestPlacement) String() string {
+	return "farthest"
+}
+
+// ClusteredPlacement lands the aliens around a random city, each one at the city or any of its neighbors.
+type ClusteredPlacement struct{}
+
+func (ClusteredPlacement) Place(ctx PlacementContext) []*datastructure.Vertex {
+	center := ctx.Cities[ctx.Randomizer.Intn(len(ctx.Cities))]
+
+	cluster := []*datastructure.Vertex{center}
+	for _, edgeId := range center.AllEdges() {
+		cluster = append(cluster, center.GetAdjacent(edgeId))
+	}
+
+	placed := make([]*datastructure.Vertex, 0, ctx.Amount)
+	for i := 0; i < ctx.Amount; i++ {
+		placed = append(placed, cluster[ctx.Randomizer.Intn(len(cluster))])
+	}
+
+	return placed
+}
+
+func (ClusteredPlacement) String() string {
+	return "clustered"
+}
+
+// RegionPlacement lands the aliens as RandomPlacement does, but only at the cities up to Radius roads away
+// from a random city.
+type RegionPlacement struct {
+	Radius int
+}
+
+func (placement RegionPlacement) Place(ctx PlacementContext) []*datastructure.Vertex {
+	center := ctx.Cities[ctx.Randomizer.Intn(len(ctx.Cities))]
+
+	region := make([]*datastructure.Vertex, 0)
+	distances := ctx.Graph.Distances([]*datastructure.Vertex{center})
+
+	for _, city := range ctx.Cities {
+		if roads, reachable := distances[city]; reachable && roads <= placement.Radius {
+			region = append(region, city)
+		}
+	}
+
+	return selectCities(ctx.Randomizer, region, ctx.Amount)
+}
+
+func (placement RegionPlacement) String() string {
+	return "region:" + strconv.Itoa(placement.Radius)
+}
+
+// LargestComponentPlacement lands the aliens as RandomPlacement does, but only at the largest group of cities
+// connected by roads. Between groups of the same size the one with the first city by name is chosen.
+type LargestComponentPlacement struct{}
+
+func (LargestComponentPlacement) Place(ctx PlacementContext) []*datastructure.Vertex {
+	var largest []*datastructure.Vertex
+	for _, component := range ctx.Graph.Components() {
+		if len(component) > len(largest) {
+			largest = component
+		}
+	}
+
+	return selectCities(ctx.Randomizer, largest, ctx.Amount)
+}
+
+func (LargestComponentPlacement) String() string {
+	return "largest"
+}
+
+// DegreePlacement lands the aliens at random cities, the more roads a city has the more likely.
+// Cities without roads only get aliens if no city has roads.
+type DegreePlacement struct{}
+
+func (DegreePlacement) Place(ctx PlacementContext) []*datastructure.Vertex {
+	return weightedCities(ctx, func(city *datastructure.Vertex) int { return len(city.AllEdges()) })
+}
+
+func (DegreePlacement) String() string {
+	return "degree"
+}
+
+// PopulationPlacement lands the aliens at random cities, the more population a city has the more likely.
+// Cities without population only get aliens if no city has population.
+type PopulationPlacement struct{}
+
+func (PopulationPlacement) Place(ctx PlacementContext) []*datastructure.Vertex {
+	return weightedCities(ctx, func(city *datastructure.Vertex) int { return ctx.Attributes[city.Id].Population })
+}
+
+func (PopulationPlacement) String() string {
+	return "population"
+}
+
+// AvoidBattlesPlacement lands the aliens where they don't fight right away: at a random city without aliens
+// next to it first, then at a random city without aliens, and only then anywhere.
+type AvoidBattlesPlacement struct{}
+
+func (AvoidBattlesPlacement) Place(ctx PlacementContext) []*datastructure.Vertex {
+	occupation := make(map[*datastructure.Vertex]int, len(ctx.Aliens))
+	for _, city := range ctx.Aliens {
+		occupation[city]++
+	}
+
+	calm := func(city *datastructure.Vertex) bool {
+		for _, edgeId := range city.AllEdges() {
+			if occupation[city.GetAdjacent(edgeId)] > 0 {
+				return false
+			}
+		}
+
+		return occupation[city] == 0
+	}
+
+	placed := make([]*datastructure.Vertex, 0, ctx.Amount)
+	for i := 0; i < ctx.Amount; i++ {
+		calmCities, emptyCities := make([]*datastructure.Vertex, 0), make([]*datastructure.Vertex, 0)
+		for _, city := range ctx.Cities {
+			if calm(city) {
+				calmCities = append(calmCities, city)
+			}
+
+			if occupation[city] == 0 {
+				emptyCities = append(emptyCities, city)
+			}
+		}
+
+		candidates := ctx.Cities
+		switch {
+		case len(calmCities) > 0:
+			candidates = calmCities
+		case len(emptyCities) > 0:
+			candidates = emptyCities
+		}
+
+		city := candidates[ctx.Randomizer.Intn(len(candidates))]
+		occupation[city]++
+		placed = append(placed, city)
+	}
+
+	return placed
+}
+
+func (AvoidBattlesPlacement) String() string {
+	return "avoid-battles"
+}
+
+// CitiesPlacement lands the aliens at the given cities taking turns, skipping the destroyed ones.
+// No alien lands if every city was destroyed.
+type CitiesPlacement struct {
+	Cities []string
+}
+
+func (placement CitiesPlacement) Place(ctx PlacementContext) []*datastructure.Vertex {
+	standing := make(map[string]*datastructure.Vertex, len(ctx.Cities))
+	for _, city := range ctx.Cities {
+		standing[city.Id] = city
+	}
+
+	cities := make([]*datastructure.Vertex, 0, len(placement.Cities))
+	for _, name := range placement.Cities {
+		if city, exists := standing[name]; exists {
+			cities = append(cities, city)
+		}
+	}
+
+	if len(cities) == 0 {
+		return nil
+	}
+
+	placed := make([]*datastructure.Vertex, 0, ctx.Amount)
+	for i := 0; i < ctx.Amount; i++ {
+		placed = append(placed, cities[i%len(cities)])
+	}
+
+	return placed
+}
+
+func (placement CitiesPlacement) String() string {
+	return "cities:" + strings.Join(placement.Cities, ",")
+}
+
+// checkPlacement returns an error if the placement lands aliens at a city that doesn't exist,
+// what describes who uses the placement.
+func (planet *Planet) checkPlacement(placement PlacementStrategy, what string) error {
+	citiesPlacement, isCitiesPlacement := placement.(CitiesPlacement)
+	if !isCitiesPlacement {
+		return nil
+	}
+
+	for _, city := range citiesPlacement.Cities {
+		if planet.graph.GetVertex(city) == nil {
+			return fmt.Errorf("%w: %s lands aliens at %q", datastructure.ErrVertexNotFound, what, city)
+		}
+	}
+
+	return nil
+}
+
+// selectCities returns amount random cities, every city is selected once before any is selected again.
+func selectCities(randomizer *rand.Rand, cities []*datastructure.Vertex, amount int) []*datastructure.Vertex {
+	selector := newRandomSelector(randomizer, cities)
+
+	selected := make([]*datastructure.Vertex, 0, amount)
+	for i := 0; i < amount; i++ {
+		selected = append(selected, selector())
+	}
+
+	return selected
+}
+
+// weightedCities returns amount random cities, each one as likely as its weight. If every weight is 0
+// the cities are selected as RandomPlacement does.
+func weightedCities(ctx PlacementContext, weight func(city *datastructure.Vertex) int) []*datastructure.Vertex {
+	weights := make([]int, len(ctx.Cities))
+	total := 0
+
+	for i, city := range ctx.Cities {
+		weights[i] = weight(city)
+		total += weights[i]
+	}
+
+	if total == 0 {
+		return selectCities(ctx.Randomizer, ctx.Cities, ctx.Amount)
+	}
+
+	selected := make([]*datastructure.Vertex, 0, ctx.Amount)
+	for i := 0; i < ctx.Amount; i++ {
+		target := ctx.Randomizer.Intn(total)
+		for j, cityWeight := range weights {
+			if target < cityWeight {
+				selected = append(selected, ctx.Cities[j])
+				break
+			}
+
+			target -= cityWeight
+		}
+	}
+
+	return selected
+}
@@ -0,0 +1,170 @@
+package earth
+
+import (
+	"errors"
+	"math/rand"
+	"reflect"
+	"testing"
+
+	"github.com/jattento/alien-invasion-simulator/internal/platform/datastructure"
+)
+
+func TestParsePlacement(t *testing.T) {
+	for _, spec := range []string{"random", "spread", "farthest", "clustered", "region:3", "largest", "degree", "population",
+		"avoid-battles", "cities:A,C"} {
+		placement, err := ParsePlacement(spec)
+		if err != nil {
+			t.Fatalf("ParsePlacement(%q) error = %v", spec, err)
+		}
+
+		if placement.String() != spec {
+			t.Errorf("ParsePlacement(%q).String() = %q", spec, placement.String())
+		}
+	}
+
+	if placement, err := ParsePlacement(""); err != nil || placement != (RandomPlacement{}) {
+		t.Errorf("ParsePlacement(\"\") = %v, %v, expected random", placement, err)
+	}
+
+	if placement, err := ParsePlacement("region"); err != nil || placement != (RegionPlacement{Radius: 2}) {
+		t.Errorf("ParsePlacement(\"region\") = %v, %v, expected a radius of 2", placement, err)
+	}
+
+	for _, spec := range []string{"cities", "cities:", "nearest", "region:-1", "region:x"} {
+		if _, err := ParsePlacement(spec); !errors.Is(err, ErrUnknownPlacement) {
+			t.Errorf("ParsePlacement(%q) error = %v, expected ErrUnknownPlacement", spec, err)
+		}
+	}
+}
+
+func TestPlacementStrategies(t *testing.T) {
+	planet := line(t)
+	names := func(cities []*datastructure.Vertex) []string {
+		ids := make([]string, 0, len(cities))
+		for _, city := range cities {
+			ids = append(ids, city.Id)
+		}
+
+		return ids
+	}
+
+	place := func(placement PlacementStrategy, amount int, aliens map[string]*datastructure.Vertex) []*datastructure.Vertex {
+		return placement.Place(PlacementContext{
+			Cities:     planet.standingCities(),
+			Graph:      planet.graph,
+			Amount:     amount,
+			Aliens:     aliens,
+			Randomizer: rand.New(rand.NewSource(1)),
+		})
+	}
+
+	counts := func(cities []*datastructure.Vertex) map[string]int {
+		counts := make(map[string]int)
+		for _, city := range cities {
+			counts[city.Id]++
+		}
+
+		return counts
+	}
+
+	if placed := counts(place(RandomPlacement{}, 4, nil)); len(placed) != 4 {
+		t.Errorf("random placement should use every city before repeating one, got %v", placed)
+	}
+
+	// A and B already have aliens, so the first ones land at C and D
+	occupied := map[string]*datastructure.Vertex{"x": planet.graph.GetVertex("A"), "y": planet.graph.GetVertex("B")}
+	if placed := counts(place(SpreadPlacement{}, 2, occupied)); placed["C"] != 1 || placed["D"] != 1 {
+		t.Errorf("spread placement should fill the empty cities first, got %v", placed)
+	}
+
+	if placed := counts(place(ClusteredPlacement{}, 50, nil)); len(placed) > 3 {
+		t.Errorf("clustered placement should only use a city and its neighbors, got %v", placed)
+	}
+
+	if placed := names(place(CitiesPlacement{Cities: []string{"D", "A"}}, 3, nil)); !reflect.DeepEqual(placed, []string{"D", "A", "D"}) {
+		t.Errorf("cities placement should take turns between the cities, got %v", placed)
+	}
+
+	// Destroying B splits the planet into A and C - D
+	planet.graph.GetVertex("B").Disable()
+
+	if placed := counts(place(LargestComponentPlacement{}, 10, nil)); placed["A"] != 0 || placed["C"] == 0 || placed["C"]+placed["D"] != 10 {
+		t.Errorf("largest placement should only use C and D, got %v", placed)
+	}
+
+	if placed := place(CitiesPlacement{Cities: []string{"B"}}, 3, nil); len(placed) != 0 {
+		t.Errorf("cities placement shouldn't land at destroyed cities, got %v", names(placed))
+	}
+}
+
+func TestPlacementStrategies_Spread(t *testing.T) {
+	planet := line(t)
+	place := func(placement PlacementStrategy, amount int, aliens map[string]*datastructure.Vertex) map[string]int {
+		counts := make(map[string]int)
+		for _, city := range placement.Place(PlacementContext{
+			Cities:     planet.standingCities(),
+			Graph:      planet.graph,
+			Amount:     amount,
+			Aliens:     aliens,
+			Attributes: map[string]CityAttributes{"C": {Population: 10}},
+			Randomizer: rand.New(rand.NewSource(1)),
+		}) {
+			counts[city.Id]++
+		}
+
+		return counts
+	}
+
+	// The farthest city from an alien at A is D
+	if placed := place(FarthestPlacement{}, 1, map[string]*datastructure.Vertex{"x": planet.graph.GetVertex("A")}); placed["D"] != 1 {
+		t.Errorf("farthest placement should land at D, got %v", placed)
+	}
+
+	// Wherever the first alien lands, the second one is at least two roads away
+	if placed := place(FarthestPlacement{}, 2, nil); len(placed) != 2 || placed["A"]+placed["D"] == 0 {
+		t.Errorf("farthest placement should land the aliens apart, got %v", placed)
+	}
+
+	if placed := place(RegionPlacement{Radius: 0}, 5, nil); len(placed) != 1 {
+		t.Errorf("region placement without radius should use a single city, got %v", placed)
+	}
+
+	// A and D only have a road each, B and C have two
+	if placed := place(DegreePlacement{}, 600, nil); placed["B"] < placed["A"] || placed["C"] < placed["D"] {
+		t.Errorf("degree placement should favor the cities with more roads, got %v", placed)
+	}
+
+	if placed := place(PopulationPlacement{}, 10, nil); placed["C"] != 10 {
+		t.Errorf("population placement should only use the populated C, got %v", placed)
+	}
+
+	// Only D is neither occupied nor next to an occupied city
+	occupied := map[string]*datastructure.Vertex{"x": planet.graph.GetVertex("A"), "y": planet.graph.GetVertex("B")}
+	if placed := place(AvoidBattlesPlacement{}, 2, occupied); placed["D"] != 1 || placed["C"] != 1 {
+		t.Errorf("avoid-battles placement should land at D and then at the empty C, got %v", placed)
+	}
+}
+
+func TestNew_Placement(t *testing.T) {
+	layout := map[string]map[Direction]string{"A": {East: "B"}, "B": {West: "A"}, "C": {}}
+
+	planet, err := New(layout, 4, rand.New(rand.NewSource(0)), WithPlacement(CitiesPlacement{Cities: []string{"C"}}))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	for name, alien := range planet.Aliens {
+		if alien.City.Id != "C" {
+			t.Errorf("alien %q spawned at %q, expected C", name, alien.City.Id)
+		}
+	}
+
+	_, err = New(layout, 4, rand.New(rand.NewSource(0)), WithPlacement(CitiesPlacement{Cities: []string{"Z"}}))
+	if !errors.Is(err, datastructure.ErrVertexNotFound) {
+		t.Errorf("New() error = %v, expected ErrVertexNotFound", err)
+	}
+
+	if planet, err = New(map[string]map[Direction]string{}, 4, rand.New(rand.NewSource(0))); err != nil || len(planet.Aliens) != 0 {
+		t.Errorf("New() without cities = %v, %v, expected no aliens", planet, err)
+	}
+}
@@ -0,0 +1,81 @@
+package earth
+
+import "github.com/jattento/alien-invasion-simulator/internal/platform/datastructure"
+
+// AliensIsolated returns whether every alien is alone at a city without roads to a standing city,
+// so none of them can ever fight again. It is false if there are no aliens.
+func (planet *Planet) AliensIsolated() bool {
+	if len(planet.Aliens) == 0 {
+		return false
+	}
+
+	occupation := make(map[*datastructure.Vertex]int, len(planet.Aliens))
+	for _, alien := range planet.Aliens {
+		occupation[alien.City]++
+	}
+
+	for city, aliens := range occupation {
+		if aliens > 1 || len(city.AllEdges()) > 0 {
+			return false
+		}
+	}
+
+	return true
+}
+
+// BattlesPossible returns whether two aliens can still meet, which needs a group of standing cities connected
+// by roads that both of them can reach. Aliens at destroyed cities can still take the roads leaving them.
+func (planet *Planet) BattlesPossible() bool {
+	return planet.AliensTrapped() < len(planet.Aliens)
+}
+
+// AliensTrapped returns the amount of aliens that can't meet any other alien anymore: they are alone at their
+// city and no other alien can reach the group of standing cities connected by roads they can reach.
+func (planet *Planet) AliensTrapped() int {
+	occupation := make(map[*datastructure.Vertex]int, len(planet.Aliens))
+	for _, alien := range planet.Aliens {
+		occupation[alien.City]++
+	}
+
+	// City:Components the aliens at it can reach
+	reaches := make(map[*datastructure.Vertex][]*datastructure.Component, len(occupation))
+
+	// Component:Amount of aliens that can reach it
+	reachable := make(map[*datastructure.Component]int)
+
+	for city, aliens := range occupation {
+		if city.Enabled() {
+			reaches[city] = []*datastructure.Component{planet.graph.ComponentOf(city)}
+		} else {
+			reached := make(map[*datastructure.Component]bool)
+			for _, edgeId := range city.AllEdges() {
+				if component := planet.graph.ComponentOf(city.GetAdjacent(edgeId)); component != nil && !reached[component] {
+					reached[component] = true
+					reaches[city] = append(reaches[city], component)
+				}
+			}
+		}
+
+		for _, component := range reaches[city] {
+			reachable[component] += aliens
+		}
+	}
+
+	trapped := 0
+	for city, aliens := range occupation {
+		if aliens > 1 {
+			continue
+		}
+
+		free := false
+		for _, component := range reaches[city] {
+			free = free || reachable[component] > 1
+		}
+
+		if !free {
+			trapped++
+		}
+	}
+
+	return trapped
+}
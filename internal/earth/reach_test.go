@@ -0,0 +1,99 @@
+package earth
+
+import "testing"
+
+func TestPlanet_AliensIsolated(t *testing.T) {
+	planet := line(t)
+
+	if planet.AliensIsolated() {
+		t.Errorf("AliensIsolated() = true without aliens")
+	}
+
+	planet.Aliens["x"] = &Alien{Name: "x", City: planet.graph.GetVertex("A")}
+	planet.Aliens["y"] = &Alien{Name: "y", City: planet.graph.GetVertex("D")}
+
+	if planet.AliensIsolated() {
+		t.Errorf("AliensIsolated() = true with aliens that can still move")
+	}
+
+	// A and D are left without roads
+	planet.graph.GetVertex("B").Disable()
+	planet.graph.GetVertex("C").Disable()
+
+	if !planet.AliensIsolated() {
+		t.Errorf("AliensIsolated() = false with every alien alone and without roads")
+	}
+
+	planet.Aliens["z"] = &Alien{Name: "z", City: planet.graph.GetVertex("A")}
+	if planet.AliensIsolated() {
+		t.Errorf("AliensIsolated() = true with two aliens at the same city")
+	}
+}
+
+func TestPlanet_BattlesPossible(t *testing.T) {
+	place := func(planet *Planet, cities ...string) {
+		for i, city := range cities {
+			name := string(rune('a' + i))
+			planet.Aliens[name] = &Alien{Name: name, City: planet.graph.GetVertex(city)}
+		}
+	}
+
+	planet := line(t)
+	place(planet, "A", "D")
+
+	if !planet.BattlesPossible() {
+		t.Errorf("BattlesPossible() = false with two aliens connected by roads")
+	}
+
+	planet.graph.GetVertex("B").Disable()
+	if planet.BattlesPossible() {
+		t.Errorf("BattlesPossible() = true with the aliens apart")
+	}
+
+	// The alien at the destroyed B can still reach both sides
+	planet = line(t)
+	place(planet, "A", "B", "D")
+	planet.graph.GetVertex("B").Disable()
+	planet.graph.GetVertex("C").Disable()
+
+	if !planet.BattlesPossible() {
+		t.Errorf("BattlesPossible() = false with an alien that can still leave its destroyed city")
+	}
+
+	delete(planet.Aliens, "a")
+	if planet.BattlesPossible() {
+		t.Errorf("BattlesPossible() = true with every alien alone")
+	}
+
+	planet.Aliens["d"] = &Alien{Name: "d", City: planet.graph.GetVertex("D")}
+	if !planet.BattlesPossible() {
+		t.Errorf("BattlesPossible() = false with two aliens at the same city")
+	}
+}
+
+func TestPlanet_AliensTrapped(t *testing.T) {
+	planet := line(t)
+	for name, city := range map[string]string{"a": "A", "b": "C", "c": "D"} {
+		planet.Aliens[name] = &Alien{Name: name, City: planet.graph.GetVertex(city)}
+	}
+
+	if trapped := planet.AliensTrapped(); trapped != 0 {
+		t.Errorf("AliensTrapped() = %d with every alien connected by roads, expected 0", trapped)
+	}
+
+	// A is cut from C and D, whose aliens can still meet
+	planet.graph.GetVertex("B").Disable()
+	if trapped := planet.AliensTrapped(); trapped != 1 {
+		t.Errorf("AliensTrapped() = %d with the alien at A apart, expected 1", trapped)
+	}
+
+	planet.graph.GetVertex("C").Disable()
+	if trapped := planet.AliensTrapped(); trapped != 1 {
+		t.Errorf("AliensTrapped() = %d with an alien that can still leave the destroyed C, expected 1", trapped)
+	}
+
+	delete(planet.Aliens, "c")
+	if trapped := planet.AliensTrapped(); trapped != 2 {
+		t.Errorf("AliensTrapped() = %d with every alien alone, expected 2", trapped)
+	}
+}
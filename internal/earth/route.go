@@ -0,0 +1,52 @@
+package earth
+
+import (
+	"fmt"
+
+	"github.com/jattento/alien-invasion-simulator/internal/platform/datastructure"
+)
+
+// Road is a road taken from a city to another.
+type Road struct {
+	From      string
+	To        string
+	Direction Direction
+}
+
+// Route returns the roads of the shortest way from one city to another, empty if both are the same.
+// Only standing cities are crossed, but a destroyed from city can still be left, as an alien in it would.
+// It returns datastructure.ErrPathNotFound if no road leads there.
+func (planet *Planet) Route(from, to string) ([]Road, error) {
+	path, err := planet.graph.ShortestPath(from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	roads := make([]Road, 0, len(path))
+	for _, edge := range path {
+		roads = append(roads, Road{From: edge.From, To: edge.To, Direction: edge.Id})
+	}
+
+	return roads, nil
+}
+
+// Distances returns City:Roads from the nearest of cities, for every city that can be reached from them.
+// Like Route, only standing cities are reached but destroyed ones can still be left.
+func (planet *Planet) Distances(cities []string) (map[string]int, error) {
+	sources := make([]*datastructure.Vertex, 0, len(cities))
+	for _, city := range cities {
+		vertex := planet.graph.GetVertex(city)
+		if vertex == nil {
+			return nil, fmt.Errorf("%w: %q", datastructure.ErrVertexNotFound, city)
+		}
+
+		sources = append(sources, vertex)
+	}
+
+	distances := make(map[string]int)
+	for vertex, roads := range planet.graph.Distances(sources) {
+		distances[vertex.Id] = roads
+	}
+
+	return distances, nil
+}
@@ -0,0 +1,64 @@
+package earth
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/jattento/alien-invasion-simulator/internal/platform/datastructure"
+)
+
+func TestPlanet_Route(t *testing.T) {
+	planet := line(t)
+
+	roads, err := planet.Route("A", "D")
+	if err != nil {
+		t.Fatalf("Route() failed with error %v", err)
+	}
+
+	expected := []Road{{From: "A", To: "B", Direction: East}, {From: "B", To: "C", Direction: East}, {From: "C", To: "D", Direction: East}}
+	if !reflect.DeepEqual(roads, expected) {
+		t.Errorf("Route() = %v, expected %v", roads, expected)
+	}
+
+	if roads, err := planet.Route("B", "B"); err != nil || len(roads) != 0 {
+		t.Errorf("Route() to the same city = %v, %v, expected no roads", roads, err)
+	}
+
+	if _, err := planet.Route("A", "Z"); !errors.Is(err, datastructure.ErrVertexNotFound) {
+		t.Errorf("Route() to an unknown city should return %v, but returned %v", datastructure.ErrVertexNotFound, err)
+	}
+
+	planet.graph.GetVertex("C").Disable()
+	if _, err := planet.Route("A", "D"); !errors.Is(err, datastructure.ErrPathNotFound) {
+		t.Errorf("Route() through a destroyed city should return %v, but returned %v", datastructure.ErrPathNotFound, err)
+	}
+
+	// A destroyed city can still be left
+	if roads, err := planet.Route("C", "D"); err != nil || len(roads) != 1 {
+		t.Errorf("Route() from a destroyed city = %v, %v, expected one road", roads, err)
+	}
+}
+
+func TestPlanet_Distances(t *testing.T) {
+	planet := line(t)
+
+	distances, err := planet.Distances([]string{"A", "D"})
+	if err != nil {
+		t.Fatalf("Distances() failed with error %v", err)
+	}
+
+	expected := map[string]int{"A": 0, "B": 1, "C": 1, "D": 0}
+	if !reflect.DeepEqual(distances, expected) {
+		t.Errorf("Distances() = %v, expected %v", distances, expected)
+	}
+
+	planet.graph.GetVertex("B").Disable()
+	if distances, _ := planet.Distances([]string{"A"}); !reflect.DeepEqual(distances, map[string]int{"A": 0}) {
+		t.Errorf("Distances() through a destroyed city = %v, expected only A", distances)
+	}
+
+	if _, err := planet.Distances([]string{"Z"}); !errors.Is(err, datastructure.ErrVertexNotFound) {
+		t.Errorf("Distances() from an unknown city should return %v, but returned %v", datastructure.ErrVertexNotFound, err)
+	}
+}
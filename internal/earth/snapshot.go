@@ -0,0 +1,338 @@
+package earth
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+
+	"github.com/jattento/alien-invasion-simulator/internal/platform/datastructure"
+)
+
+// Snapshot is the serializable state of a planet, the randomizer isn't included since it belongs to the caller.
+type Snapshot struct {
+	Cities []CitySnapshot `json:"cities"`
+
+	// Alien:City
+	Aliens map[string]string `json:"aliens"`
+
+	// City:[Aliens] battles of day zero, nil if day zero already passed
+	DayZero map[string][]string `json:"day_zero,omitempty"`
+
+	// Alien:Road taken the previous day
+	LastDirections map[string]Direction `json:"last_directions,omitempty"`
+
+	// Alien:Species, the aliens of DefaultSpecies aren't included
+	AlienSpecies map[string]string `json:"alien_species,omitempty"`
+
+	// Species:Stats of every species in AlienSpecies and SpeciesMix
+	Species map[string]SpeciesSnapshot `json:"species,omitempty"`
+
+	// SpeciesMix is the mix the aliens of the waves that didn't land yet get their species from, see WithSpeciesMix
+	SpeciesMix []SpeciesShareSnapshot `json:"species_mix,omitempty"`
+
+	// Garrisons are sorted by name
+	Garrisons []GarrisonSnapshot `json:"garrisons,omitempty"`
+
+	// Day is the amount of days that already passed
+	Day int `json:"day,omitempty"`
+
+	// Name:Amount of aliens that got it, only kept while there are waves to land
+	AlienNames map[string]int `json:"alien_names,omitempty"`
+
+	// Directions are the free-form directions of the planet in the order of their values,
+	// the first one goes right after the built-in ones.
+	Directions []DirectionSnapshot `json:"directions,omitempty"`
+}
+
+// DirectionSnapshot is a free-form direction and its inverse, see Directions.Register.
+type DirectionSnapshot struct {
+	Name    string `json:"name"`
+	Inverse string `json:"inverse"`
+}
+
+// GarrisonSnapshot is a garrison and the city where it is.
+type GarrisonSnapshot struct {
+	Name     string `json:"name"`
+	City     string `json:"city"`
+	Strength int    `json:"strength"`
+}
+
+// SpeciesShareSnapshot is the weight of a species of Snapshot.Species in the species mix.
+type SpeciesShareSnapshot struct {
+	Species string `json:"species"`
+	Weight  int    `json:"weight"`
+}
+
+// SpeciesSnapshot Movement is the spec of the species movement strategy, empty if it uses the planet one.
+type SpeciesSnapshot struct {
+	Movement string `json:"movement,omitempty"`
+	Speed    int    `json:"speed"`
+	Strength int    `json:"strength"`
+}
+
+// CitySnapshot roads include the ones leading to destroyed cities.
+type CitySnapshot struct {
+	Name      string               `json:"name"`
+	Roads     map[Direction]string `json:"roads"`
+	Destroyed bool                 `json:"destroyed"`
+
+	Population int     `json:"population,omitempty"`
+	Defense    int     `json:"defense,omitempty"`
+	Terrain    Terrain `json:"terrain,omitempty"`
+}
+
+// Snapshot returns the current state of the planet, cities are sorted by name.
+func (planet *Planet) Snapshot() Snapshot {
+	snapshot := Snapshot{
+		Cities: make([]CitySnapshot, 0),
+		Aliens: make(map[string]string, len(planet.Aliens)),
+	}
+
+	for _, vertex := range planet.graph.Vertices() {
+		attributes := planet.attributes(vertex)
+
+		city := CitySnapshot{
+			Name:       vertex.Id,
+			Roads:      make(map[Direction]string),
+			Destroyed:  !vertex.Enabled(),
+			Population: attributes.Population,
+			Defense:    attributes.Defense,
+			Terrain:    attributes.Terrain,
+		}
+		for direction, adjacent := range vertex.Edges() {
+			city.Roads[direction] = adjacent.Id
+		}
+
+		snapshot.Cities = append(snapshot.Cities, city)
+	}
+
+	sort.Slice(snapshot.Cities, func(i, j int) bool { return snapshot.Cities[i].Name < snapshot.Cities[j].Name })
+
+	for direction := _builtinDirections; planet.directions.Known(direction); direction++ {
+		inverse, _ := planet.directions.Inverse(direction)
+		snapshot.Directions = append(snapshot.Directions, DirectionSnapshot{
+			Name:    planet.directions.Name(direction),
+			Inverse: planet.directions.Name(inverse),
+		})
+	}
+
+	for name, alien := range planet.Aliens {
+		snapshot.Aliens[name] = alien.City.Id
+
+		if alien.lastDirection != Stay {
+			if snapshot.LastDirections == nil {
+				snapshot.LastDirections = make(map[string]Direction)
+			}
+
+			snapshot.LastDirections[name] = alien.lastDirection
+		}
+
+		if alien.Species != DefaultSpecies {
+			if snapshot.AlienSpecies == nil {
+				snapshot.AlienSpecies = make(map[string]string)
+				snapshot.Species = make(map[string]SpeciesSnapshot)
+			}
+
+			snapshot.AlienSpecies[name] = alien.Species.Name
+			snapshot.Species[alien.Species.Name] = newSpeciesSnapshot(alien.Species)
+		}
+	}
+
+	for _, share := range planet.speciesMix {
+		if snapshot.Species == nil {
+			snapshot.Species = make(map[string]SpeciesSnapshot)
+		}
+
+		snapshot.Species[share.Species.Name] = newSpeciesSnapshot(share.Species)
+		snapshot.SpeciesMix = append(snapshot.SpeciesMix, SpeciesShareSnapshot{Species: share.Species.Name, Weight: share.Weight})
+	}
+
+	for _, name := range planet.garrisonNames() {
+		garrison := planet.Garrisons[name]
+		snapshot.Garrisons = append(snapshot.Garrisons, GarrisonSnapshot{Name: name, City: garrison.City.Id, Strength: garrison.Strength})
+	}
+
+	snapshot.Day = planet.day
+	if planet.AliensIncoming() > 0 {
+		snapshot.AlienNames = make(map[string]int, len(planet.nameCounts))
+		for name, count := range planet.nameCounts {
+			snapshot.AlienNames[name] = count
+		}
+	}
+
+	if planet.dayZeroCacheData != nil {
+		snapshot.DayZero = make(map[string][]string, len(planet.dayZeroCacheData))
+		for vertex, aliens := range planet.dayZeroCacheData {
+			snapshot.DayZero[vertex.Id] = append(make([]string, 0, len(aliens)), aliens...)
+		}
+	}
+
+	return snapshot
+}
+
+// Restore rebuilds a planet from a snapshot, the randomizer must be at the same point it was when the snapshot was taken
+// for the invasion to continue exactly as the original one, and so must be the options.
+// The garrisons, city attributes and directions are the ones of the snapshot, WithGarrisons, WithRandomGarrisons,
+// WithCityAttributes and WithDirections are ignored. The species mix is the one of the snapshot unless WithSpeciesMix
+// replaces it.
+func Restore(snapshot Snapshot, randomizer *rand.Rand, opts ...Option) (*Planet, error) {
+	directions, err := restoreDirections(snapshot.Directions)
+	if err != nil {
+		return nil, err
+	}
+
+	p := Planet{
+		Aliens:           make(map[string]*Alien, len(snapshot.Aliens)),
+		Garrisons:        make(map[string]*Garrison, len(snapshot.Garrisons)),
+		randomizer:       randomizer,
+		day:              snapshot.Day,
+		nameCounts:       make(map[string]int, len(snapshot.AlienNames)),
+		movement:         UniformMovement{},
+		battle:           AnnihilationBattle{},
+		defenderMovement: HoldMovement{},
+	}
+
+	species := make(map[string]Species, len(snapshot.Species))
+	for name, speciesSnapshot := range snapshot.Species {
+		movement, err := speciesSnapshot.movement()
+		if err != nil {
+			return nil, err
+		}
+
+		species[name] = Species{Name: name, Movement: movement, Speed: speciesSnapshot.Speed, Strength: speciesSnapshot.Strength}
+	}
+
+	for _, share := range snapshot.SpeciesMix {
+		shareSpecies, known := species[share.Species]
+		if !known {
+			return nil, fmt.Errorf("%w: %q of the species mix", ErrUnknownSpecies, share.Species)
+		}
+
+		p.speciesMix = append(p.speciesMix, SpeciesShare{Species: shareSpecies, Weight: share.Weight})
+	}
+
+	for _, opt := range opts {
+		opt.apply(&p)
+	}
+
+	p.directions = directions
+
+	for name, count := range snapshot.AlienNames {
+		p.nameCounts[name] = count
+	}
+
+	citiesAndAdjacent := make(map[string]map[Direction]string, len(snapshot.Cities))
+	p.cityAttributes = make(map[string]CityAttributes)
+
+	for _, city := range snapshot.Cities {
+		citiesAndAdjacent[city.Name] = city.Roads
+
+		if city.Population == 0 && city.Defense == 0 && city.Terrain == "" {
+			continue
+		}
+
+		terrain, err := ParseTerrain(string(city.Terrain))
+		if err != nil {
+			return nil, fmt.Errorf("city %q: %w", city.Name, err)
+		}
+
+		p.cityAttributes[city.Name] = CityAttributes{Population: city.Population, Defense: city.Defense, Terrain: terrain}
+	}
+
+	if _, err := p.buildGraph(citiesAndAdjacent); err != nil {
+		return nil, err
+	}
+
+	for _, city := range snapshot.Cities {
+		if city.Destroyed {
+			p.graph.GetVertex(city.Name).Disable()
+		}
+	}
+
+	for alien, cityName := range snapshot.Aliens {
+		city := p.graph.GetVertex(cityName)
+		if city == nil {
+			return nil, fmt.Errorf("%w: alien %q is at %q", datastructure.ErrVertexNotFound, alien, cityName)
+		}
+
+		p.Aliens[alien] = &Alien{Name: alien, Species: DefaultSpecies, City: city, lastDirection: Stay}
+
+		if direction, moved := snapshot.LastDirections[alien]; moved {
+			p.Aliens[alien].lastDirection = direction
+		}
+
+		if speciesName, exists := snapshot.AlienSpecies[alien]; exists {
+			alienSpecies, known := species[speciesName]
+			if !known {
+				return nil, fmt.Errorf("%w: alien %q is a %q", ErrUnknownSpecies, alien, speciesName)
+			}
+
+			p.Aliens[alien].Species = alienSpecies
+		}
+	}
+
+	for _, garrison := range snapshot.Garrisons {
+		city := p.graph.GetVertex(garrison.City)
+		if city == nil {
+			return nil, fmt.Errorf("%w: garrison %q is at %q", datastructure.ErrVertexNotFound, garrison.Name, garrison.City)
+		}
+
+		p.Garrisons[garrison.Name] = &Garrison{Name: garrison.Name, City: city, Strength: garrison.Strength}
+	}
+
+	if err := p.checkWaves(); err != nil {
+		return nil, err
+	}
+
+	if snapshot.DayZero != nil {
+		p.dayZeroCacheData = make(map[*datastructure.Vertex][]string, len(snapshot.DayZero))
+		for cityName, aliens := range snapshot.DayZero {
+			city := p.graph.GetVertex(cityName)
+			if city == nil {
+				return nil, fmt.Errorf("%w: %q", datastructure.ErrVertexNotFound, cityName)
+			}
+
+			p.dayZeroCacheData[city] = append(make([]string, 0, len(aliens)), aliens...)
+		}
+	}
+
+	return &p, nil
+}
+
+// restoreDirections registers the free-form directions of a snapshot in the order of their values,
+// so each one gets the value it had when the snapshot was taken.
+func restoreDirections(snapshots []DirectionSnapshot) (*Directions, error) {
+	directions := NewDirections()
+
+	for i, directionSnapshot := range snapshots {
+		// The inverse of a direction is registered along with it
+		if _, known := directions.Lookup(directionSnapshot.Name); !known {
+			if _, err := directions.Register(directionSnapshot.Name, directionSnapshot.Inverse); err != nil {
+				return nil, err
+			}
+		}
+
+		if direction, _ := directions.Lookup(directionSnapshot.Name); direction != _builtinDirections+i {
+			return nil, fmt.Errorf("%w: %q is out of the order of the directions", ErrDirectionConflict, directionSnapshot.Name)
+		}
+	}
+
+	return directions, nil
+}
+
+func newSpeciesSnapshot(species Species) SpeciesSnapshot {
+	speciesSnapshot := SpeciesSnapshot{Speed: species.Speed, Strength: species.Strength}
+	if species.Movement != nil {
+		speciesSnapshot.Movement = species.Movement.String()
+	}
+
+	return speciesSnapshot
+}
+
+func (speciesSnapshot SpeciesSnapshot) movement() (MovementStrategy, error) {
+	if speciesSnapshot.Movement == "" {
+		return nil, nil
+	}
+
+	return ParseMovement(speciesSnapshot.Movement)
+}
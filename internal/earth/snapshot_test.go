@@ -0,0 +1,63 @@
+package earth
+
+import (
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+func TestPlanet_SnapshotRestore(t *testing.T) {
+	layout := map[string]map[Direction]string{
+		"A": {East: "B", South: "C"},
+		"B": {West: "A", South: "D"},
+		"C": {North: "A", East: "D"},
+		"D": {North: "B", West: "C"},
+	}
+
+	planet, err := New(layout, 3, rand.New(rand.NewSource(11)))
+	if err != nil {
+		t.Fatalf("error while creating the planet: %v", err)
+	}
+
+	// Day zero hasn't passed yet, so its battles must be part of the snapshot
+	snapshot := planet.Snapshot()
+	if snapshot.DayZero == nil {
+		t.Fatalf("day zero battles should be part of the snapshot")
+	}
+
+	planet.graph.GetVertex("D").Disable()
+	snapshot = planet.Snapshot()
+
+	restored, err := Restore(snapshot, rand.New(rand.NewSource(5)))
+	if err != nil {
+		t.Fatalf("error while restoring the planet: %v", err)
+	}
+
+	if !reflect.DeepEqual(restored.Snapshot(), snapshot) {
+		t.Errorf("restored planet snapshot = %v, expected %v", restored.Snapshot(), snapshot)
+	}
+
+	if !restored.CityDestroyed("D") {
+		t.Errorf("D should still be destroyed after restoring")
+	}
+
+	// Both planets must evolve the same way when their randomizers are at the same point
+	planet.randomizer = rand.New(rand.NewSource(5))
+	for i := 0; i < 10; i++ {
+		expected, actual := planet.NextDay(), restored.NextDay()
+		if !reflect.DeepEqual(expected, actual) {
+			t.Fatalf("day %d: restored planet report = %v, expected %v", i, actual, expected)
+		}
+	}
+}
+
+func TestRestore_UnknownCity(t *testing.T) {
+	_, err := Restore(Snapshot{
+		Cities: []CitySnapshot{{Name: "A", Roads: map[Direction]string{}}},
+		Aliens: map[string]string{"Zorg on": "B"},
+	}, rand.New(rand.NewSource(0)))
+
+	if err == nil {
+		t.Errorf("restoring an alien in an unknown city should fail")
+	}
+}
@@ -0,0 +1,126 @@
+package earth
+
+import (
+	"errors"
+	"math/rand"
+	"sort"
+
+	"github.com/jattento/alien-invasion-simulator/internal/platform/datastructure"
+)
+
+// Alien is a single invader and the city where it is.
+type Alien struct {
+	Name    string
+	Species Species
+	City    *datastructure.Vertex
+
+	// lastDirection is the last road taken, Stay if the alien didn't move the previous day or just spawned
+	lastDirection Direction
+}
+
+// Species are the stats shared by every alien of a kind.
+type Species struct {
+	Name string
+
+	// Movement is how the species picks its roads, nil uses the planet movement.
+	Movement MovementStrategy
+
+	// Speed is the amount of roads an alien can take per day.
+	Speed int
+
+	// Strength is used by the battle rules to decide who wins a fight.
+	Strength int
+}
+
+var ErrUnknownSpecies = errors.New("unknown species")
+
+// DefaultSpecies is the species of every alien when the planet has no species mix,
+// they behave as aliens always did.
+var DefaultSpecies = Species{Name: "common", Speed: 1, Strength: 1}
+
+// BuiltinSpecies returns the species available without a species file, by name.
+func BuiltinSpecies() map[string]Species {
+	return map[string]Species{
+		DefaultSpecies.Name: DefaultSpecies,
+		"scout":             {Name: "scout", Movement: RandomWalkMovement{}, Speed: 2, Strength: 1},
+		"brute":             {Name: "brute", Movement: MomentumMovement{Persistence: _defaultPersistence}, Speed: 1, Strength: 3},
+		"hive":              {Name: "hive", Movement: LazyMovement{StayProbability: 0.8}, Speed: 1, Strength: 2},
+	}
+}
+
+// SpeciesShare is the weight of a species in the mix of a planet,
+// a species with weight 2 has twice the aliens of one with weight 1.
+type SpeciesShare struct {
+	Species Species
+	Weight  int
+}
+
+// assignSpecies returns the species of each of the aliens. The amount of aliens of each species
+// follows the weights as close as possible, and which alien gets which species is random.
+func assignSpecies(aliensAmount int, mix []SpeciesShare, randomizer *rand.Rand) []Species {
+	species := make([]Species, 0, aliensAmount)
+
+	totalWeight := 0
+	for _, share := range mix {
+		totalWeight += share.Weight
+	}
+
+	if totalWeight <= 0 {
+		for i := 0; i < aliensAmount; i++ {
+			species = append(species, DefaultSpecies)
+		}
+
+		return species
+	}
+
+	// Largest remainder method, so the amounts always add up to aliensAmount
+	amounts := make([]int, len(mix))
+	remainders := make([]int, len(mix))
+	assigned := 0
+
+	for i, share := range mix {
+		amounts[i] = aliensAmount * share.Weight / totalWeight
+		remainders[i] = aliensAmount * share.Weight % totalWeight
+		assigned += amounts[i]
+	}
+
+	order := make([]int, len(mix))
+	for i := range order {
+		order[i] = i
+	}
+
+	sort.SliceStable(order, func(i, j int) bool { return remainders[order[i]] > remainders[order[j]] })
+
+	for i := 0; assigned < aliensAmount; i++ {
+		amounts[order[i%len(order)]]++
+		assigned++
+	}
+
+	for i, share := range mix {
+		for j := 0; j < amounts[i]; j++ {
+			species = append(species, share.Species)
+		}
+	}
+
+	randomizer.Shuffle(len(species), func(i, j int) { species[i], species[j] = species[j], species[i] })
+
+	return species
+}
+
+// speed returns how many roads the alien can take per day, at least one.
+func (alien *Alien) speed() int {
+	if alien.Species.Speed < 1 {
+		return 1
+	}
+
+	return alien.Species.Speed
+}
+
+// strength returns how strong the alien is in battle, at least one.
+func (alien *Alien) strength() int {
+	if alien.Species.Strength < 1 {
+		return 1
+	}
+
+	return alien.Species.Strength
+}
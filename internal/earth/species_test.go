@@ -0,0 +1,137 @@
+package earth
+
+import (
+	"errors"
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+func TestAssignSpecies(t *testing.T) {
+	scout, brute := Species{Name: "scout", Speed: 2}, Species{Name: "brute", Strength: 3}
+
+	species := assignSpecies(10, []SpeciesShare{{Species: scout, Weight: 2}, {Species: brute, Weight: 1}}, rand.New(rand.NewSource(0)))
+
+	counts := make(map[string]int)
+	for _, s := range species {
+		counts[s.Name]++
+	}
+
+	// 10 * 2/3 = 6.67 and 10 * 1/3 = 3.33, the largest remainder gets the extra alien
+	if expected := map[string]int{"scout": 7, "brute": 3}; !reflect.DeepEqual(counts, expected) {
+		t.Errorf("assignSpecies() counts = %v, expected %v", counts, expected)
+	}
+
+	for _, s := range assignSpecies(3, nil, rand.New(rand.NewSource(0))) {
+		if s != DefaultSpecies {
+			t.Errorf("aliens without a mix should be %v, got %v", DefaultSpecies, s)
+		}
+	}
+}
+
+func TestNew_SpeciesMix(t *testing.T) {
+	layout := map[string]map[Direction]string{
+		"A": {East: "B"},
+		"B": {West: "A", East: "C"},
+		"C": {West: "B", East: "D"},
+		"D": {West: "C"},
+	}
+
+	// The mix must not change where aliens spawn
+	withoutMix, err := New(layout, 2, rand.New(rand.NewSource(3)))
+	if err != nil {
+		t.Fatalf("error while creating the planet: %v", err)
+	}
+
+	scout := Species{Name: "scout", Movement: RandomWalkMovement{}, Speed: 3, Strength: 1}
+	planet, err := New(layout, 2, rand.New(rand.NewSource(3)), WithSpeciesMix([]SpeciesShare{{Species: scout, Weight: 1}}))
+	if err != nil {
+		t.Fatalf("error while creating the planet: %v", err)
+	}
+
+	for name, alien := range planet.Aliens {
+		if alien.Species != scout {
+			t.Errorf("alien %s should be a scout, got %v", name, alien.Species)
+		}
+
+		if alien.City != nil && withoutMix.Aliens[name].City.Id != alien.City.Id {
+			t.Errorf("alien %s should spawn at %s, got %s", name, withoutMix.Aliens[name].City.Id, alien.City.Id)
+		}
+	}
+
+	reports := []DayReport{planet.NextDay(), planet.NextDay()}
+	for _, battle := range reports[0].Battles {
+		if !reflect.DeepEqual(battle.Species, []string{"scout", "scout"}) {
+			t.Errorf("battle species = %v, expected both scouts", battle.Species)
+		}
+	}
+
+	// Scouts walk three roads per day, so there is a movement for each road
+	steps := make(map[string]int)
+	for _, movement := range reports[1].Movements {
+		if movement.Stayed {
+			t.Errorf("scouts never stay, but %s did", movement.Alien)
+		}
+
+		steps[movement.Alien]++
+	}
+
+	for alien, amount := range steps {
+		if amount != 3 {
+			t.Errorf("alien %s took %d roads, expected 3", alien, amount)
+		}
+	}
+}
+
+func TestPlanet_SnapshotRestore_Species(t *testing.T) {
+	layout := map[string]map[Direction]string{
+		"A": {East: "B"},
+		"B": {West: "A"},
+	}
+
+	hive := Species{Name: "hive", Movement: LazyMovement{StayProbability: 0.8}, Speed: 1, Strength: 2}
+	planet, err := New(layout, 2, rand.New(rand.NewSource(0)), WithSpeciesMix([]SpeciesShare{
+		{Species: hive, Weight: 1},
+		{Species: DefaultSpecies, Weight: 1},
+	}))
+	if err != nil {
+		t.Fatalf("error while creating the planet: %v", err)
+	}
+
+	snapshot := planet.Snapshot()
+	if len(snapshot.AlienSpecies) != 1 || snapshot.Species["hive"] != (SpeciesSnapshot{Movement: "lazy:0.8", Speed: 1, Strength: 2}) {
+		t.Fatalf("only the hive alien should be in the snapshot species, got %v and %v", snapshot.AlienSpecies, snapshot.Species)
+	}
+
+	expectedMix := []SpeciesShareSnapshot{{Species: "hive", Weight: 1}, {Species: "common", Weight: 1}}
+	if !reflect.DeepEqual(snapshot.SpeciesMix, expectedMix) {
+		t.Errorf("Snapshot().SpeciesMix = %v, expected %v", snapshot.SpeciesMix, expectedMix)
+	}
+
+	restored, err := Restore(snapshot, rand.New(rand.NewSource(0)))
+	if err != nil {
+		t.Fatalf("error while restoring the planet: %v", err)
+	}
+
+	for name, alien := range planet.Aliens {
+		if restored.Aliens[name].Species != alien.Species {
+			t.Errorf("restored alien %s species = %v, expected %v", name, restored.Aliens[name].Species, alien.Species)
+		}
+	}
+
+	// The next waves follow the same mix, unless another one is set
+	if !reflect.DeepEqual(restored.speciesMix, planet.speciesMix) {
+		t.Errorf("restored species mix = %v, expected %v", restored.speciesMix, planet.speciesMix)
+	}
+
+	mix := []SpeciesShare{{Species: DefaultSpecies, Weight: 1}}
+	if restored, err := Restore(snapshot, rand.New(rand.NewSource(0)), WithSpeciesMix(mix)); err != nil ||
+		!reflect.DeepEqual(restored.speciesMix, mix) {
+		t.Errorf("Restore() with a species mix = %v, %v, expected the mix %v", restored, err, mix)
+	}
+
+	snapshot.SpeciesMix = []SpeciesShareSnapshot{{Species: "brute", Weight: 1}}
+	if _, err := Restore(snapshot, rand.New(rand.NewSource(0))); !errors.Is(err, ErrUnknownSpecies) {
+		t.Errorf("Restore() should return %v, but returned %v", ErrUnknownSpecies, err)
+	}
+}
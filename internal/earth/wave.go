@@ -0,0 +1,164 @@
+package earth
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/jattento/alien-invasion-simulator/internal/platform/datastructure"
+)
+
+// Wave is a group of aliens landing during the invasion, at the day Tick.
+// Day zero is the day of the first battles, so a wave at 0 lands with the aliens spawned by New.
+type Wave struct {
+	Aliens    int
+	Tick      int
+	Placement PlacementStrategy
+}
+
+// WaveReport describes the aliens of a wave that landed, Cities and Species are in the same order as Aliens.
+type WaveReport struct {
+	Aliens  []string
+	Cities  []string
+	Species []string
+}
+
+var ErrInvalidWave = errors.New("invalid wave")
+
+// ParseWave returns the wave described by spec, which is <aliens>@<tick> optionally followed by :<placement>,
+// see ParsePlacement. The aliens of waves without placement land at random cities.
+func ParseWave(spec string) (Wave, error) {
+	schedule, placementSpec, _ := strings.Cut(spec, ":")
+
+	aliensText, tickText, hasTick := strings.Cut(schedule, "@")
+	if !hasTick {
+		return Wave{}, fmt.Errorf("%w: %q, must be <aliens>@<tick>[:placement]", ErrInvalidWave, spec)
+	}
+
+	aliens, err := strconv.Atoi(aliensText)
+	if err != nil || aliens < 1 {
+		return Wave{}, fmt.Errorf("%w: %q aliens must be an amount of at least 1", ErrInvalidWave, spec)
+	}
+
+	tick, err := strconv.Atoi(tickText)
+	if err != nil || tick < 0 {
+		return Wave{}, fmt.Errorf("%w: %q tick must be a day of at least 0", ErrInvalidWave, spec)
+	}
+
+	placement, err := ParsePlacement(placementSpec)
+	if err != nil {
+		return Wave{}, fmt.Errorf("%w: %q: %v", ErrInvalidWave, spec, err)
+	}
+
+	return Wave{Aliens: aliens, Tick: tick, Placement: placement}, nil
+}
+
+// String returns the spec that ParseWave turns into this wave.
+func (wave Wave) String() string {
+	spec := fmt.Sprintf("%d@%d", wave.Aliens, wave.Tick)
+	if wave.Placement != nil && wave.Placement != (RandomPlacement{}) {
+		spec += ":" + wave.Placement.String()
+	}
+
+	return spec
+}
+
+// checkWaves returns an error if a wave lands at a city that doesn't exist.
+func (planet *Planet) checkWaves() error {
+	for _, wave := range planet.waves {
+		if err := planet.checkPlacement(wave.Placement, "wave "+wave.String()); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// AliensIncoming returns the amount of aliens of the waves that didn't land yet.
+func (planet *Planet) AliensIncoming() int {
+	incoming := 0
+	for _, wave := range planet.waves {
+		if wave.Tick >= planet.day {
+			incoming += wave.Aliens
+		}
+	}
+
+	return incoming
+}
+
+// land drops the aliens of the waves of the current day at the cities chosen by their placement,
+// adding them to citiesCache so they fight where they land.
+func (planet *Planet) land(citiesCache map[*datastructure.Vertex][]string) []WaveReport {
+	reports := make([]WaveReport, 0)
+
+	for _, wave := range planet.waves {
+		if wave.Tick != planet.day {
+			continue
+		}
+
+		standing := planet.standingCities()
+		if len(standing) == 0 {
+			continue
+		}
+
+		positions := make(map[string]*datastructure.Vertex, len(planet.Aliens))
+		for name, alien := range planet.Aliens {
+			positions[name] = alien.City
+		}
+
+		placement := wave.Placement
+		if placement == nil {
+			placement = RandomPlacement{}
+		}
+
+		cities := placement.Place(PlacementContext{
+			Cities:     standing,
+			Graph:      planet.graph,
+			Amount:     wave.Aliens,
+			Aliens:     positions,
+			Attributes: planet.cityAttributes,
+			Randomizer: planet.randomizer,
+		})
+
+		names := nextAlienNames(planet.nameCounts, planet.Aliens, len(cities), planet.randomizer)
+
+		species := make([]Species, len(names))
+		for i := range species {
+			species[i] = DefaultSpecies
+		}
+
+		if len(planet.speciesMix) > 0 {
+			species = assignSpecies(len(names), planet.speciesMix, planet.randomizer)
+		}
+
+		report := WaveReport{Aliens: names, Cities: make([]string, 0, len(names)), Species: make([]string, 0, len(names))}
+
+		for i, name := range names {
+			planet.Aliens[name] = &Alien{Name: name, Species: species[i], City: cities[i], lastDirection: Stay}
+			citiesCache[cities[i]] = append(citiesCache[cities[i]], name)
+
+			report.Cities = append(report.Cities, cities[i].Id)
+			report.Species = append(report.Species, species[i].Name)
+		}
+
+		reports = append(reports, report)
+	}
+
+	return reports
+}
+
+// standingCities returns the cities that weren't destroyed sorted by name.
+func (planet *Planet) standingCities() []*datastructure.Vertex {
+	cities := make([]*datastructure.Vertex, 0)
+	for _, city := range planet.graph.Vertices() {
+		if city.Enabled() {
+			cities = append(cities, city)
+		}
+	}
+
+	sort.Slice(cities, func(i, j int) bool { return cities[i].Id < cities[j].Id })
+
+	return cities
+}
@@ -0,0 +1,102 @@
+package earth
+
+import (
+	"errors"
+	"math/rand"
+	"testing"
+
+	"github.com/jattento/alien-invasion-simulator/internal/platform/datastructure"
+)
+
+func TestParseWave(t *testing.T) {
+	for _, spec := range []string{"20@0", "10@50:spread", "3@2:cities:A,B"} {
+		wave, err := ParseWave(spec)
+		if err != nil {
+			t.Fatalf("ParseWave(%q) error = %v", spec, err)
+		}
+
+		if wave.String() != spec {
+			t.Errorf("ParseWave(%q).String() = %q", spec, wave.String())
+		}
+	}
+
+	if wave, _ := ParseWave("5@1:random"); wave.String() != "5@1" {
+		t.Errorf("random waves should be written without placement, got %q", wave.String())
+	}
+
+	for _, spec := range []string{"20", "0@1", "x@1", "5@-1", "5@x", "5@1:nearest"} {
+		if _, err := ParseWave(spec); !errors.Is(err, ErrInvalidWave) {
+			t.Errorf("ParseWave(%q) error = %v, expected ErrInvalidWave", spec, err)
+		}
+	}
+}
+
+func TestPlanet_NextDay_Waves(t *testing.T) {
+	planet := line(t, WithMovement(HoldMovement{}), WithWaves([]Wave{
+		{Aliens: 1, Tick: 0, Placement: CitiesPlacement{Cities: []string{"A"}}},
+		{Aliens: 2, Tick: 2, Placement: CitiesPlacement{Cities: []string{"C", "D"}}},
+	}))
+
+	if incoming := planet.AliensIncoming(); incoming != 3 {
+		t.Errorf("AliensIncoming() = %d, expected 3", incoming)
+	}
+
+	report := planet.NextDay()
+	if len(report.Waves) != 1 || report.Waves[0].Cities[0] != "A" || report.Waves[0].Species[0] != DefaultSpecies.Name {
+		t.Fatalf("NextDay() waves at day zero = %+v, expected one alien at A", report.Waves)
+	}
+
+	if report = planet.NextDay(); len(report.Waves) != 0 {
+		t.Errorf("NextDay() waves at day 1 = %+v, expected none", report.Waves)
+	}
+
+	report = planet.NextDay()
+	if len(report.Waves) != 1 || len(report.Waves[0].Aliens) != 2 {
+		t.Fatalf("NextDay() waves at day 2 = %+v, expected two aliens", report.Waves)
+	}
+
+	if len(planet.Aliens) != 3 || planet.AliensIncoming() != 0 {
+		t.Errorf("every alien should have landed, got alive %v and incoming %d", planet.Aliens, planet.AliensIncoming())
+	}
+}
+
+func TestPlanet_NextDay_WavesFight(t *testing.T) {
+	// Both aliens land at B, where they fight the same day
+	planet := line(t, WithWaves([]Wave{{Aliens: 2, Tick: 0, Placement: CitiesPlacement{Cities: []string{"B"}}}}))
+
+	report := planet.NextDay()
+	if len(report.Battles) != 1 || report.Battles[0].City != "B" || !planet.CityDestroyed("B") {
+		t.Errorf("NextDay() battles = %+v, expected the aliens of the wave to destroy B", report.Battles)
+	}
+}
+
+func TestNew_Waves(t *testing.T) {
+	_, err := New(map[string]map[Direction]string{"A": {}}, 0, rand.New(rand.NewSource(0)),
+		WithWaves([]Wave{{Aliens: 1, Tick: 1, Placement: CitiesPlacement{Cities: []string{"Z"}}}}))
+	if !errors.Is(err, datastructure.ErrVertexNotFound) {
+		t.Errorf("New() error = %v, expected ErrVertexNotFound", err)
+	}
+}
+
+func TestNextAlienNames(t *testing.T) {
+	counts := make(map[string]int)
+	first := nextAlienNames(counts, nil, 50, rand.New(rand.NewSource(0)))
+
+	taken := make(map[string]*Alien, len(first))
+	for _, name := range first {
+		taken[name] = &Alien{Name: name}
+	}
+
+	// Same randomizer, so every name would be repeated without the counts
+	for _, name := range nextAlienNames(counts, nil, 50, rand.New(rand.NewSource(0))) {
+		if taken[name] != nil {
+			t.Errorf("nextAlienNames() repeated %q", name)
+		}
+	}
+
+	for _, name := range nextAlienNames(make(map[string]int), taken, 50, rand.New(rand.NewSource(0))) {
+		if taken[name] != nil {
+			t.Errorf("nextAlienNames() returned the taken name %q", name)
+		}
+	}
+}
@@ -15,11 +15,45 @@ type Manager struct {
 	DayCounterCh <-chan string
 	CitiesCh     <-chan []string
 
+	// LogsResetCh replaces every log shown, the last one of the slice is the most recent.
+	LogsResetCh <-chan []string
+
+	// CommandsCh enables the playback controls when set, each key pressed is sent through it.
+	CommandsCh chan<- Command
+
 	logs string
 
 	WaitTime int64
 }
 
+// Command is a playback control triggered by a key.
+type Command int
+
+const (
+	CommandPause Command = iota
+	CommandStepForward
+	CommandStepBackward
+	CommandJumpForward
+	CommandJumpBackward
+	CommandJumpStart
+	CommandJumpEnd
+)
+
+var _commandKeys = map[gobless.Key]Command{
+	gobless.KeyCtrlP: CommandPause,
+	gobless.KeyRight: CommandStepForward,
+	gobless.KeyLeft:  CommandStepBackward,
+	gobless.KeyPgDn:  CommandJumpForward,
+	gobless.KeyPgUp:  CommandJumpBackward,
+	gobless.KeyHome:  CommandJumpStart,
+	gobless.KeyEnd:   CommandJumpEnd,
+}
+
+const (
+	_controls         = "Control + Q: Close | Control + A: Time speed down | Control + S: Time speed up"
+	_playbackControls = " | Control + P: Pause | ← →: Step | PgUp PgDn: Jump 10 days | Home End: First and last day"
+)
+
 func New(output io.Writer, logsCh <-chan string, dayCounterCh <-chan string, citiesCh <-chan []string) *Manager {
 	return &Manager{
 		output:       output,
@@ -64,7 +98,12 @@ func (manager *Manager) Run() error {
 	dayCounterBox.SetText("Day: 0")
 
 	ControllerBox := gobless.NewTextBox()
-	ControllerBox.SetText("Control + Q: Close | Control + A: Time speed down | Control + S: Time speed up")
+	ControllerBox.SetTextWrap(true)
+	if manager.CommandsCh != nil {
+		ControllerBox.SetText(_controls + _playbackControls)
+	} else {
+		ControllerBox.SetText(_controls)
+	}
 
 	rows := []gobless.Component{
 		gobless.NewRow(
@@ -96,6 +135,15 @@ func (manager *Manager) Run() error {
 		atomic.AddInt64(&manager.WaitTime, atomic.LoadInt64(&manager.WaitTime)/3)
 	})
 
+	if manager.CommandsCh != nil {
+		for key, command := range _commandKeys {
+			command := command
+			gui.HandleKeyPress(key, func(event gobless.KeyPressEvent) {
+				manager.CommandsCh <- command
+			})
+		}
+	}
+
 	gui.Render(rows...)
 
 	go func() {
@@ -104,6 +152,14 @@ func (manager *Manager) Run() error {
 			case log := <-manager.LogsCh:
 				manager.logs = log + "\n" + manager.logs
 				logsBox.SetText(manager.logs)
+			case logs := <-manager.LogsResetCh:
+				var builder strings.Builder
+				for i := len(logs) - 1; i >= 0; i-- {
+					builder.WriteString(logs[i] + "\n")
+				}
+
+				manager.logs = builder.String()
+				logsBox.SetText(manager.logs)
 			case info := <-manager.DayCounterCh:
 				dayCounterBox.SetText(info)
 			case cities := <-manager.CitiesCh:
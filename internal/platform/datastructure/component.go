@@ -0,0 +1,276 @@
+package datastructure
+
+// Component is a group of enabled vertices connected by edges in any direction.
+type Component struct {
+	size int
+}
+
+// Size returns the amount of vertices of the component.
+func (component *Component) Size() int {
+	return component.size
+}
+
+// Components returns every component as its vertices, both the components and their vertices
+// in the order the vertices were added.
+func (graph *Graph) Components() [][]*Vertex {
+	graph.trackComponents()
+
+	// Component:Position in components
+	positions := make(map[*Component]int)
+	components := make([][]*Vertex, 0, graph.components.count)
+
+	for _, vertex := range graph.vertices {
+		if vertex.disabled {
+			continue
+		}
+
+		position, known := positions[vertex.component]
+		if !known {
+			position = len(components)
+			positions[vertex.component] = position
+			components = append(components, make([]*Vertex, 0, vertex.component.size))
+		}
+
+		components[position] = append(components[position], vertex)
+	}
+
+	return components
+}
+
+// ComponentOf returns the component of the vertex, nil if it is disabled.
+func (graph *Graph) ComponentOf(vertex *Vertex) *Component {
+	graph.trackComponents()
+
+	if vertex.disabled {
+		return nil
+	}
+
+	return vertex.component
+}
+
+// ComponentCount returns the amount of components.
+func (graph *Graph) ComponentCount() int {
+	return graph.trackComponents().count
+}
+
+// LargestComponent returns the size of the largest component, 0 if there is none.
+func (graph *Graph) LargestComponent() int {
+	return graph.trackComponents().largest
+}
+
+// componentTracker keeps the components up to date as vertices are disabled, they are found once and then only
+// the component of each disabled vertex is looked at again.
+type componentTracker struct {
+	count int
+
+	// Size:Amount of components of that size
+	sizes   map[int]int
+	largest int
+}
+
+// trackComponents finds every component the first time it is called, or after the graph grew.
+func (graph *Graph) trackComponents() *componentTracker {
+	if graph.components != nil {
+		return graph.components
+	}
+
+	tracker := &componentTracker{sizes: make(map[int]int)}
+
+	for _, vertex := range graph.vertices {
+		vertex.component = nil
+	}
+
+	for _, vertex := range graph.vertices {
+		if vertex.disabled || vertex.component != nil {
+			continue
+		}
+
+		component := &Component{}
+		vertex.component = component
+
+		members := []*Vertex{vertex}
+		for next := 0; next < len(members); next++ {
+			for _, neighbor := range members[next].neighbors() {
+				if neighbor.component != component {
+					neighbor.component = component
+					members = append(members, neighbor)
+				}
+			}
+		}
+
+		component.size = len(members)
+		tracker.add(component)
+	}
+
+	graph.components = tracker
+
+	return tracker
+}
+
+// remove updates the components after the vertex was disabled, the pieces its component splits into
+// become new components except for the last one, which keeps being the same component.
+func (tracker *componentTracker) remove(vertex *Vertex) {
+	component := vertex.component
+	vertex.component = nil
+
+	if component == nil {
+		return
+	}
+
+	tracker.drop(component)
+	component.size--
+
+	for _, piece := range splitOff(vertex.neighbors()) {
+		pieceComponent := &Component{size: len(piece)}
+		for _, member := range piece {
+			member.component = pieceComponent
+		}
+
+		component.size -= len(piece)
+		tracker.add(pieceComponent)
+	}
+
+	if component.size > 0 {
+		tracker.add(component)
+	}
+}
+
+func (tracker *componentTracker) add(component *Component) {
+	tracker.count++
+	tracker.sizes[component.size]++
+
+	if component.size > tracker.largest {
+		tracker.largest = component.size
+	}
+}
+
+func (tracker *componentTracker) drop(component *Component) {
+	tracker.count--
+
+	tracker.sizes[component.size]--
+	if tracker.sizes[component.size] == 0 {
+		delete(tracker.sizes, component.size)
+	}
+
+	// Components only shrink, so the largest size is only looked for downwards
+	for tracker.largest > 0 && tracker.sizes[tracker.largest] == 0 {
+		tracker.largest--
+	}
+}
+
+// splitOff returns the pieces a component splits into that don't include the last of them, given the neighbors
+// of the vertex that was removed from it. A search starts from every neighbor and all of them advance at the same
+// pace, merging when they meet, until a single one is left. The work done is proportional to the size
+// of the pieces that split off, instead of to the size of the whole component.
+func splitOff(neighbors []*Vertex) [][]*Vertex {
+	type search struct {
+		// Reached vertices, the ones from next on still have to be explored
+		vertices []*Vertex
+		next     int
+
+		// Index of the search it was merged into, itself if it wasn't
+		mergedInto int
+		finished   bool
+	}
+
+	// Vertex:Index of the search that reached it
+	owner := make(map[*Vertex]int, len(neighbors))
+	searches := make([]*search, 0, len(neighbors))
+
+	for _, neighbor := range neighbors {
+		if _, reached := owner[neighbor]; !reached {
+			owner[neighbor] = len(searches)
+			searches = append(searches, &search{vertices: []*Vertex{neighbor}, mergedInto: len(searches)})
+		}
+	}
+
+	root := func(i int) int {
+		for searches[i].mergedInto != i {
+			i = searches[i].mergedInto
+		}
+
+		return i
+	}
+
+	pieces := make([][]*Vertex, 0)
+
+	for running := len(searches); running > 1; {
+		for i, current := range searches {
+			if running <= 1 {
+				break
+			}
+
+			if current.mergedInto != i || current.finished {
+				continue
+			}
+
+			if current.next == len(current.vertices) {
+				current.finished = true
+				running--
+				pieces = append(pieces, current.vertices)
+
+				continue
+			}
+
+			explored := current.vertices[current.next]
+			current.next++
+
+			for _, neighbor := range explored.neighbors() {
+				other, reached := owner[neighbor]
+				if !reached {
+					owner[neighbor] = i
+					current.vertices = append(current.vertices, neighbor)
+
+					continue
+				}
+
+				if other = root(other); other == i {
+					continue
+				}
+
+				// Both searches are in the same piece, the explored vertices are kept first
+				merged := searches[other]
+				vertices := make([]*Vertex, 0, len(current.vertices)+len(merged.vertices))
+				vertices = append(vertices, current.vertices[:current.next]...)
+				vertices = append(vertices, merged.vertices[:merged.next]...)
+				vertices = append(vertices, current.vertices[current.next:]...)
+				vertices = append(vertices, merged.vertices[merged.next:]...)
+
+				current.next += merged.next
+				current.vertices = vertices
+				merged.mergedInto = i
+				running--
+			}
+		}
+	}
+
+	return pieces
+}
+
+// neighbors returns the enabled vertices with an edge from or to the vertex, a vertex can be returned more than once.
+func (vertex *Vertex) neighbors() []*Vertex {
+	neighbors := make([]*Vertex, 0, len(vertex.adjacent)+len(vertex.incoming))
+	for _, adjacent := range vertex.adjacent {
+		if !adjacent.disabled {
+			neighbors = append(neighbors, adjacent)
+		}
+	}
+
+	for _, incoming := range vertex.incoming {
+		if !incoming.disabled {
+			neighbors = append(neighbors, incoming)
+		}
+	}
+
+	return neighbors
+}
+
+// removeIncoming forgets one of the edges from the vertex from.
+func (vertex *Vertex) removeIncoming(from *Vertex) {
+	for i, incoming := range vertex.incoming {
+		if incoming == from {
+			vertex.incoming = append(vertex.incoming[:i], vertex.incoming[i+1:]...)
+			return
+		}
+	}
+}
@@ -0,0 +1,193 @@
+package datastructure
+
+import (
+	"math/rand"
+	"sort"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// componentIds returns the ids of the vertices of each component.
+func componentIds(components [][]*Vertex) [][]string {
+	ids := make([][]string, 0, len(components))
+	for _, component := range components {
+		componentIds := make([]string, 0, len(component))
+		for _, vertex := range component {
+			componentIds = append(componentIds, vertex.Id)
+		}
+
+		ids = append(ids, componentIds)
+	}
+
+	return ids
+}
+
+func TestGraph_Components(t *testing.T) {
+	graph := square(t)
+
+	// E only has an edge to A, but that is enough to be connected
+	assert.Equal(t, [][]string{{"A", "B", "C", "D", "E"}}, componentIds(graph.Components()))
+	assert.Equal(t, 1, graph.ComponentCount())
+	assert.Equal(t, 5, graph.LargestComponent())
+
+	// Test case: B and D are still connected through C
+	graph.GetVertex("A").Disable()
+	assert.Equal(t, [][]string{{"B", "C", "D"}, {"E"}}, componentIds(graph.Components()))
+	assert.Equal(t, 2, graph.ComponentCount())
+	assert.Equal(t, 3, graph.LargestComponent())
+	assert.Nil(t, graph.ComponentOf(graph.GetVertex("A")))
+	assert.Equal(t, 1, graph.ComponentOf(graph.GetVertex("E")).Size())
+
+	// Test case: the component splits
+	graph.GetVertex("C").Disable()
+	assert.Equal(t, [][]string{{"B"}, {"D"}, {"E"}}, componentIds(graph.Components()))
+	assert.Equal(t, 3, graph.ComponentCount())
+	assert.Equal(t, 1, graph.LargestComponent())
+	assert.NotSame(t, graph.ComponentOf(graph.GetVertex("B")), graph.ComponentOf(graph.GetVertex("D")))
+
+	// Test case: disabling twice changes nothing
+	graph.GetVertex("C").Disable()
+	assert.Equal(t, 3, graph.ComponentCount())
+
+	for _, id := range []string{"B", "D", "E"} {
+		graph.GetVertex(id).Disable()
+	}
+
+	assert.Empty(t, graph.Components())
+	assert.Equal(t, 0, graph.ComponentCount())
+	assert.Equal(t, 0, graph.LargestComponent())
+}
+
+func TestGraph_Components_GraphGrows(t *testing.T) {
+	graph := &Graph{}
+	_, err := graph.AddVertices([]string{"A", "B"})
+	require.NoError(t, err)
+	assert.Equal(t, 2, graph.ComponentCount())
+
+	require.NoError(t, graph.AddEdge(1, "A", "B"))
+	assert.Equal(t, 1, graph.ComponentCount())
+
+	_, err = graph.AddVertex("C")
+	require.NoError(t, err)
+	assert.Equal(t, [][]string{{"A", "B"}, {"C"}}, componentIds(graph.Components()))
+}
+
+func TestGraph_Components_Incremental(t *testing.T) {
+	randomizer := rand.New(rand.NewSource(1))
+
+	for i := 0; i < 50; i++ {
+		size := 2 + randomizer.Intn(60)
+		ids, _ := benchmarkEdges(size)
+
+		graph := &Graph{}
+		_, err := graph.AddVertices(ids)
+		require.NoError(t, err)
+
+		for edges := randomizer.Intn(size * 2); edges > 0; edges-- {
+			err := graph.AddEdge(randomizer.Intn(8), ids[randomizer.Intn(size)], ids[randomizer.Intn(size)])
+			if err != nil {
+				require.ErrorIs(t, err, ErrEdgeDuplicated)
+			}
+		}
+
+		// Components are tracked from before the first vertex is disabled
+		graph.ComponentCount()
+
+		for _, disabled := range randomizer.Perm(size) {
+			graph.GetVertex(ids[disabled]).Disable()
+
+			tracked := graph.Components()
+			assertSameComponents(t, tracked, graph)
+
+			sizes := make([]int, 0, len(tracked))
+			for _, component := range tracked {
+				sizes = append(sizes, len(component))
+			}
+			sort.Sort(sort.Reverse(sort.IntSlice(sizes)))
+
+			assert.Equal(t, len(tracked), graph.ComponentCount())
+			if len(sizes) > 0 {
+				assert.Equal(t, sizes[0], graph.LargestComponent())
+			}
+		}
+	}
+}
+
+// assertSameComponents checks that the components tracked are the ones found from scratch joining the enabled
+// vertices of every edge, and that their sizes are up to date.
+func assertSameComponents(t *testing.T, tracked [][]*Vertex, graph *Graph) {
+	group := make(map[*Vertex]*Vertex)
+	find := func(vertex *Vertex) *Vertex {
+		for group[vertex] != vertex {
+			vertex = group[vertex]
+		}
+
+		return vertex
+	}
+
+	for _, vertex := range graph.vertices {
+		group[vertex] = vertex
+	}
+
+	for _, vertex := range graph.vertices {
+		for _, adjacent := range vertex.adjacent {
+			if !vertex.disabled && !adjacent.disabled {
+				group[find(vertex)] = find(adjacent)
+			}
+		}
+	}
+
+	found := make([][]*Vertex, 0)
+	positions := make(map[*Vertex]int)
+	for _, vertex := range graph.vertices {
+		if vertex.disabled {
+			continue
+		}
+
+		position, known := positions[find(vertex)]
+		if !known {
+			position = len(found)
+			positions[find(vertex)] = position
+			found = append(found, nil)
+		}
+
+		found[position] = append(found[position], vertex)
+	}
+
+	require.Equal(t, componentIds(found), componentIds(tracked))
+
+	for _, component := range tracked {
+		assert.Equal(t, len(component), graph.ComponentOf(component[0]).Size())
+	}
+}
+
+func BenchmarkGraph_Components(b *testing.B) {
+	for _, size := range benchmarkSizes {
+		ids, edges := benchmarkEdges(size)
+
+		b.Run(strconv.Itoa(size), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				b.StopTimer()
+				graph := NewGraph(size)
+				if _, err := graph.AddVertices(ids); err != nil {
+					b.Fatal(err)
+				}
+
+				if err := graph.AddEdges(edges); err != nil {
+					b.Fatal(err)
+				}
+
+				b.StartTimer()
+
+				// The ring is split in 100 pieces one vertex at a time
+				graph.ComponentCount()
+				for vertex := 0; vertex < size; vertex += size / 100 {
+					graph.GetVertex(ids[vertex]).Disable()
+				}
+			}
+		})
+	}
+}
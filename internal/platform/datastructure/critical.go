@@ -0,0 +1,179 @@
+package datastructure
+
+import "sort"
+
+// ArticulationPoints returns the enabled vertices that would split their component if they were disabled,
+// in the order they were added.
+func (graph *Graph) ArticulationPoints() []*Vertex {
+	articulationPoints, _ := graph.criticalParts()
+
+	return articulationPoints
+}
+
+// Bridges returns the edges between enabled vertices that would split their component if they were removed,
+// along with the edge in the opposite direction, if there is one. Each bridge is given as the edge from the vertex
+// added first when it exists, and they are sorted by the order in which their vertices were added.
+func (graph *Graph) Bridges() []Edge {
+	_, bridges := graph.criticalParts()
+
+	return bridges
+}
+
+// criticalParts finds the articulation points and bridges with a depth first search over the enabled vertices,
+// as if every edge went in both directions. The search doesn't recurse so it can go through huge graphs.
+func (graph *Graph) criticalParts() ([]*Vertex, []Edge) {
+	vertices := make([]*Vertex, 0, len(graph.vertices))
+	position := make(map[*Vertex]int, len(graph.vertices))
+
+	for _, vertex := range graph.vertices {
+		if !vertex.disabled {
+			position[vertex] = len(vertices)
+			vertices = append(vertices, vertex)
+		}
+	}
+
+	// Position:Positions of its neighbors, without repeating them
+	neighbors := make([][]int, len(vertices))
+	for i, vertex := range vertices {
+		for _, neighbor := range vertex.neighbors() {
+			if j := position[neighbor]; j != i {
+				neighbors[i] = append(neighbors[i], j)
+			}
+		}
+
+		neighbors[i] = sortedUnique(neighbors[i])
+	}
+
+	var (
+		// Order in which each vertex was discovered, starting from 1, and the earliest one it can reach
+		// without going back through its parent
+		discovered = make([]int, len(vertices))
+		low        = make([]int, len(vertices))
+		parent     = make([]int, len(vertices))
+
+		isArticulationPoint = make([]bool, len(vertices))
+		bridges             = make([][2]int, 0)
+		time                = 0
+	)
+
+	type frame struct {
+		vertex, next int
+	}
+
+	for root := range vertices {
+		if discovered[root] != 0 {
+			continue
+		}
+
+		time++
+		discovered[root], low[root], parent[root] = time, time, -1
+		rootChildren := 0
+
+		for stack := []frame{{vertex: root}}; len(stack) > 0; {
+			top := &stack[len(stack)-1]
+			vertex := top.vertex
+
+			if top.next < len(neighbors[vertex]) {
+				neighbor := neighbors[vertex][top.next]
+				top.next++
+
+				switch {
+				case discovered[neighbor] == 0:
+					time++
+					discovered[neighbor], low[neighbor], parent[neighbor] = time, time, vertex
+					stack = append(stack, frame{vertex: neighbor})
+
+					if vertex == root {
+						rootChildren++
+					}
+				case neighbor != parent[vertex] && discovered[neighbor] < low[vertex]:
+					low[vertex] = discovered[neighbor]
+				}
+
+				continue
+			}
+
+			stack = stack[:len(stack)-1]
+
+			from := parent[vertex]
+			if from < 0 {
+				continue
+			}
+
+			if low[vertex] < low[from] {
+				low[from] = low[vertex]
+			}
+
+			if low[vertex] > discovered[from] {
+				bridges = append(bridges, [2]int{from, vertex})
+			}
+
+			if from != root && low[vertex] >= discovered[from] {
+				isArticulationPoint[from] = true
+			}
+		}
+
+		if rootChildren > 1 {
+			isArticulationPoint[root] = true
+		}
+	}
+
+	articulationPoints := make([]*Vertex, 0)
+	for i, vertex := range vertices {
+		if isArticulationPoint[i] {
+			articulationPoints = append(articulationPoints, vertex)
+		}
+	}
+
+	for i, bridge := range bridges {
+		if bridge[0] > bridge[1] {
+			bridges[i] = [2]int{bridge[1], bridge[0]}
+		}
+	}
+
+	sort.Slice(bridges, func(i, j int) bool {
+		if bridges[i][0] != bridges[j][0] {
+			return bridges[i][0] < bridges[j][0]
+		}
+
+		return bridges[i][1] < bridges[j][1]
+	})
+
+	bridgeEdges := make([]Edge, 0, len(bridges))
+	for _, bridge := range bridges {
+		from, to := vertices[bridge[0]], vertices[bridge[1]]
+		if _, exists := from.edgeTo(to); !exists {
+			from, to = to, from
+		}
+
+		edgeId, _ := from.edgeTo(to)
+		bridgeEdges = append(bridgeEdges, Edge{Id: edgeId, From: from.Id, To: to.Id})
+	}
+
+	return articulationPoints, bridgeEdges
+}
+
+// edgeTo returns the id of the edge from the vertex to another, and whether there is one.
+func (vertex *Vertex) edgeTo(to *Vertex) (int, bool) {
+	for edgeId, adjacent := range vertex.adjacent {
+		if adjacent == to {
+			return edgeId, true
+		}
+	}
+
+	return 0, false
+}
+
+// sortedUnique sorts the values and removes the repeated ones, reusing the same slice.
+func sortedUnique(values []int) []int {
+	sort.Ints(values)
+
+	unique := values[:0]
+	for _, value := range values {
+		if len(unique) == 0 || value != unique[len(unique)-1] {
+			unique = append(unique, value)
+		}
+	}
+
+	return unique
+}
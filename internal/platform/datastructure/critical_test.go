@@ -0,0 +1,108 @@
+package datastructure
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func vertexIds(vertices []*Vertex) []string {
+	ids := make([]string, 0, len(vertices))
+	for _, vertex := range vertices {
+		ids = append(ids, vertex.Id)
+	}
+
+	return ids
+}
+
+// bowtie returns the triangles A-B-C and C-D-E joined at C, with F hanging from E and G alone.
+// Every edge goes in both directions except the one between E and F.
+func bowtie(t *testing.T) *Graph {
+	graph := &Graph{}
+
+	_, err := graph.AddVertices([]string{"A", "B", "C", "D", "E", "F", "G"})
+	require.NoError(t, err)
+
+	edges := make([]Edge, 0)
+	for _, pair := range [][2]string{{"A", "B"}, {"B", "C"}, {"C", "A"}, {"C", "D"}, {"D", "E"}, {"E", "C"}} {
+		edges = append(edges, Edge{Id: 1, From: pair[0], To: pair[1]}, Edge{Id: 2, From: pair[1], To: pair[0]})
+	}
+
+	require.NoError(t, graph.AddEdges(append(edges, Edge{Id: 3, From: "F", To: "E"})))
+
+	return graph
+}
+
+func TestGraph_ArticulationPoints(t *testing.T) {
+	graph := bowtie(t)
+	assert.Equal(t, []string{"C", "E"}, vertexIds(graph.ArticulationPoints()))
+
+	// Test case: without D, E only hangs from C
+	graph.GetVertex("D").Disable()
+	assert.Equal(t, []string{"C", "E"}, vertexIds(graph.ArticulationPoints()))
+
+	graph.GetVertex("F").Disable()
+	assert.Equal(t, []string{"C"}, vertexIds(graph.ArticulationPoints()))
+
+	assert.Empty(t, new(Graph).ArticulationPoints())
+}
+
+func TestGraph_Bridges(t *testing.T) {
+	graph := bowtie(t)
+
+	// The edge between E and F only goes from F
+	assert.Equal(t, []Edge{{Id: 3, From: "F", To: "E"}}, graph.Bridges())
+
+	graph.GetVertex("D").Disable()
+	assert.Equal(t, []Edge{{Id: 2, From: "C", To: "E"}, {Id: 3, From: "F", To: "E"}}, graph.Bridges())
+
+	// Test case: a line is only made of bridges
+	graph = &Graph{}
+	_, err := graph.AddVertices([]string{"A", "B", "C"})
+	require.NoError(t, err)
+	require.NoError(t, graph.AddEdges([]Edge{{Id: 1, From: "A", To: "B"}, {Id: 1, From: "B", To: "C"}, {Id: 2, From: "C", To: "B"}}))
+
+	assert.Equal(t, []Edge{{Id: 1, From: "A", To: "B"}, {Id: 1, From: "B", To: "C"}}, graph.Bridges())
+	assert.Equal(t, []string{"B"}, vertexIds(graph.ArticulationPoints()))
+}
+
+func TestGraph_ArticulationPoints_Disabled(t *testing.T) {
+	// Disabling an articulation point splits its component, disabling any other vertex doesn't
+	for _, id := range []string{"A", "B", "C", "D", "E", "F"} {
+		graph := bowtie(t)
+		critical := vertexIds(graph.ArticulationPoints())
+		components := graph.ComponentCount()
+
+		graph.GetVertex(id).Disable()
+
+		isCritical := false
+		for _, criticalId := range critical {
+			isCritical = isCritical || criticalId == id
+		}
+
+		assert.Equal(t, isCritical, graph.ComponentCount() > components, id)
+	}
+}
+
+func BenchmarkGraph_ArticulationPoints(b *testing.B) {
+	for _, size := range benchmarkSizes {
+		ids, edges := benchmarkEdges(size)
+
+		graph := NewGraph(size)
+		if _, err := graph.AddVertices(ids); err != nil {
+			b.Fatal(err)
+		}
+
+		if err := graph.AddEdges(edges); err != nil {
+			b.Fatal(err)
+		}
+
+		b.Run(strconv.Itoa(size), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				graph.ArticulationPoints()
+			}
+		})
+	}
+}
@@ -0,0 +1,105 @@
+package datastructure
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// DOTAttributes are Graphviz attributes, they are written sorted by name.
+type DOTAttributes = map[string]string
+
+// DOTStyle customizes how a graph is written in the Graphviz DOT language, every field is optional.
+type DOTStyle struct {
+	Name string
+
+	Graph DOTAttributes
+	Node  DOTAttributes
+
+	VertexAttributes func(vertex *Vertex) DOTAttributes
+
+	// EdgeAttributes is called once for each pair of connected vertices,
+	// with the edge of the vertex that was added first.
+	EdgeAttributes func(from *Vertex, edgeId int, to *Vertex) DOTAttributes
+}
+
+// WriteDOT writes the graph as an undirected Graphviz graph, each pair of connected vertices
+// is written as a single edge. Disabled vertices and their edges are included.
+func (graph *Graph) WriteDOT(output io.Writer, style DOTStyle) error {
+	writer := bufio.NewWriter(output)
+
+	fmt.Fprintf(writer, "graph %s {\n", strconv.Quote(style.Name))
+
+	for _, name := range sortedAttributeNames(style.Graph) {
+		fmt.Fprintf(writer, "  %s=%s\n", name, strconv.Quote(style.Graph[name]))
+	}
+
+	if len(style.Node) > 0 {
+		fmt.Fprintf(writer, "  node%s\n", formatDOTAttributes(style.Node))
+	}
+
+	for _, vertex := range graph.vertices {
+		var attributes DOTAttributes
+		if style.VertexAttributes != nil {
+			attributes = style.VertexAttributes(vertex)
+		}
+
+		fmt.Fprintf(writer, "  %s%s\n", strconv.Quote(vertex.Id), formatDOTAttributes(attributes))
+	}
+
+	written := make(map[[2]*Vertex]bool)
+
+	for _, vertex := range graph.vertices {
+		edgeIds := make([]int, 0, len(vertex.adjacent))
+		for edgeId := range vertex.adjacent {
+			edgeIds = append(edgeIds, edgeId)
+		}
+		sort.Ints(edgeIds)
+
+		for _, edgeId := range edgeIds {
+			adjacent := vertex.adjacent[edgeId]
+			if written[[2]*Vertex{adjacent, vertex}] || written[[2]*Vertex{vertex, adjacent}] {
+				continue
+			}
+
+			written[[2]*Vertex{vertex, adjacent}] = true
+
+			var attributes DOTAttributes
+			if style.EdgeAttributes != nil {
+				attributes = style.EdgeAttributes(vertex, edgeId, adjacent)
+			}
+
+			fmt.Fprintf(writer, "  %s -- %s%s\n", strconv.Quote(vertex.Id), strconv.Quote(adjacent.Id), formatDOTAttributes(attributes))
+		}
+	}
+
+	fmt.Fprintln(writer, "}")
+
+	return writer.Flush()
+}
+
+func formatDOTAttributes(attributes DOTAttributes) string {
+	if len(attributes) == 0 {
+		return ""
+	}
+
+	pairs := make([]string, 0, len(attributes))
+	for _, name := range sortedAttributeNames(attributes) {
+		pairs = append(pairs, name+"="+strconv.Quote(attributes[name]))
+	}
+
+	return " [" + strings.Join(pairs, ", ") + "]"
+}
+
+func sortedAttributeNames(attributes DOTAttributes) []string {
+	names := make([]string, 0, len(attributes))
+	for name := range attributes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
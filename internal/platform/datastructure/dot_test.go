@@ -0,0 +1,61 @@
+package datastructure
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGraphWriteDOT(t *testing.T) {
+	graph := new(Graph)
+	for _, id := range []string{"A", "B", "C"} {
+		if _, err := graph.AddVertex(id); err != nil {
+			t.Fatalf("AddVertex(%q) error: %v", id, err)
+		}
+	}
+
+	for _, edge := range []struct {
+		id       int
+		from, to string
+	}{{1, "A", "B"}, {3, "B", "A"}, {2, "B", "C"}} {
+		if err := graph.AddEdge(edge.id, edge.from, edge.to); err != nil {
+			t.Fatalf("AddEdge(%d, %q, %q) error: %v", edge.id, edge.from, edge.to, err)
+		}
+	}
+
+	graph.GetVertex("C").Disable()
+
+	var output strings.Builder
+	err := graph.WriteDOT(&output, DOTStyle{
+		Name:  "test",
+		Graph: DOTAttributes{"layout": "neato"},
+		Node:  DOTAttributes{"shape": "box"},
+		VertexAttributes: func(vertex *Vertex) DOTAttributes {
+			if !vertex.Enabled() {
+				return DOTAttributes{"style": "dashed"}
+			}
+
+			return nil
+		},
+		EdgeAttributes: func(from *Vertex, edgeId int, to *Vertex) DOTAttributes {
+			return DOTAttributes{"label": from.Id + string(rune('0'+edgeId)) + to.Id}
+		},
+	})
+	if err != nil {
+		t.Fatalf("WriteDOT() error: %v", err)
+	}
+
+	// The A-B pair is written once, with the edge of A since it was added first
+	expected := `graph "test" {
+  layout="neato"
+  node [shape="box"]
+  "A"
+  "B"
+  "C" [style="dashed"]
+  "A" -- "B" [label="A1B"]
+  "B" -- "C" [label="B2C"]
+}
+`
+	if output.String() != expected {
+		t.Errorf("WriteDOT() = %s, expected %s", output.String(), expected)
+	}
+}
@@ -3,12 +3,32 @@ package datastructure
 import (
 	"errors"
 	"fmt"
+	"sort"
 )
 
 // Graph is a simple data structure implementation without any special considerations.
 // Each operation that modifies the graph does integrity checks.
+// Vertices are indexed by id, so looking them up doesn't depend on the size of the graph.
 type Graph struct {
 	vertices []*Vertex
+	index    map[string]*Vertex
+
+	// Connected components of the enabled vertices, nil until they are first asked for
+	components *componentTracker
+}
+
+// NewGraph returns an empty graph with room for capacity vertices, the zero Graph is ready to use too.
+func NewGraph(capacity int) *Graph {
+	return &Graph{
+		vertices: make([]*Vertex, 0, capacity),
+		index:    make(map[string]*Vertex, capacity),
+	}
+}
+
+// Edge is a directed edge of the graph, see AddEdge.
+type Edge struct {
+	Id       int
+	From, To string
 }
 
 // Vertex ...
@@ -16,11 +36,18 @@ type Vertex struct {
 	Id       string
 	adjacent map[int]*Vertex
 
+	// Vertices with an edge to this one, in the order the edges were added
+	incoming []*Vertex
+
+	// The graph is told when the vertex is disabled, to keep its components up to date
+	graph     *Graph
+	component *Component
+
 	// Having a disabled flag is more performant than actually removing the item
 	disabled bool
 }
 
-// AllEdges returns all enabled edge Ids
+// AllEdges returns all enabled edge Ids sorted in ascending order
 func (vertex *Vertex) AllEdges() []int {
 	edges := make([]int, 0)
 	for edgeId, edge := range vertex.adjacent {
@@ -29,6 +56,8 @@ func (vertex *Vertex) AllEdges() []int {
 		}
 	}
 
+	sort.Ints(edges)
+
 	return edges
 }
 
@@ -37,13 +66,31 @@ func (vertex *Vertex) Enabled() bool {
 }
 
 func (vertex *Vertex) Disable() {
+	if vertex.disabled {
+		return
+	}
+
 	vertex.disabled = true
+
+	if vertex.graph != nil && vertex.graph.components != nil {
+		vertex.graph.components.remove(vertex)
+	}
 }
 
 func (vertex *Vertex) GetAdjacent(edgeId int) *Vertex {
 	return vertex.adjacent[edgeId]
 }
 
+// Edges returns a copy of every edge, including the ones pointing to disabled vertices.
+func (vertex *Vertex) Edges() map[int]*Vertex {
+	edges := make(map[int]*Vertex, len(vertex.adjacent))
+	for edgeId, adjacent := range vertex.adjacent {
+		edges[edgeId] = adjacent
+	}
+
+	return edges
+}
+
 var (
 	ErrVertexDuplicated = errors.New("vertex already exist")
 	ErrEdgeDuplicated   = errors.New("edge already exist")
@@ -52,31 +99,64 @@ var (
 
 func (graph *Graph) AddVertex(id string) (*Vertex, error) {
 	// Integrity check
-	for _, vertex := range graph.vertices {
-		if vertex.Id == id {
+	if _, exists := graph.index[id]; exists {
+		return nil, fmt.Errorf("%w: %q", ErrVertexDuplicated, id)
+	}
+
+	return graph.addVertex(id), nil
+}
+
+// AddVertices adds a vertex for each id in the same order, if any id is duplicated none of them is added.
+func (graph *Graph) AddVertices(ids []string) ([]*Vertex, error) {
+	// Integrity check
+	added := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		if _, exists := graph.index[id]; exists || added[id] {
 			return nil, fmt.Errorf("%w: %q", ErrVertexDuplicated, id)
 		}
+
+		added[id] = true
+	}
+
+	vertices := make([]*Vertex, 0, len(ids))
+	for _, id := range ids {
+		vertices = append(vertices, graph.addVertex(id))
+	}
+
+	return vertices, nil
+}
+
+func (graph *Graph) addVertex(id string) *Vertex {
+	if graph.index == nil {
+		graph.index = make(map[string]*Vertex)
 	}
 
 	newVertex := &Vertex{
 		Id:       id,
 		adjacent: make(map[int]*Vertex),
+		graph:    graph,
 	}
 
 	graph.vertices = append(graph.vertices, newVertex)
+	graph.index[id] = newVertex
+
+	// The new vertex is a component of its own
+	graph.components = nil
 
-	return newVertex, nil
+	return newVertex
+}
+
+// Vertices returns every vertex in the order they were added, including the disabled ones.
+func (graph *Graph) Vertices() []*Vertex {
+	vertices := make([]*Vertex, len(graph.vertices))
+	copy(vertices, graph.vertices)
+
+	return vertices
 }
 
 // GetVertex returns the vertex with the matching ID, if it's not found, it returns nil.
 func (graph *Graph) GetVertex(id string) *Vertex {
-	for _, vertex := range graph.vertices {
-		if vertex.Id == id {
-			return vertex
-		}
-	}
-
-	return nil
+	return graph.index[id]
 }
 
 // AddEdge returns an error in case an edge with the same destination already exists.
@@ -103,6 +183,31 @@ func (graph *Graph) AddEdge(id int, from, to string) error {
 		}
 	}
 
+	// The edge replaces the one with the same id, which could split a component
+	if previous, exists := fromVertex.adjacent[id]; exists {
+		previous.removeIncoming(fromVertex)
+		graph.components = nil
+	}
+
 	fromVertex.adjacent[id] = toVertex
+	toVertex.incoming = append(toVertex.incoming, fromVertex)
+
+	// A new edge can only join components
+	if graph.components != nil && fromVertex.component != toVertex.component {
+		graph.components = nil
+	}
+
+	return nil
+}
+
+// AddEdges adds every edge in order, stopping at the first one AddEdge rejects.
+// The edges added before the failing one are kept.
+func (graph *Graph) AddEdges(edges []Edge) error {
+	for _, edge := range edges {
+		if err := graph.AddEdge(edge.Id, edge.From, edge.To); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
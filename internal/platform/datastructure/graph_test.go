@@ -3,6 +3,7 @@ package datastructure
 import (
 	"errors"
 	"reflect"
+	"strconv"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -114,3 +115,136 @@ func TestGraph_AddEdge(t *testing.T) {
 	assert.Error(t, err)
 	assert.ErrorIs(t, err, ErrVertexNotFound)
 }
+
+func TestVertexEdges(t *testing.T) {
+	disabledVertex := &Vertex{Id: "B", disabled: true}
+	enabledVertex := &Vertex{Id: "C"}
+	vertex := &Vertex{adjacent: map[int]*Vertex{1: disabledVertex, 2: enabledVertex}}
+
+	edges := vertex.Edges()
+	assert.Equal(t, map[int]*Vertex{1: disabledVertex, 2: enabledVertex}, edges)
+
+	// Modifying the copy must not modify the vertex
+	delete(edges, 1)
+	assert.Len(t, vertex.adjacent, 2)
+}
+
+func TestGraph_Vertices(t *testing.T) {
+	graph := &Graph{}
+	vertexA, _ := graph.AddVertex("A")
+	vertexB, _ := graph.AddVertex("B")
+	vertexB.Disable()
+
+	assert.Equal(t, []*Vertex{vertexA, vertexB}, graph.Vertices())
+}
+
+func TestNewGraph(t *testing.T) {
+	graph := NewGraph(2)
+
+	vertexA, err := graph.AddVertex("A")
+	assert.NoError(t, err)
+	assert.Equal(t, vertexA, graph.GetVertex("A"))
+
+	_, err = graph.AddVertex("A")
+	assert.ErrorIs(t, err, ErrVertexDuplicated)
+
+	// The zero graph looks up vertices without any of them
+	assert.Nil(t, new(Graph).GetVertex("A"))
+}
+
+func TestGraph_AddVertices(t *testing.T) {
+	graph := &Graph{}
+	_, err := graph.AddVertex("A")
+	assert.NoError(t, err)
+
+	vertices, err := graph.AddVertices([]string{"C", "B"})
+	assert.NoError(t, err)
+	assert.Equal(t, []*Vertex{graph.GetVertex("C"), graph.GetVertex("B")}, vertices)
+	assert.Equal(t, []*Vertex{graph.GetVertex("A"), graph.GetVertex("C"), graph.GetVertex("B")}, graph.Vertices())
+
+	// Test case: duplicated with the graph, nothing is added
+	_, err = graph.AddVertices([]string{"D", "A"})
+	assert.ErrorIs(t, err, ErrVertexDuplicated)
+	assert.Nil(t, graph.GetVertex("D"))
+
+	// Test case: duplicated with itself
+	_, err = graph.AddVertices([]string{"E", "E"})
+	assert.ErrorIs(t, err, ErrVertexDuplicated)
+	assert.Len(t, graph.Vertices(), 3)
+}
+
+func TestGraph_AddEdges(t *testing.T) {
+	graph := &Graph{}
+	_, err := graph.AddVertices([]string{"A", "B", "C"})
+	assert.NoError(t, err)
+
+	// Test case: successful add
+	err = graph.AddEdges([]Edge{{Id: 1, From: "A", To: "B"}, {Id: 2, From: "B", To: "C"}})
+	assert.NoError(t, err)
+	assert.Equal(t, graph.GetVertex("B"), graph.GetVertex("A").GetAdjacent(1))
+	assert.Equal(t, graph.GetVertex("C"), graph.GetVertex("B").GetAdjacent(2))
+
+	// Test case: stops at the first failure, keeping the edges added before it
+	err = graph.AddEdges([]Edge{{Id: 1, From: "C", To: "A"}, {Id: 2, From: "C", To: "D"}, {Id: 3, From: "C", To: "B"}})
+	assert.ErrorIs(t, err, ErrVertexNotFound)
+	assert.Equal(t, []int{1}, graph.GetVertex("C").AllEdges())
+}
+
+// benchmarkSizes are the amounts of vertices of the benchmarks, up to the size of the largest generated maps
+var benchmarkSizes = []int{1_000, 100_000, 1_000_000}
+
+// benchmarkEdges returns the ids of size vertices and the edges of a ring through them.
+func benchmarkEdges(size int) ([]string, []Edge) {
+	ids := make([]string, size)
+	for i := range ids {
+		ids[i] = "vertex-" + strconv.Itoa(i)
+	}
+
+	edges := make([]Edge, 0, size*2)
+	for i, id := range ids {
+		edges = append(edges,
+			Edge{Id: 1, From: id, To: ids[(i+1)%size]},
+			Edge{Id: 3, From: id, To: ids[(i+size-1)%size]})
+	}
+
+	return ids, edges
+}
+
+func BenchmarkGraph_Build(b *testing.B) {
+	for _, size := range benchmarkSizes {
+		ids, edges := benchmarkEdges(size)
+
+		b.Run(strconv.Itoa(size), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				graph := NewGraph(size)
+
+				if _, err := graph.AddVertices(ids); err != nil {
+					b.Fatal(err)
+				}
+
+				if err := graph.AddEdges(edges); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkGraph_GetVertex(b *testing.B) {
+	for _, size := range benchmarkSizes {
+		ids, _ := benchmarkEdges(size)
+
+		graph := NewGraph(size)
+		if _, err := graph.AddVertices(ids); err != nil {
+			b.Fatal(err)
+		}
+
+		b.Run(strconv.Itoa(size), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				if graph.GetVertex(ids[i%size]) == nil {
+					b.Fatal("vertex not found")
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,121 @@
+package datastructure
+
+import (
+	"errors"
+	"fmt"
+)
+
+var ErrPathNotFound = errors.New("path does not exist")
+
+// ShortestPath returns the edges of a path with the fewest edges from one vertex to another, empty if both are the same.
+// Paths only go through enabled vertices, but a disabled from vertex can still be left.
+// Edges are visited in ascending order, so between paths of the same length the same one is always returned.
+func (graph *Graph) ShortestPath(from, to string) ([]Edge, error) {
+	var (
+		fromVertex = graph.GetVertex(from)
+		toVertex   = graph.GetVertex(to)
+	)
+
+	// Integrity checks
+	{
+		if fromVertex == nil {
+			return nil, fmt.Errorf("%w: %q", ErrVertexNotFound, from)
+		}
+
+		if toVertex == nil {
+			return nil, fmt.Errorf("%w: %q", ErrVertexNotFound, to)
+		}
+	}
+
+	if fromVertex == toVertex {
+		return make([]Edge, 0), nil
+	}
+
+	// Vertex:Edge it was first reached by
+	reachedBy := make(map[*Vertex]Edge)
+
+	breadthFirst([]*Vertex{fromVertex}, func(vertex *Vertex, edgeId int, adjacent *Vertex, _ int) bool {
+		reachedBy[adjacent] = Edge{Id: edgeId, From: vertex.Id, To: adjacent.Id}
+
+		return adjacent != toVertex
+	})
+
+	if _, reached := reachedBy[toVertex]; !reached {
+		return nil, fmt.Errorf("%w: from %q to %q", ErrPathNotFound, from, to)
+	}
+
+	path := make([]Edge, 0)
+	for vertex := toVertex; vertex != fromVertex; vertex = graph.GetVertex(reachedBy[vertex].From) {
+		path = append(path, reachedBy[vertex])
+	}
+
+	// The path was walked backwards
+	for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+		path[i], path[j] = path[j], path[i]
+	}
+
+	return path, nil
+}
+
+// Distances returns Vertex:Edges from the nearest of sources, for every vertex reachable from them.
+// Like ShortestPath, paths only go through enabled vertices but disabled sources can still be left.
+func (graph *Graph) Distances(sources []*Vertex) map[*Vertex]int {
+	distances := make(map[*Vertex]int, len(sources))
+	for _, source := range sources {
+		distances[source] = 0
+	}
+
+	breadthFirst(sources, func(_ *Vertex, _ int, adjacent *Vertex, distance int) bool {
+		distances[adjacent] = distance
+
+		return true
+	})
+
+	return distances
+}
+
+// AllDistances returns From:To:Edges for every pair of vertices connected by a path, as Distances does.
+// It takes quadratic time and memory in the amount of vertices, so it is meant for small graphs.
+func (graph *Graph) AllDistances() map[*Vertex]map[*Vertex]int {
+	distances := make(map[*Vertex]map[*Vertex]int, len(graph.vertices))
+	for _, vertex := range graph.vertices {
+		distances[vertex] = graph.Distances([]*Vertex{vertex})
+	}
+
+	return distances
+}
+
+// breadthFirst calls reached the first time each vertex is reached from sources, with the vertex and edge it
+// was reached by and its distance to the nearest source. Only enabled vertices are reached, and the search
+// stops as soon as reached returns false.
+func breadthFirst(sources []*Vertex, reached func(vertex *Vertex, edgeId int, adjacent *Vertex, distance int) bool) {
+	// Vertex:Edges from the nearest source, for every vertex already reached
+	distance := make(map[*Vertex]int, len(sources))
+	pending := make([]*Vertex, 0, len(sources))
+
+	for _, source := range sources {
+		if _, visited := distance[source]; !visited {
+			distance[source] = 0
+			pending = append(pending, source)
+		}
+	}
+
+	for next := 0; next < len(pending); next++ {
+		vertex := pending[next]
+
+		for _, edgeId := range vertex.AllEdges() {
+			adjacent := vertex.adjacent[edgeId]
+			if _, visited := distance[adjacent]; visited {
+				continue
+			}
+
+			distance[adjacent] = distance[vertex] + 1
+
+			if !reached(vertex, edgeId, adjacent, distance[adjacent]) {
+				return
+			}
+
+			pending = append(pending, adjacent)
+		}
+	}
+}
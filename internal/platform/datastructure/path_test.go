@@ -0,0 +1,132 @@
+package datastructure
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// square returns the graph A-B-C-D-A with edges in both directions, 1 clockwise and 2 counterclockwise,
+// and E with an edge to A.
+func square(t *testing.T) *Graph {
+	graph := &Graph{}
+
+	_, err := graph.AddVertices([]string{"A", "B", "C", "D", "E"})
+	assert.NoError(t, err)
+
+	assert.NoError(t, graph.AddEdges([]Edge{
+		{Id: 1, From: "A", To: "B"}, {Id: 1, From: "B", To: "C"}, {Id: 1, From: "C", To: "D"}, {Id: 1, From: "D", To: "A"},
+		{Id: 2, From: "A", To: "D"}, {Id: 2, From: "D", To: "C"}, {Id: 2, From: "C", To: "B"}, {Id: 2, From: "B", To: "A"},
+		{Id: 1, From: "E", To: "A"},
+	}))
+
+	return graph
+}
+
+func TestGraph_ShortestPath(t *testing.T) {
+	graph := square(t)
+
+	// Test case: between paths of the same length the one with the lowest edges is taken
+	path, err := graph.ShortestPath("A", "C")
+	assert.NoError(t, err)
+	assert.Equal(t, []Edge{{Id: 1, From: "A", To: "B"}, {Id: 1, From: "B", To: "C"}}, path)
+
+	// Test case: same vertex
+	path, err = graph.ShortestPath("A", "A")
+	assert.NoError(t, err)
+	assert.Empty(t, path)
+
+	// Test case: edges only go one way
+	_, err = graph.ShortestPath("A", "E")
+	assert.ErrorIs(t, err, ErrPathNotFound)
+
+	// Test case: vertex not found
+	_, err = graph.ShortestPath("A", "F")
+	assert.ErrorIs(t, err, ErrVertexNotFound)
+
+	// Test case: disabled vertices are avoided
+	graph.GetVertex("B").Disable()
+	path, err = graph.ShortestPath("A", "C")
+	assert.NoError(t, err)
+	assert.Equal(t, []Edge{{Id: 2, From: "A", To: "D"}, {Id: 2, From: "D", To: "C"}}, path)
+
+	graph.GetVertex("D").Disable()
+	_, err = graph.ShortestPath("A", "C")
+	assert.ErrorIs(t, err, ErrPathNotFound)
+
+	// Test case: a disabled vertex can still be left, but not reached
+	path, err = graph.ShortestPath("B", "C")
+	assert.NoError(t, err)
+	assert.Equal(t, []Edge{{Id: 1, From: "B", To: "C"}}, path)
+
+	_, err = graph.ShortestPath("C", "B")
+	assert.ErrorIs(t, err, ErrPathNotFound)
+}
+
+func TestGraph_Distances(t *testing.T) {
+	graph := square(t)
+
+	assert.Equal(t, map[string]int{"A": 0, "B": 1, "C": 2, "D": 1},
+		distanceIds(graph.Distances([]*Vertex{graph.GetVertex("A")})))
+
+	// Test case: from the nearest source
+	assert.Equal(t, map[string]int{"A": 1, "B": 2, "C": 3, "D": 2, "E": 0},
+		distanceIds(graph.Distances([]*Vertex{graph.GetVertex("E")})))
+
+	assert.Equal(t, map[string]int{"A": 0, "B": 1, "C": 0, "D": 1, "E": 0},
+		distanceIds(graph.Distances([]*Vertex{graph.GetVertex("E"), graph.GetVertex("C"), graph.GetVertex("A")})))
+
+	// Test case: disabled sources are left, disabled vertices aren't reached
+	graph.GetVertex("A").Disable()
+	assert.Equal(t, map[string]int{"E": 0}, distanceIds(graph.Distances([]*Vertex{graph.GetVertex("E")})))
+	assert.Equal(t, map[string]int{"A": 0, "B": 1, "C": 2, "D": 1},
+		distanceIds(graph.Distances([]*Vertex{graph.GetVertex("A")})))
+
+	assert.Empty(t, graph.Distances(nil))
+}
+
+func TestGraph_AllDistances(t *testing.T) {
+	graph := square(t)
+	graph.GetVertex("D").Disable()
+
+	distances := graph.AllDistances()
+
+	assert.Len(t, distances, 5)
+	assert.Equal(t, map[string]int{"A": 0, "B": 1, "C": 2}, distanceIds(distances[graph.GetVertex("A")]))
+	assert.Equal(t, map[string]int{"A": 2, "B": 1, "C": 0}, distanceIds(distances[graph.GetVertex("C")]))
+	assert.Equal(t, map[string]int{"A": 1, "B": 2, "C": 1, "D": 0}, distanceIds(distances[graph.GetVertex("D")]))
+}
+
+func BenchmarkGraph_Distances(b *testing.B) {
+	for _, size := range benchmarkSizes {
+		ids, edges := benchmarkEdges(size)
+
+		graph := NewGraph(size)
+		if _, err := graph.AddVertices(ids); err != nil {
+			b.Fatal(err)
+		}
+
+		if err := graph.AddEdges(edges); err != nil {
+			b.Fatal(err)
+		}
+
+		sources := []*Vertex{graph.GetVertex(ids[0])}
+
+		b.Run(strconv.Itoa(size), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				graph.Distances(sources)
+			}
+		})
+	}
+}
+
+// distanceIds returns the distances by vertex id.
+func distanceIds(distances map[*Vertex]int) map[string]int {
+	ids := make(map[string]int, len(distances))
+	for vertex, distance := range distances {
+		ids[vertex.Id] = distance
+	}
+
+	return ids
+}
@@ -0,0 +1,36 @@
+package numeric
+
+import "math"
+
+// Mean returns the arithmetic mean of values, or 0 if there are no values.
+func Mean(values []int) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+
+	total := 0
+	for _, value := range values {
+		total += value
+	}
+
+	return float64(total) / float64(len(values))
+}
+
+// Percentile returns the nearest-rank percentile p (0 < p <= 100) of values, which must be sorted in ascending order.
+// It returns 0 if there are no values.
+func Percentile(sortedValues []int, p float64) int {
+	if len(sortedValues) == 0 {
+		return 0
+	}
+
+	rank := int(math.Ceil(p / 100 * float64(len(sortedValues))))
+	if rank < 1 {
+		rank = 1
+	}
+
+	if rank > len(sortedValues) {
+		rank = len(sortedValues)
+	}
+
+	return sortedValues[rank-1]
+}
@@ -0,0 +1,54 @@
+package numeric
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestMean(t *testing.T) {
+	testCases := []struct {
+		input    []int
+		expected float64
+	}{
+		{nil, 0},
+		{[]int{4}, 4},
+		{[]int{1, 2, 3, 4}, 2.5},
+	}
+
+	for _, tc := range testCases {
+		t.Run(fmt.Sprintf("Input %v", tc.input), func(t *testing.T) {
+			output := Mean(tc.input)
+			if output != tc.expected {
+				t.Errorf("Expected %v, but got %v", tc.expected, output)
+			}
+		})
+	}
+}
+
+func TestPercentile(t *testing.T) {
+	values := []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}
+
+	testCases := []struct {
+		input    []int
+		p        float64
+		expected int
+	}{
+		{nil, 50, 0},
+		{values, 0, 1},
+		{values, 10, 1},
+		{values, 50, 5},
+		{values, 90, 9},
+		{values, 99, 10},
+		{values, 100, 10},
+		{[]int{7}, 50, 7},
+	}
+
+	for _, tc := range testCases {
+		t.Run(fmt.Sprintf("Input %v p%v", tc.input, tc.p), func(t *testing.T) {
+			output := Percentile(tc.input, tc.p)
+			if output != tc.expected {
+				t.Errorf("Expected %d, but got %d", tc.expected, output)
+			}
+		})
+	}
+}
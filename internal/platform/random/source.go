@@ -0,0 +1,53 @@
+package random
+
+import "math/rand"
+
+// Source is a math/rand source whose state can be saved and restored,
+// since the standard library doesn't expose the state of its sources.
+// It isn't safe for concurrent use, same as the standard sources.
+type Source struct {
+	seed   int64
+	draws  uint64
+	source rand.Source64
+}
+
+// State is everything needed to rebuild a Source at the same point of its sequence.
+type State struct {
+	Seed  int64  `json:"seed"`
+	Draws uint64 `json:"draws"`
+}
+
+func NewSource(seed int64) *Source {
+	return &Source{seed: seed, source: rand.NewSource(seed).(rand.Source64)}
+}
+
+// Restore returns a source that continues the sequence right where the saved one was.
+// It replays every draw, which is cheap compared with the simulation that made them.
+func Restore(state State) *Source {
+	source := NewSource(state.Seed)
+	for source.draws < state.Draws {
+		source.Uint64()
+	}
+
+	return source
+}
+
+func (source *Source) Int63() int64 {
+	source.draws++
+	return source.source.Int63()
+}
+
+func (source *Source) Uint64() uint64 {
+	source.draws++
+	return source.source.Uint64()
+}
+
+func (source *Source) Seed(seed int64) {
+	source.seed = seed
+	source.draws = 0
+	source.source.Seed(seed)
+}
+
+func (source *Source) State() State {
+	return State{Seed: source.seed, Draws: source.draws}
+}
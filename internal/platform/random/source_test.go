@@ -0,0 +1,46 @@
+package random
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSource_SameSequenceAsStandardSource(t *testing.T) {
+	expected := rand.New(rand.NewSource(42))
+	actual := rand.New(NewSource(42))
+
+	for i := 0; i < 100; i++ {
+		assert.Equal(t, expected.Intn(1000), actual.Intn(1000))
+		assert.Equal(t, expected.Int63(), actual.Int63())
+		assert.Equal(t, expected.Uint64(), actual.Uint64())
+	}
+}
+
+func TestRestore(t *testing.T) {
+	source := NewSource(7)
+	randomizer := rand.New(source)
+
+	for i := 0; i < 50; i++ {
+		randomizer.Intn(10)
+		randomizer.Float64()
+	}
+
+	state := source.State()
+	assert.Equal(t, int64(7), state.Seed)
+
+	restored := rand.New(Restore(state))
+	for i := 0; i < 50; i++ {
+		assert.Equal(t, randomizer.Intn(1000), restored.Intn(1000))
+	}
+}
+
+func TestSource_Seed(t *testing.T) {
+	source := NewSource(1)
+	source.Int63()
+
+	source.Seed(2)
+	assert.Equal(t, State{Seed: 2}, source.State())
+	assert.Equal(t, rand.NewSource(2).Int63(), source.Int63())
+}
@@ -0,0 +1,422 @@
+package system
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf8"
+
+	"gopkg.in/yaml.v3"
+)
+
+// The JSON and YAML formats share the same schema, each record is an entry of "cities"
+// whose key is "name", whose values are "roads" and that may have "attributes": the "pop",
+// "def" and "terrain" of the city.
+//
+//	Example:
+//	cities:
+//	  - name: Foo
+//	    roads: {north: Bar}
+//	    attributes: {pop: 120000, def: 2, terrain: mountain}
+//	  - name: Bar
+//	    roads: {south: Foo}
+const (
+	_documentRecords    = "cities"
+	_documentKey        = "name"
+	_documentValues     = "roads"
+	_documentAttributes = "attributes"
+)
+
+// document mirrors the schema above, it is only used for writing since reading walks
+// the yaml.Node tree to keep the position of every record.
+type document struct {
+	Cities []documentRecord `json:"cities" yaml:"cities"`
+}
+
+type documentRecord struct {
+	Name       string        `json:"name" yaml:"name"`
+	Roads      orderedValues `json:"roads,omitempty" yaml:"roads,omitempty"`
+	Attributes orderedValues `json:"attributes,omitempty" yaml:"attributes,omitempty"`
+}
+
+type keyValue struct {
+	key   string
+	value string
+}
+
+// orderedValues is written as an object whose keys keep the slice order.
+type orderedValues []keyValue
+
+func (values orderedValues) MarshalJSON() ([]byte, error) {
+	var buffer bytes.Buffer
+
+	buffer.WriteByte('{')
+	for i, pair := range values {
+		if i > 0 {
+			buffer.WriteByte(',')
+		}
+
+		key, err := json.Marshal(pair.key)
+		if err != nil {
+			return nil, err
+		}
+
+		value, err := json.Marshal(pair.value)
+		if err != nil {
+			return nil, err
+		}
+
+		buffer.Write(key)
+		buffer.WriteByte(':')
+		buffer.Write(value)
+	}
+	buffer.WriteByte('}')
+
+	return buffer.Bytes(), nil
+}
+
+func (values orderedValues) MarshalYAML() (interface{}, error) {
+	node := &yaml.Node{Kind: yaml.MappingNode, Style: yaml.FlowStyle}
+	for _, pair := range values {
+		node.Content = append(node.Content,
+			&yaml.Node{Kind: yaml.ScalarNode, Value: pair.key},
+			&yaml.Node{Kind: yaml.ScalarNode, Value: pair.value},
+		)
+	}
+
+	return node, nil
+}
+
+func writeDocument(output io.Writer, loaded LoadedFile, format Format, keyOrder []string) error {
+	doc := document{Cities: make([]documentRecord, 0, len(loaded.Records))}
+
+	for _, key := range sortedKeys(loaded.Records) {
+		record := documentRecord{Name: key}
+
+		for _, valueKey := range sortedValueKeys(loaded.Records[key], keyOrder) {
+			record.Roads = append(record.Roads, keyValue{key: valueKey, value: loaded.Records[key][valueKey]})
+		}
+
+		for _, attributeKey := range sortedValueKeys(loaded.Attributes[key], nil) {
+			record.Attributes = append(record.Attributes, keyValue{key: attributeKey, value: loaded.Attributes[key][attributeKey]})
+		}
+
+		doc.Cities = append(doc.Cities, record)
+	}
+
+	if format == FormatJSON {
+		encoder := json.NewEncoder(output)
+		encoder.SetIndent("", "  ")
+		encoder.SetEscapeHTML(false)
+
+		return encoder.Encode(doc)
+	}
+
+	encoder := yaml.NewEncoder(output)
+	encoder.SetIndent(2)
+
+	if err := encoder.Encode(doc); err != nil {
+		return err
+	}
+
+	return encoder.Close()
+}
+
+// loadDocument reads a JSON or YAML records file. Syntax errors and records that don't follow the schema
+// are returned as LineErrors, matching ErrInvalidFormat or ErrDuplicatedKey.
+func loadDocument(data []byte, format Format) (LoadedFile, error) {
+	var (
+		root *yaml.Node
+		err  error
+	)
+
+	if format == FormatJSON {
+		root, err = jsonToNode(data)
+	} else {
+		root, err = yamlToNode(data)
+	}
+
+	if err != nil {
+		return LoadedFile{}, err
+	}
+
+	return decodeDocument(root)
+}
+
+func yamlToNode(data []byte) (*yaml.Node, error) {
+	var root yaml.Node
+	if err := yaml.Unmarshal(data, &root); err != nil {
+		// yaml.v3 doesn't expose the position of syntax errors, only within the message
+		position := Position{Line: 1, Column: 1}
+		message := strings.TrimPrefix(err.Error(), "yaml: ")
+
+		if _, scanErr := fmt.Sscanf(message, "line %d:", &position.Line); scanErr == nil {
+			message = strings.TrimSpace(message[strings.Index(message, ":")+1:])
+		}
+
+		return nil, LineErrors{{Position: position, Err: fmt.Errorf("%w: %s", ErrInvalidFormat, message)}}
+	}
+
+	if root.Kind == yaml.DocumentNode && len(root.Content) > 0 {
+		return root.Content[0], nil
+	}
+
+	return &root, nil
+}
+
+// jsonToNode reads a JSON document as a yaml.Node tree, so both formats are decoded by the same code
+// and every value keeps its position.
+func jsonToNode(data []byte) (*yaml.Node, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	if len(bytes.TrimSpace(data)) == 0 {
+		return &yaml.Node{}, nil
+	}
+
+	node, err := readJSONValue(decoder, data)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := decoder.Token(); !errors.Is(err, io.EOF) {
+		return nil, jsonError(data, decoder.InputOffset(), errors.New("unexpected data after the document"))
+	}
+
+	return node, nil
+}
+
+func readJSONValue(decoder *json.Decoder, data []byte) (*yaml.Node, error) {
+	// The decoder offset is right after the previous token, separators included
+	start := decoder.InputOffset()
+	for start < int64(len(data)) && strings.ContainsRune(" \t\r\n,:", rune(data[start])) {
+		start++
+	}
+
+	token, err := decoder.Token()
+	if err != nil {
+		var syntaxError *json.SyntaxError
+		if errors.As(err, &syntaxError) {
+			return nil, jsonError(data, syntaxError.Offset, err)
+		}
+
+		return nil, jsonError(data, start, err)
+	}
+
+	position := offsetPosition(data, start)
+	node := &yaml.Node{Kind: yaml.ScalarNode, Line: position.Line, Column: position.Column}
+
+	switch value := token.(type) {
+	case json.Delim:
+		node.Kind = yaml.MappingNode
+		if value == '[' {
+			node.Kind = yaml.SequenceNode
+		}
+
+		for decoder.More() {
+			child, err := readJSONValue(decoder, data)
+			if err != nil {
+				return nil, err
+			}
+
+			node.Content = append(node.Content, child)
+		}
+
+		// Closing delimiter
+		if _, err := decoder.Token(); err != nil {
+			return nil, jsonError(data, decoder.InputOffset(), err)
+		}
+	case string:
+		node.Tag, node.Value = "!!str", value
+	case json.Number:
+		node.Tag, node.Value = "!!float", value.String()
+	case bool:
+		node.Tag, node.Value = "!!bool", fmt.Sprint(value)
+	case nil:
+		node.Tag, node.Value = "!!null", "null"
+	}
+
+	return node, nil
+}
+
+func jsonError(data []byte, offset int64, err error) error {
+	return LineErrors{{Position: offsetPosition(data, offset), Err: fmt.Errorf("%w: %s", ErrInvalidFormat, err.Error())}}
+}
+
+// offsetPosition returns the position of the byte at offset, columns are counted in characters.
+func offsetPosition(data []byte, offset int64) Position {
+	if offset > int64(len(data)) {
+		offset = int64(len(data))
+	}
+
+	before := data[:offset]
+	lineStart := bytes.LastIndexByte(before, '\n') + 1
+
+	return Position{Line: bytes.Count(before, []byte{'\n'}) + 1, Column: utf8.RuneCount(before[lineStart:]) + 1}
+}
+
+func decodeDocument(root *yaml.Node) (LoadedFile, error) {
+	loaded := LoadedFile{Records: make(LoadFileRecords), Positions: make(LoadFilePositions), Attributes: make(LoadFileAttributes)}
+
+	// Empty document
+	if root.Kind == 0 {
+		return loaded, nil
+	}
+
+	fields, err := decodeFields(root, _documentRecords)
+	if err != nil {
+		return LoadedFile{}, LineErrors{err}
+	}
+
+	recordsNode, exists := fields[_documentRecords]
+	if !exists {
+		return loaded, nil
+	}
+
+	if recordsNode.Kind != yaml.SequenceNode {
+		return LoadedFile{}, LineErrors{nodeError(recordsNode, "%q must be a list", _documentRecords)}
+	}
+
+	lineErrors := make(LineErrors, 0)
+
+	for _, recordNode := range recordsNode.Content {
+		key, values, attributes, position, err := decodeRecord(recordNode)
+		if err != nil {
+			lineErrors = append(lineErrors, err)
+			continue
+		}
+
+		if _, alreadyExist := loaded.Records[key]; alreadyExist {
+			lineErrors = append(lineErrors, &LineError{
+				Position: position.Position,
+				Err:      fmt.Errorf("%w: %q already defined at line %d", ErrDuplicatedKey, key, loaded.Positions[key].Line),
+			})
+			continue
+		}
+
+		loaded.Records[key] = values
+		loaded.Positions[key] = position
+		loaded.Attributes[key] = attributes
+	}
+
+	if len(lineErrors) > 0 {
+		return LoadedFile{}, lineErrors
+	}
+
+	return loaded, nil
+}
+
+func decodeRecord(node *yaml.Node) (string, map[string]string, map[string]string, RecordPosition, *LineError) {
+	fields, err := decodeFields(node, _documentKey, _documentValues, _documentAttributes)
+	if err != nil {
+		return "", nil, nil, RecordPosition{}, err
+	}
+
+	keyNode, exists := fields[_documentKey]
+	if !exists {
+		return "", nil, nil, RecordPosition{}, nodeError(node, "missing %q", _documentKey)
+	}
+
+	if keyNode.Kind != yaml.ScalarNode || keyNode.Value == "" {
+		return "", nil, nil, RecordPosition{}, nodeError(keyNode, "%q must be a non empty text", _documentKey)
+	}
+
+	position := RecordPosition{Position: nodePosition(keyNode), Values: make(map[string]Position)}
+
+	values, valuesPositions, err := decodeValues(fields[_documentValues], _documentValues)
+	if err != nil {
+		return "", nil, nil, RecordPosition{}, err
+	}
+
+	for valueKey, valuePosition := range valuesPositions {
+		position.Values[valueKey] = valuePosition
+	}
+
+	attributes, attributesPositions, err := decodeValues(fields[_documentAttributes], _documentAttributes)
+	if err != nil {
+		return "", nil, nil, RecordPosition{}, err
+	}
+
+	if len(attributesPositions) > 0 {
+		position.Attributes = attributesPositions
+	}
+
+	return keyNode.Value, values, attributes, position, nil
+}
+
+// decodeFields returns the value of each key of the mapping node, every key must be one of allowed.
+func decodeFields(node *yaml.Node, allowed ...string) (map[string]*yaml.Node, *LineError) {
+	if node.Kind != yaml.MappingNode {
+		return nil, nodeError(node, "expected an object with %q", strings.Join(allowed, ", "))
+	}
+
+	fields := make(map[string]*yaml.Node)
+
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		keyNode, valueNode := node.Content[i], node.Content[i+1]
+
+		if !contains(allowed, keyNode.Value) {
+			return nil, nodeError(keyNode, "unknown field %q", keyNode.Value)
+		}
+
+		if _, alreadyExist := fields[keyNode.Value]; alreadyExist {
+			return nil, &LineError{Position: nodePosition(keyNode), Err: fmt.Errorf("%w: field %q", ErrDuplicatedKey, keyNode.Value)}
+		}
+
+		fields[keyNode.Value] = valueNode
+	}
+
+	return fields, nil
+}
+
+// decodeValues reads a mapping of texts, a missing node is an empty mapping.
+func decodeValues(node *yaml.Node, field string) (map[string]string, map[string]Position, *LineError) {
+	values := make(map[string]string)
+	positions := make(map[string]Position)
+
+	if node == nil {
+		return values, positions, nil
+	}
+
+	if node.Kind != yaml.MappingNode {
+		return nil, nil, nodeError(node, "%q must be an object", field)
+	}
+
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		keyNode, valueNode := node.Content[i], node.Content[i+1]
+
+		if keyNode.Kind != yaml.ScalarNode || valueNode.Kind != yaml.ScalarNode || valueNode.ShortTag() == "!!null" {
+			return nil, nil, nodeError(keyNode, "%q values must be texts or numbers", field)
+		}
+
+		if _, alreadyExist := values[keyNode.Value]; alreadyExist {
+			return nil, nil, &LineError{Position: nodePosition(keyNode), Err: fmt.Errorf("%w: %q", ErrDuplicatedKey, keyNode.Value)}
+		}
+
+		values[keyNode.Value] = valueNode.Value
+		positions[keyNode.Value] = nodePosition(keyNode)
+	}
+
+	return values, positions, nil
+}
+
+func nodeError(node *yaml.Node, format string, args ...interface{}) *LineError {
+	return &LineError{Position: nodePosition(node), Err: fmt.Errorf("%w: "+format, append([]interface{}{ErrInvalidFormat}, args...)...)}
+}
+
+func nodePosition(node *yaml.Node) Position {
+	return Position{Line: node.Line, Column: node.Column}
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
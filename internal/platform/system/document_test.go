@@ -0,0 +1,183 @@
+package system
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func managerReading(content string) *Manager {
+	return &Manager{OpenFunc: func(string) (io.ReadCloser, error) {
+		return io.NopCloser(strings.NewReader(content)), nil
+	}}
+}
+
+func TestManager_Load_Documents(t *testing.T) {
+	expectedRecords := LoadFileRecords{"Foo": {"north": "Bar", "west": "Baz"}, "New York": {"south": "Foo"}}
+	expectedAttributes := LoadFileAttributes{"Foo": {"pop": "120000", "terrain": "mountain"}, "New York": {}}
+
+	t.Run("yaml", func(t *testing.T) {
+		loaded, err := managerReading("cities:\n" +
+			"  - name: Foo\n" +
+			"    roads: {north: Bar, west: Baz}\n" +
+			"    attributes: {pop: 120000, terrain: mountain}\n" +
+			"  - name: New York\n" +
+			"    roads:\n" +
+			"      south: Foo\n").Load("layout.yaml")
+		require.NoError(t, err)
+
+		assert.Equal(t, expectedRecords, loaded.Records)
+		assert.Equal(t, expectedAttributes, loaded.Attributes)
+		assert.Equal(t, LoadFilePositions{
+			"Foo": {
+				Position:   Position{Line: 2, Column: 11},
+				Values:     map[string]Position{"north": {3, 13}, "west": {3, 25}},
+				Attributes: map[string]Position{"pop": {4, 18}, "terrain": {4, 31}},
+			},
+			"New York": {Position: Position{Line: 5, Column: 11}, Values: map[string]Position{"south": {7, 7}}},
+		}, loaded.Positions)
+	})
+
+	t.Run("json", func(t *testing.T) {
+		loaded, err := managerReading(`{"cities": [
+  {"name": "Foo", "roads": {"north": "Bar", "west": "Baz"}, "attributes": {"pop": 120000, "terrain": "mountain"}},
+  {"name": "New York", "roads": {"south": "Foo"}}
+]}`).Load("layout.json")
+		require.NoError(t, err)
+
+		assert.Equal(t, expectedRecords, loaded.Records)
+		assert.Equal(t, expectedAttributes, loaded.Attributes)
+		assert.Equal(t, LoadFilePositions{
+			"Foo": {
+				Position:   Position{Line: 2, Column: 12},
+				Values:     map[string]Position{"north": {2, 29}, "west": {2, 45}},
+				Attributes: map[string]Position{"pop": {2, 76}, "terrain": {2, 91}},
+			},
+			"New York": {Position: Position{Line: 3, Column: 12}, Values: map[string]Position{"south": {3, 34}}},
+		}, loaded.Positions)
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		for _, path := range []string{"layout.json", "layout.yaml"} {
+			loaded, err := managerReading("").Load(path)
+			require.NoError(t, err)
+			assert.Empty(t, loaded.Records)
+		}
+	})
+}
+
+func TestManager_Load_DocumentErrors(t *testing.T) {
+	t.Run("schema", func(t *testing.T) {
+		_, err := managerReading("cities:\n" +
+			"  - name: Foo\n" +
+			"    roads: [Bar]\n" +
+			"  - name: Bar\n" +
+			"  - nam: Baz\n" +
+			"  - name: Bar\n").Load("layout.yaml")
+
+		var lineErrors LineErrors
+		require.ErrorAs(t, err, &lineErrors)
+		require.Len(t, lineErrors, 3)
+
+		assert.Equal(t, Position{Line: 3, Column: 12}, lineErrors[0].Position)
+		assert.EqualError(t, lineErrors[0], `line 3, column 12: invalid format: "roads" must be an object`)
+
+		assert.Equal(t, Position{Line: 5, Column: 5}, lineErrors[1].Position)
+		assert.ErrorIs(t, lineErrors[1], ErrInvalidFormat)
+
+		assert.Equal(t, Position{Line: 6, Column: 11}, lineErrors[2].Position)
+		assert.ErrorIs(t, lineErrors[2], ErrDuplicatedKey)
+	})
+
+	t.Run("yaml syntax", func(t *testing.T) {
+		_, err := managerReading("cities:\n  - name: Foo\n    roads: {north: Bar\n").Load("layout.yaml")
+
+		var lineErrors LineErrors
+		require.ErrorAs(t, err, &lineErrors)
+		assert.ErrorIs(t, err, ErrInvalidFormat)
+	})
+
+	t.Run("json syntax", func(t *testing.T) {
+		_, err := managerReading("{\"cities\": [\n  {\"name\": \"Foo\",}\n]}").Load("layout.json")
+
+		var lineErrors LineErrors
+		require.ErrorAs(t, err, &lineErrors)
+		assert.Equal(t, 2, lineErrors[0].Line)
+		assert.ErrorIs(t, err, ErrInvalidFormat)
+	})
+
+	t.Run("json null road", func(t *testing.T) {
+		_, err := managerReading(`{"cities": [{"name": "Foo", "roads": {"north": null}}]}`).Load("layout.json")
+
+		assert.ErrorIs(t, err, ErrInvalidFormat)
+	})
+}
+
+func TestWriteFile(t *testing.T) {
+	loaded := LoadedFile{
+		Records:    LoadFileRecords{"Foo": {"b": "Bar", "a": "Baz"}, "Bar": {}},
+		Attributes: LoadFileAttributes{"Foo": {"size": "3"}},
+	}
+
+	t.Run("json", func(t *testing.T) {
+		var output strings.Builder
+		require.NoError(t, WriteFile(&output, loaded, FormatJSON, []string{"b"}))
+
+		assert.Equal(t, `{
+  "cities": [
+    {
+      "name": "Bar"
+    },
+    {
+      "name": "Foo",
+      "roads": {
+        "b": "Bar",
+        "a": "Baz"
+      },
+      "attributes": {
+        "size": "3"
+      }
+    }
+  ]
+}
+`, output.String())
+	})
+
+	t.Run("yaml", func(t *testing.T) {
+		var output strings.Builder
+		require.NoError(t, WriteFile(&output, loaded, FormatYAML, []string{"b"}))
+
+		assert.Equal(t, "cities:\n  - name: Bar\n  - name: Foo\n    roads: {b: Bar, a: Baz}\n    attributes: {size: 3}\n", output.String())
+	})
+
+	t.Run("text", func(t *testing.T) {
+		var output strings.Builder
+		require.NoError(t, WriteFile(&output, loaded, FormatText, []string{"b"}))
+		assert.Equal(t, "Bar\nFoo b=Bar a=Baz size=3\n", output.String())
+
+		duplicated := LoadedFile{
+			Records:    LoadFileRecords{"Foo": {"size": "2"}},
+			Attributes: LoadFileAttributes{"Foo": {"size": "3"}},
+		}
+		assert.ErrorIs(t, WriteFile(&output, duplicated, FormatText, nil), ErrDuplicatedKey)
+
+		loaded.Attributes = nil
+		output.Reset()
+		require.NoError(t, WriteFile(&output, loaded, FormatText, []string{"b"}))
+		assert.Equal(t, "Bar\nFoo b=Bar a=Baz\n", output.String())
+	})
+
+	t.Run("round trip", func(t *testing.T) {
+		for _, format := range []Format{FormatJSON, FormatYAML} {
+			var output strings.Builder
+			require.NoError(t, WriteFile(&output, loaded, format, nil))
+
+			reloaded, err := managerReading(output.String()).Load("layout." + string(format))
+			require.NoError(t, err)
+			assert.Equal(t, loaded.Records, reloaded.Records)
+		}
+	})
+}
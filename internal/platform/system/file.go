@@ -4,8 +4,11 @@ import (
 	"bufio"
 	"errors"
 	"fmt"
+	"io"
 	"log"
+	"sort"
 	"strings"
+	"unicode/utf8"
 )
 
 // LoadFileRecords represents a file record in which each key is the first word of a new line,
@@ -21,18 +24,89 @@ import (
 //		}
 type LoadFileRecords = map[string]map[string]string
 
+// LoadFilePositions has the position of every record of LoadFileRecords, using the same keys.
+type LoadFilePositions = map[string]RecordPosition
+
+// Position locates text in a file, both line and column start at 1.
+// The zero value means the position is unknown.
+type Position struct {
+	Line   int
+	Column int
+}
+
+// RecordPosition has the position of the record key and of each of its values and attributes keys.
+type RecordPosition struct {
+	Position
+	Values     map[string]Position
+	Attributes map[string]Position
+}
+
 var (
 	ErrInvalidFormat = errors.New("invalid format")
 	ErrDuplicatedKey = errors.New("duplicated key")
 )
 
-// LoadFile must point to a file that has a valid LoadFileRecords format. If not it is going to return ErrInvalidFormat.
+// LineError locates the reason why a line of a file could not be loaded.
+type LineError struct {
+	Position
+	Err error
+}
+
+func (e *LineError) Error() string {
+	return fmt.Sprintf("line %d, column %d: %s", e.Line, e.Column, e.Err.Error())
+}
+
+func (e *LineError) Unwrap() error {
+	return e.Err
+}
+
+// LineErrors has an error for each line that could not be loaded, sorted by line.
+type LineErrors []*LineError
+
+func (e LineErrors) Error() string {
+	messages := make([]string, 0, len(e))
+	for _, lineErr := range e {
+		messages = append(messages, lineErr.Error())
+	}
+
+	return strings.Join(messages, "\n")
+}
+
+// Is reports whether any of the line errors matches target.
+func (e LineErrors) Is(target error) bool {
+	for _, lineErr := range e {
+		if errors.Is(lineErr, target) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// LoadFile must point to a file that has a valid LoadFileRecords format. If not it is going to return LineErrors,
+// matching ErrInvalidFormat or ErrDuplicatedKey, with every line that is not valid.
 func (manager *Manager) LoadFile(path string) (LoadFileRecords, error) {
-	output := make(LoadFileRecords)
+	records, _, err := manager.LoadFileWithPositions(path)
+
+	return records, err
+}
+
+// LoadFileWithPositions works as LoadFile but also returns where each record is in the file.
+// Empty lines are ignored.
+func (manager *Manager) LoadFileWithPositions(path string) (LoadFileRecords, LoadFilePositions, error) {
+	loaded, err := manager.Load(path)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return loaded.Records, loaded.Positions, nil
+}
 
+// Load reads the records file in the format returned by FormatOf, the errors are the same as LoadFile ones.
+func (manager *Manager) Load(path string) (LoadedFile, error) {
 	file, err := manager.OpenFunc(path)
 	if err != nil {
-		return nil, err
+		return LoadedFile{}, err
 	}
 
 	defer func() {
@@ -42,56 +116,211 @@ func (manager *Manager) LoadFile(path string) (LoadFileRecords, error) {
 		}
 	}()
 
+	switch format := manager.FormatOf(path); format {
+	case FormatText:
+		return loadText(file)
+	case FormatJSON, FormatYAML:
+		data, err := io.ReadAll(file)
+		if err != nil {
+			return LoadedFile{}, err
+		}
+
+		return loadDocument(data, format)
+	default:
+		return LoadedFile{}, fmt.Errorf("%w: %q", ErrUnknownFormat, format)
+	}
+}
+
+func loadText(file io.Reader) (LoadedFile, error) {
+	output := make(LoadFileRecords)
+	positions := make(LoadFilePositions)
+
 	scanner := bufio.NewScanner(file)
 
 	scanner.Split(bufio.ScanLines)
 
-	for scanner.Scan() {
-		key, values, err := processLoadFileRecord(scanner.Text())
+	lineErrors := make(LineErrors, 0)
+
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		if strings.TrimSpace(scanner.Text()) == "" {
+			continue
+		}
+
+		key, values, position, err := processLoadFileRecord(scanner.Text(), lineNumber)
 		if err != nil {
-			return nil, err
+			lineErrors = append(lineErrors, err)
+			continue
 		}
 
 		if _, alreadyExist := output[key]; alreadyExist {
-			return nil, fmt.Errorf("%w: %q", ErrDuplicatedKey, key)
+			lineErrors = append(lineErrors, &LineError{
+				Position: position.Position,
+				Err:      fmt.Errorf("%w: %q already defined at line %d", ErrDuplicatedKey, key, positions[key].Line),
+			})
+			continue
 		}
 
 		output[key] = values
+		positions[key] = position
 	}
 
 	if err := scanner.Err(); err != nil {
-		return nil, err
+		return LoadedFile{}, err
+	}
+
+	if len(lineErrors) > 0 {
+		return LoadedFile{}, lineErrors
 	}
 
-	return output, nil
+	return LoadedFile{Records: output, Positions: positions, Attributes: make(LoadFileAttributes)}, nil
 }
 
-func processLoadFileRecord(s string) (string, map[string]string, error) {
+// WriteRecords writes the records in the LoadFileRecords format sorted by key.
+// The values of each record are written in keyOrder first, and then the rest sorted by key.
+func WriteRecords(output io.Writer, records LoadFileRecords, keyOrder []string) error {
+	writer := bufio.NewWriter(output)
+
+	for _, key := range sortedKeys(records) {
+		line := key
+		for _, valueKey := range sortedValueKeys(records[key], keyOrder) {
+			line += " " + valueKey + "=" + records[key][valueKey]
+		}
+
+		if _, err := writer.WriteString(line + "\n"); err != nil {
+			return err
+		}
+	}
+
+	return writer.Flush()
+}
+
+// WriteFile writes the loaded file in the given format, sorted as WriteRecords does.
+// The attributes are sorted by key, the text format writes them after the values of their record
+// and returns ErrDuplicatedKey if an attribute has the same key as a value.
+func WriteFile(output io.Writer, loaded LoadedFile, format Format, keyOrder []string) error {
+	switch format {
+	case FormatText:
+		records, err := mergeAttributes(loaded.Records, loaded.Attributes)
+		if err != nil {
+			return err
+		}
+
+		return WriteRecords(output, records, keyOrder)
+	case FormatJSON, FormatYAML:
+		return writeDocument(output, loaded, format, keyOrder)
+	default:
+		return fmt.Errorf("%w: %q", ErrUnknownFormat, format)
+	}
+}
+
+// mergeAttributes returns a copy of records with the attributes of each record added to its values,
+// records is returned as is if there are no attributes.
+func mergeAttributes(records LoadFileRecords, attributes LoadFileAttributes) (LoadFileRecords, error) {
+	if len(attributes) == 0 {
+		return records, nil
+	}
+
+	merged := make(LoadFileRecords, len(records))
+	for key, values := range records {
+		merged[key] = make(map[string]string, len(values)+len(attributes[key]))
+		for valueKey, value := range values {
+			merged[key][valueKey] = value
+		}
+
+		for attributeKey, attribute := range attributes[key] {
+			if _, alreadyExist := values[attributeKey]; alreadyExist {
+				return nil, fmt.Errorf("%w: %q -> %q is both a value and an attribute", ErrDuplicatedKey, key, attributeKey)
+			}
+
+			merged[key][attributeKey] = attribute
+		}
+	}
+
+	return merged, nil
+}
+
+func sortedKeys(records map[string]map[string]string) []string {
+	keys := make([]string, 0, len(records))
+	for key := range records {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
+
+// sortedValueKeys returns the keys of values in keyOrder first, and then the rest sorted.
+func sortedValueKeys(values map[string]string, keyOrder []string) []string {
+	rank := make(map[string]int, len(keyOrder))
+	for i, key := range keyOrder {
+		rank[key] = i
+	}
+
+	valueKeys := make([]string, 0, len(values))
+	for valueKey := range values {
+		valueKeys = append(valueKeys, valueKey)
+	}
+
+	sort.Slice(valueKeys, func(i, j int) bool {
+		iRank, iRanked := rank[valueKeys[i]]
+		jRank, jRanked := rank[valueKeys[j]]
+
+		switch {
+		case iRanked && jRanked:
+			return iRank < jRank
+		case iRanked != jRanked:
+			return iRanked
+		default:
+			return valueKeys[i] < valueKeys[j]
+		}
+	})
+
+	return valueKeys
+}
+
+func processLoadFileRecord(s string, lineNumber int) (string, map[string]string, RecordPosition, *LineError) {
 	recs := make(map[string]string)
 
 	line := strings.Split(s, " ")
 
+	// Column of each word of the line, counted in characters
+	columns := make([]int, len(line))
+	for i, column := 0, 1; i < len(line); i++ {
+		columns[i] = column
+		column += utf8.RuneCountInString(line[i]) + 1
+	}
+
 	if len(line) < 1 {
-		return "", nil, ErrInvalidFormat
+		return "", nil, RecordPosition{}, &LineError{Position: Position{Line: lineNumber, Column: 1}, Err: ErrInvalidFormat}
 	}
 
 	key := line[0]
+	position := RecordPosition{Position: Position{Line: lineNumber, Column: 1}, Values: make(map[string]Position)}
 
 	// Index start at 1 since we already read the line header
 	for i := 1; i < len(line); i++ {
+		wordPosition := Position{Line: lineNumber, Column: columns[i]}
+
 		lineSplit := strings.Split(line[i], "=")
 		if len(lineSplit) != 2 {
-			return "", nil, ErrInvalidFormat
+			return "", nil, RecordPosition{}, &LineError{
+				Position: wordPosition,
+				Err:      fmt.Errorf("%w: %q is not a key=value pair", ErrInvalidFormat, line[i]),
+			}
 		}
 
 		lineKey, lineValue := lineSplit[0], lineSplit[1]
 
 		if _, alreadyExist := recs[lineKey]; alreadyExist {
-			return "", nil, fmt.Errorf("%w: %q -> %q", ErrDuplicatedKey, key, lineKey)
+			return "", nil, RecordPosition{}, &LineError{
+				Position: wordPosition,
+				Err:      fmt.Errorf("%w: %q -> %q", ErrDuplicatedKey, key, lineKey),
+			}
 		}
 
 		recs[lineKey] = lineValue
+		position.Values[lineKey] = wordPosition
 	}
 
-	return key, recs, nil
+	return key, recs, position, nil
 }
@@ -1,8 +1,10 @@
 package system
 
 import (
+	"io"
 	"io/ioutil"
 	"os"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -63,7 +65,8 @@ func TestManager_LoadFile(t *testing.T) {
 
 		_, err = manager.LoadFile(file.Name())
 		require.Error(t, err)
-		require.EqualError(t, err, ErrInvalidFormat.Error())
+		require.ErrorIs(t, err, ErrInvalidFormat)
+		require.EqualError(t, err, `line 3, column 5: invalid format: "south=Foo=west=Bee" is not a key=value pair`)
 
 		// test error handling: duplicated key
 		duplicateData := "Foo north=Bar\nFoo west=Baz"
@@ -75,3 +78,54 @@ func TestManager_LoadFile(t *testing.T) {
 		require.ErrorIs(t, err, ErrInvalidFormat)
 	})
 }
+
+func TestManager_LoadFileWithPositions(t *testing.T) {
+	manager := &Manager{OpenFunc: func(string) (io.ReadCloser, error) {
+		return io.NopCloser(strings.NewReader("Foo north=Bar west=Baz\n\nBar south=Foo\n")), nil
+	}}
+
+	records, positions, err := manager.LoadFileWithPositions("layout.txt")
+	require.NoError(t, err)
+
+	assert.Equal(t, LoadFileRecords{"Foo": {"north": "Bar", "west": "Baz"}, "Bar": {"south": "Foo"}}, records)
+	assert.Equal(t, LoadFilePositions{
+		"Foo": {Position: Position{Line: 1, Column: 1}, Values: map[string]Position{"north": {1, 5}, "west": {1, 15}}},
+		"Bar": {Position: Position{Line: 3, Column: 1}, Values: map[string]Position{"south": {3, 5}}},
+	}, positions)
+}
+
+func TestManager_LoadFile_EveryLineError(t *testing.T) {
+	manager := &Manager{OpenFunc: func(string) (io.ReadCloser, error) {
+		return io.NopCloser(strings.NewReader("Foo north=Bar west\nBar south=Foo\nBar west=Baz\nBaz east=Bar east=Foo\n")), nil
+	}}
+
+	_, err := manager.LoadFile("layout.txt")
+
+	var lineErrors LineErrors
+	require.ErrorAs(t, err, &lineErrors)
+	require.Len(t, lineErrors, 3)
+
+	assert.Equal(t, Position{Line: 1, Column: 15}, lineErrors[0].Position)
+	assert.ErrorIs(t, lineErrors[0], ErrInvalidFormat)
+
+	assert.Equal(t, Position{Line: 3, Column: 1}, lineErrors[1].Position)
+	assert.ErrorIs(t, lineErrors[1], ErrDuplicatedKey)
+
+	assert.Equal(t, Position{Line: 4, Column: 14}, lineErrors[2].Position)
+	assert.ErrorIs(t, lineErrors[2], ErrDuplicatedKey)
+
+	assert.ErrorIs(t, err, ErrInvalidFormat)
+	assert.ErrorIs(t, err, ErrDuplicatedKey)
+}
+
+func TestWriteRecords(t *testing.T) {
+	var output strings.Builder
+
+	err := WriteRecords(&output, LoadFileRecords{
+		"Foo": {"b": "2", "z": "3", "a": "1"},
+		"Bar": {},
+	}, []string{"z"})
+	require.NoError(t, err)
+
+	assert.Equal(t, "Bar\nFoo z=3 a=1 b=2\n", output.String())
+}
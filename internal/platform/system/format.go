@@ -0,0 +1,65 @@
+package system
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// Format is the syntax in which a records file is written.
+type Format string
+
+const (
+	// FormatAuto picks the format from the file extension, see FormatFromPath.
+	FormatAuto Format = ""
+	FormatText Format = "text"
+	FormatJSON Format = "json"
+	FormatYAML Format = "yaml"
+)
+
+var ErrUnknownFormat = errors.New("unknown format")
+
+// LoadFileAttributes has the optional attributes of each record of LoadFileRecords, using the same keys.
+// The text format has no way to tell them apart from the values, so they are loaded as values.
+type LoadFileAttributes = map[string]map[string]string
+
+// LoadedFile is everything read from a records file.
+type LoadedFile struct {
+	Records    LoadFileRecords
+	Positions  LoadFilePositions
+	Attributes LoadFileAttributes
+}
+
+// ParseFormat returns the format with the given name, an empty name is FormatAuto.
+func ParseFormat(name string) (Format, error) {
+	switch format := Format(strings.ToLower(name)); format {
+	case FormatAuto, FormatText, FormatJSON, FormatYAML:
+		return format, nil
+	case "yml":
+		return FormatYAML, nil
+	default:
+		return FormatAuto, fmt.Errorf("%w: %q, must be text, json or yaml", ErrUnknownFormat, name)
+	}
+}
+
+// FormatFromPath picks the format from the extension of path, every unknown extension is FormatText.
+func FormatFromPath(path string) Format {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		return FormatJSON
+	case ".yaml", ".yml":
+		return FormatYAML
+	default:
+		return FormatText
+	}
+}
+
+// FormatOf returns the format in which the manager reads path.
+func (manager *Manager) FormatOf(path string) Format {
+	if manager.Format != FormatAuto {
+		return manager.Format
+	}
+
+	return FormatFromPath(path)
+}
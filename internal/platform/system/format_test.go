@@ -0,0 +1,32 @@
+package system
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseFormat(t *testing.T) {
+	for name, expected := range map[string]Format{"": FormatAuto, "text": FormatText, "JSON": FormatJSON, "yaml": FormatYAML, "yml": FormatYAML} {
+		format, err := ParseFormat(name)
+		require.NoError(t, err)
+		assert.Equal(t, expected, format, name)
+	}
+
+	_, err := ParseFormat("xml")
+	assert.ErrorIs(t, err, ErrUnknownFormat)
+}
+
+func TestManager_FormatOf(t *testing.T) {
+	manager := NewManager()
+
+	assert.Equal(t, FormatText, manager.FormatOf("world.txt"))
+	assert.Equal(t, FormatText, manager.FormatOf("world"))
+	assert.Equal(t, FormatJSON, manager.FormatOf("world.JSON"))
+	assert.Equal(t, FormatYAML, manager.FormatOf("world.yml"))
+	assert.Equal(t, FormatYAML, manager.FormatOf("world.yaml"))
+
+	manager.Format = FormatJSON
+	assert.Equal(t, FormatJSON, manager.FormatOf("world.txt"))
+}
@@ -7,6 +7,9 @@ import (
 
 type Manager struct {
 	OpenFunc func(string) (io.ReadCloser, error)
+
+	// Format in which files are read, FormatAuto picks it from each file extension.
+	Format Format
 }
 
 func NewManager() *Manager {
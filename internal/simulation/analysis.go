@@ -0,0 +1,66 @@
+package simulation
+
+import (
+	"sort"
+)
+
+// LayoutAnalysis describes the shape of the roads between the cities still standing,
+// and which of them hold the map together.
+type LayoutAnalysis struct {
+	Cities     int `json:"cities"`
+	Roads      int `json:"roads"`
+	Components int `json:"components"`
+	Cycles     int `json:"cycles"`
+
+	// Diameter is the most roads between two connected cities, nil if it wasn't measured.
+	Diameter *int `json:"diameter,omitempty"`
+
+	// CriticalCities split the map if they are destroyed, CriticalRoads if they are cut. Both are sorted by city.
+	CriticalCities []string `json:"critical_cities"`
+	CriticalRoads  []Road   `json:"critical_roads"`
+
+	// Degrees is sorted by amount of roads.
+	Degrees []DegreeCount `json:"degrees"`
+}
+
+// DegreeCount is the amount of cities with the same amount of roads leaving them.
+type DegreeCount struct {
+	Roads  int `json:"roads"`
+	Cities int `json:"cities"`
+}
+
+// AnalyzeLayout returns the analysis of the cities still standing. Measuring the diameter goes through the whole
+// map from every city, so it can be skipped on huge maps.
+func (invasion Invasion) AnalyzeLayout(diameter bool) LayoutAnalysis {
+	analysis := invasion.planet.Analyze()
+
+	layoutAnalysis := LayoutAnalysis{
+		Cities:         analysis.Cities,
+		Roads:          analysis.Roads,
+		Components:     analysis.Components,
+		Cycles:         analysis.Cycles,
+		CriticalCities: analysis.CriticalCities,
+		CriticalRoads:  make([]Road, 0, len(analysis.CriticalRoads)),
+		Degrees:        make([]DegreeCount, 0, len(analysis.Degrees)),
+	}
+
+	if diameter {
+		roads := invasion.planet.Diameter()
+		layoutAnalysis.Diameter = &roads
+	}
+
+	for _, road := range analysis.CriticalRoads {
+		layoutAnalysis.CriticalRoads = append(layoutAnalysis.CriticalRoads,
+			Road{City: road.From, Direction: invasion.planet.Directions().Name(road.Direction), To: road.To})
+	}
+
+	for roads, cities := range analysis.Degrees {
+		layoutAnalysis.Degrees = append(layoutAnalysis.Degrees, DegreeCount{Roads: roads, Cities: cities})
+	}
+
+	sort.Slice(layoutAnalysis.Degrees, func(i, j int) bool {
+		return layoutAnalysis.Degrees[i].Roads < layoutAnalysis.Degrees[j].Roads
+	})
+
+	return layoutAnalysis
+}
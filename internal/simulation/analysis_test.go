@@ -0,0 +1,35 @@
+package simulation
+
+import (
+	"testing"
+
+	"github.com/jattento/alien-invasion-simulator/internal/earth"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInvasion_AnalyzeLayout(t *testing.T) {
+	// A triangle with a tail, the tail hangs from C
+	invasion, err := NewInvasionFromLayout(map[string]map[earth.Direction]string{
+		"A": {earth.East: "B", earth.West: "C"},
+		"B": {earth.West: "A", earth.South: "C"},
+		"C": {earth.North: "B", earth.East: "A", earth.South: "D"},
+		"D": {earth.North: "C"},
+	}, 0, 10, 1)
+	require.NoError(t, err)
+
+	analysis := invasion.AnalyzeLayout(false)
+	assert.Equal(t, LayoutAnalysis{
+		Cities:         4,
+		Roads:          4,
+		Components:     1,
+		Cycles:         1,
+		CriticalCities: []string{"C"},
+		CriticalRoads:  []Road{{City: "C", Direction: "south", To: "D"}},
+		Degrees:        []DegreeCount{{Roads: 1, Cities: 1}, {Roads: 2, Cities: 2}, {Roads: 3, Cities: 1}},
+	}, analysis)
+
+	analysis = invasion.AnalyzeLayout(true)
+	require.NotNil(t, analysis.Diameter)
+	assert.Equal(t, 2, *analysis.Diameter)
+}
@@ -0,0 +1,188 @@
+package simulation
+
+import (
+	"sort"
+	"strconv"
+
+	"github.com/jattento/alien-invasion-simulator/internal/earth"
+	"github.com/jattento/alien-invasion-simulator/internal/platform/system"
+)
+
+// Keys of the city attributes.
+const (
+	_populationAttribute = "pop"
+	_defenseAttribute    = "def"
+	_terrainAttribute    = "terrain"
+)
+
+// _attributesOrder is the order in which the attributes of a city are written in the text format, after its roads.
+var _attributesOrder = []string{_populationAttribute, _defenseAttribute, _terrainAttribute}
+
+// SplitCityAttributes returns the loaded file with the city attributes written among the roads,
+// as the text format does, moved to the attributes. The records of loaded are not modified.
+func SplitCityAttributes(loaded system.LoadedFile) system.LoadedFile {
+	split := system.LoadedFile{
+		Records:    make(system.LoadFileRecords, len(loaded.Records)),
+		Positions:  make(system.LoadFilePositions, len(loaded.Positions)),
+		Attributes: make(system.LoadFileAttributes, len(loaded.Attributes)),
+	}
+
+	for city, position := range loaded.Positions {
+		split.Positions[city] = position
+	}
+
+	for city, attributes := range loaded.Attributes {
+		split.Attributes[city] = attributes
+	}
+
+	for city, values := range loaded.Records {
+		split.Records[city] = values
+
+		if !hasAttributes(values) {
+			continue
+		}
+
+		roads := make(map[string]string, len(values))
+		attributes := make(map[string]string, len(_attributesOrder)+len(loaded.Attributes[city]))
+		for key, value := range loaded.Attributes[city] {
+			attributes[key] = value
+		}
+
+		original := loaded.Positions[city]
+		position := system.RecordPosition{
+			Position:   original.Position,
+			Values:     make(map[string]system.Position),
+			Attributes: make(map[string]system.Position),
+		}
+
+		for key, attributePosition := range original.Attributes {
+			position.Attributes[key] = attributePosition
+		}
+
+		for key, value := range values {
+			if isAttribute(key) {
+				attributes[key] = value
+				position.Attributes[key] = original.Values[key]
+			} else {
+				roads[key] = value
+				position.Values[key] = original.Values[key]
+			}
+		}
+
+		split.Records[city], split.Attributes[city] = roads, attributes
+		if _, located := loaded.Positions[city]; located {
+			split.Positions[city] = position
+		}
+	}
+
+	return split
+}
+
+func hasAttributes(values map[string]string) bool {
+	for key := range values {
+		if isAttribute(key) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func isAttribute(key string) bool {
+	for _, attribute := range _attributesOrder {
+		if key == attribute {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ParseCityAttributes returns the attributes of every city that has any, or a *LayoutError with every invalid one.
+// positions is optional and only used to locate the problems.
+func ParseCityAttributes(fileAttributes system.LoadFileAttributes, positions system.LoadFilePositions) (map[string]earth.CityAttributes, error) {
+	cityAttributes, problems := parseCityAttributes(fileAttributes, positions)
+
+	if err := newLayoutError(problems); err != nil {
+		return nil, err
+	}
+
+	return cityAttributes, nil
+}
+
+func parseCityAttributes(fileAttributes system.LoadFileAttributes, positions system.LoadFilePositions) (map[string]earth.CityAttributes, []LayoutProblem) {
+	cityAttributes := make(map[string]earth.CityAttributes)
+	problems := make([]LayoutProblem, 0)
+
+	cities := make([]string, 0, len(fileAttributes))
+	for city := range fileAttributes {
+		cities = append(cities, city)
+	}
+	sort.Strings(cities)
+
+	for _, city := range cities {
+		if len(fileAttributes[city]) == 0 {
+			continue
+		}
+
+		keys := make([]string, 0, len(fileAttributes[city]))
+		for key := range fileAttributes[city] {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		var attributes earth.CityAttributes
+
+		for _, key := range keys {
+			value := fileAttributes[city][key]
+
+			report := func(reason string) {
+				position := positions[city].Attributes[key]
+				problems = append(problems, LayoutProblem{
+					Line:      position.Line,
+					Column:    position.Column,
+					City:      city,
+					Direction: key,
+					Reason:    reason,
+				})
+			}
+
+			switch key {
+			case _populationAttribute, _defenseAttribute:
+				amount, err := strconv.Atoi(value)
+				if err != nil || amount < 0 {
+					report("must be an amount of at least 0")
+					continue
+				}
+
+				if key == _populationAttribute {
+					attributes.Population = amount
+				} else {
+					attributes.Defense = amount
+				}
+			case _terrainAttribute:
+				terrain, err := earth.ParseTerrain(value)
+				if err != nil {
+					report(err.Error())
+					continue
+				}
+
+				attributes.Terrain = terrain
+			default:
+				report("unknown attribute, it must be one of pop, def or terrain")
+			}
+		}
+
+		cityAttributes[city] = attributes
+	}
+
+	return cityAttributes, problems
+}
+
+// ValidateCityFile works as ValidateCityLayout but also checks the city attributes,
+// the file must have been split with SplitCityAttributes.
+func ValidateCityFile(loaded system.LoadedFile, directions *earth.Directions) error {
+	_, attributeProblems := parseCityAttributes(loaded.Attributes, loaded.Positions)
+
+	return newLayoutError(append(layoutProblems(loaded.Records, loaded.Positions, directions), attributeProblems...))
+}
@@ -0,0 +1,93 @@
+package simulation
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/jattento/alien-invasion-simulator/internal/earth"
+	"github.com/jattento/alien-invasion-simulator/internal/platform/system"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func textManager(content string) *system.Manager {
+	return &system.Manager{OpenFunc: func(string) (io.ReadCloser, error) {
+		return io.NopCloser(strings.NewReader(content)), nil
+	}}
+}
+
+func TestSplitCityAttributes(t *testing.T) {
+	loaded, err := textManager("Foo north=Bar pop=120000 def=3\nBar south=Foo terrain=mountain\n").Load("layout.txt")
+	require.NoError(t, err)
+
+	split := SplitCityAttributes(loaded)
+
+	assert.Equal(t, system.LoadFileRecords{"Foo": {"north": "Bar"}, "Bar": {"south": "Foo"}}, split.Records)
+	assert.Equal(t, system.LoadFileAttributes{"Foo": {"pop": "120000", "def": "3"}, "Bar": {"terrain": "mountain"}}, split.Attributes)
+	assert.Equal(t, system.RecordPosition{
+		Position:   system.Position{Line: 1, Column: 1},
+		Values:     map[string]system.Position{"north": {Line: 1, Column: 5}},
+		Attributes: map[string]system.Position{"pop": {Line: 1, Column: 15}, "def": {Line: 1, Column: 26}},
+	}, split.Positions["Foo"])
+
+	// The loaded file is left as it was
+	assert.Equal(t, "3", loaded.Records["Foo"]["def"])
+}
+
+func TestParseCityAttributes(t *testing.T) {
+	attributes, err := ParseCityAttributes(system.LoadFileAttributes{
+		"Foo": {"pop": "120000", "def": "3"},
+		"Bar": {"terrain": "swamp"},
+		"Baz": {},
+	}, nil)
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]earth.CityAttributes{
+		"Foo": {Population: 120000, Defense: 3},
+		"Bar": {Terrain: earth.TerrainSwamp},
+	}, attributes)
+
+	_, err = ParseCityAttributes(system.LoadFileAttributes{"Foo": {"pop": "-1", "def": "x", "terrain": "desert", "size": "3"}}, nil)
+
+	var layoutError *LayoutError
+	require.True(t, errors.As(err, &layoutError))
+	assert.Len(t, layoutError.Problems, 4)
+}
+
+func TestValidateCityFile(t *testing.T) {
+	loaded, err := textManager("Foo north=Bar def=x\nBar south=Foo west=Baz\n").Load("layout.txt")
+	require.NoError(t, err)
+
+	err = ValidateCityFile(SplitCityAttributes(loaded), earth.NewDirections())
+
+	var layoutError *LayoutError
+	require.True(t, errors.As(err, &layoutError))
+	assert.Equal(t, []LayoutProblem{
+		{Line: 1, Column: 15, City: "Foo", Direction: "def", Reason: "must be an amount of at least 0"},
+		{Line: 2, Column: 15, City: "Bar", Direction: "west", Reason: `"Baz" has no record`},
+	}, layoutError.Problems)
+}
+
+func TestLoadCityLayoutWithAttributes(t *testing.T) {
+	cityLayout, attributes, err := LoadCityLayoutWithAttributes("layout.txt",
+		textManager("Foo north=Bar pop=10\nBar south=Foo terrain=swamp\n"), 0, 0, nil, earth.NewDirections())
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]map[earth.Direction]string{"Foo": {earth.North: "Bar"}, "Bar": {earth.South: "Foo"}}, cityLayout)
+	assert.Equal(t, map[string]earth.CityAttributes{"Foo": {Population: 10}, "Bar": {Terrain: earth.TerrainSwamp}}, attributes)
+}
+
+func TestWriteCityLayout_Attributes(t *testing.T) {
+	var output bytes.Buffer
+
+	err := WriteCityLayout(&output, system.LoadedFile{
+		Records:    system.LoadFileRecords{"Foo": {"west": "Baz", "north": "Bar"}},
+		Attributes: system.LoadFileAttributes{"Foo": {"terrain": "swamp", "def": "3", "pop": "120000"}},
+	}, system.FormatText)
+	require.NoError(t, err)
+
+	assert.Equal(t, "Foo north=Bar west=Baz pop=120000 def=3 terrain=swamp\n", output.String())
+}
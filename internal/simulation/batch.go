@@ -0,0 +1,243 @@
+package simulation
+
+import (
+	"math/rand"
+	"sort"
+	"sync"
+
+	"github.com/jattento/alien-invasion-simulator/internal/earth"
+	"github.com/jattento/alien-invasion-simulator/internal/platform/numeric"
+)
+
+// BatchConfig describes a set of independent invasions run over the same city layout.
+type BatchConfig struct {
+	Runs         int
+	Workers      int
+	AliensAmount int
+	TickLimit    int
+
+	// Seed is used to derive the seed of every run, so the whole batch can be reproduced.
+	Seed int64
+
+	// Options are applied to every run.
+	Options []Option
+}
+
+// BatchReport aggregates the outcome of every run of a batch.
+type BatchReport struct {
+	Runs int   `json:"runs"`
+	Seed int64 `json:"seed"`
+
+	// Cities is sorted by name and MostDestroyed by destruction count.
+	Cities        []CityStats `json:"cities"`
+	MostDestroyed []CityStats `json:"most_destroyed"`
+
+	// LastBattleDay only takes into account runs with at least one battle.
+	LastBattleDay      Distribution `json:"last_battle_day"`
+	RunsWithoutBattles int          `json:"runs_without_battles"`
+
+	// AliensTrapped are the aliens alive at the end of each run that can't meet any other alien anymore.
+	AliensKilled  Distribution `json:"aliens_killed"`
+	AliensTrapped Distribution `json:"aliens_trapped"`
+
+	// HumanCasualties is the population of the cities destroyed in each run.
+	HumanCasualties Distribution `json:"human_casualties"`
+}
+
+// CityStats describes how a city did across every run of a batch.
+type CityStats struct {
+	Name      string  `json:"name"`
+	Destroyed int     `json:"destroyed"`
+	Survival  float64 `json:"survival"`
+}
+
+// Distribution summarizes a set of values collected from the runs of a batch.
+type Distribution struct {
+	Mean float64 `json:"mean"`
+	Min  int     `json:"min"`
+	P50  int     `json:"p50"`
+	P90  int     `json:"p90"`
+	P99  int     `json:"p99"`
+	Max  int     `json:"max"`
+}
+
+const _mostDestroyedLimit = 10
+
+// runResult is the outcome of a single invasion of a batch.
+type runResult struct {
+	destroyedCities []string
+	lastBattleDay   int
+	killed          int
+	trapped         int
+	humanCasualties int
+}
+
+// RunBatch runs config.Runs invasions over cityLayout in config.Workers goroutines and aggregates their outcome.
+func RunBatch(cityLayout map[string]map[earth.Direction]string, config BatchConfig) (BatchReport, error) {
+	if config.Workers < 1 {
+		config.Workers = 1
+	}
+
+	// Run seeds are drawn before starting, so the results don't depend on how the runs are scheduled
+	randomizer := rand.New(rand.NewSource(config.Seed))
+	seeds := make([]int64, config.Runs)
+	for i := range seeds {
+		seeds[i] = randomizer.Int63()
+	}
+
+	results := make([]runResult, config.Runs)
+	runIndexes := make(chan int)
+
+	var (
+		wg       sync.WaitGroup
+		errOnce  sync.Once
+		firstErr error
+	)
+
+	for i := 0; i < config.Workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			for runIndex := range runIndexes {
+				result, err := runOnce(cityLayout, config, seeds[runIndex])
+				if err != nil {
+					errOnce.Do(func() { firstErr = err })
+					continue
+				}
+
+				results[runIndex] = result
+			}
+		}()
+	}
+
+	for i := 0; i < config.Runs; i++ {
+		runIndexes <- i
+	}
+	close(runIndexes)
+
+	wg.Wait()
+
+	if firstErr != nil {
+		return BatchReport{}, firstErr
+	}
+
+	return aggregate(cityLayout, config, results), nil
+}
+
+func runOnce(cityLayout map[string]map[earth.Direction]string, config BatchConfig, seed int64) (runResult, error) {
+	invasion, err := NewInvasionFromLayout(cityLayout, config.AliensAmount, config.TickLimit, seed, config.Options...)
+	if err != nil {
+		return runResult{}, err
+	}
+
+	result := runResult{lastBattleDay: -1}
+
+	for keepTicking := true; keepTicking && invasion.AliensAlive()+invasion.AliensIncoming() > 0; {
+		var report TickReport
+		keepTicking, report = invasion.Tick()
+
+		for _, battle := range report.Battles {
+			if battle.CityDestroyed {
+				result.destroyedCities = append(result.destroyedCities, battle.City)
+			}
+
+			result.killed += len(battle.Casualties)
+			result.humanCasualties += battle.HumanCasualties
+			result.lastBattleDay = report.Tick
+		}
+
+		for _, battle := range report.RoadBattles {
+			result.killed += len(battle.Casualties)
+			result.lastBattleDay = report.Tick
+		}
+
+		for _, defense := range report.Defenses {
+			result.killed += len(defense.Casualties)
+		}
+	}
+
+	result.trapped = invasion.planet.AliensTrapped()
+
+	return result, nil
+}
+
+func aggregate(cityLayout map[string]map[earth.Direction]string, config BatchConfig, results []runResult) BatchReport {
+	report := BatchReport{Runs: config.Runs, Seed: config.Seed}
+
+	destroyedCount := make(map[string]int)
+	for cityName := range cityLayout {
+		destroyedCount[cityName] = 0
+	}
+
+	lastBattleDays := make([]int, 0, len(results))
+	killed := make([]int, 0, len(results))
+	trapped := make([]int, 0, len(results))
+	humanCasualties := make([]int, 0, len(results))
+
+	for _, result := range results {
+		for _, cityName := range result.destroyedCities {
+			destroyedCount[cityName]++
+		}
+
+		if result.lastBattleDay < 0 {
+			report.RunsWithoutBattles++
+		} else {
+			lastBattleDays = append(lastBattleDays, result.lastBattleDay)
+		}
+
+		killed = append(killed, result.killed)
+		trapped = append(trapped, result.trapped)
+		humanCasualties = append(humanCasualties, result.humanCasualties)
+	}
+
+	for cityName, destroyed := range destroyedCount {
+		survival := 0.0
+		if config.Runs > 0 {
+			survival = 1 - float64(destroyed)/float64(config.Runs)
+		}
+
+		report.Cities = append(report.Cities, CityStats{Name: cityName, Destroyed: destroyed, Survival: survival})
+	}
+
+	sort.Slice(report.Cities, func(i, j int) bool { return report.Cities[i].Name < report.Cities[j].Name })
+
+	mostDestroyed := make([]CityStats, 0, len(report.Cities))
+	for _, cityStats := range report.Cities {
+		if cityStats.Destroyed > 0 {
+			mostDestroyed = append(mostDestroyed, cityStats)
+		}
+	}
+
+	// Stable so cities destroyed the same amount of times keep the alphabetical order
+	sort.SliceStable(mostDestroyed, func(i, j int) bool { return mostDestroyed[i].Destroyed > mostDestroyed[j].Destroyed })
+
+	if len(mostDestroyed) > _mostDestroyedLimit {
+		mostDestroyed = mostDestroyed[:_mostDestroyedLimit]
+	}
+
+	report.MostDestroyed = mostDestroyed
+	report.LastBattleDay = newDistribution(lastBattleDays)
+	report.AliensKilled = newDistribution(killed)
+	report.AliensTrapped = newDistribution(trapped)
+	report.HumanCasualties = newDistribution(humanCasualties)
+
+	return report
+}
+
+func newDistribution(values []int) Distribution {
+	if len(values) == 0 {
+		return Distribution{}
+	}
+
+	sort.Ints(values)
+
+	return Distribution{
+		Mean: numeric.Mean(values),
+		Min:  values[0],
+		P50:  numeric.Percentile(values, 50),
+		P90:  numeric.Percentile(values, 90),
+		P99:  numeric.Percentile(values, 99),
+		Max:  values[len(values)-1],
+	}
+}
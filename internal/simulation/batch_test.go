@@ -0,0 +1,59 @@
+package simulation
+
+import (
+	"testing"
+
+	"github.com/jattento/alien-invasion-simulator/internal/earth"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunBatch(t *testing.T) {
+	cityLayout := map[string]map[earth.Direction]string{
+		"A": {earth.East: "B"},
+		"B": {earth.West: "A", earth.East: "C"},
+		"C": {earth.West: "B"},
+	}
+
+	config := BatchConfig{Runs: 40, Workers: 4, AliensAmount: 2, TickLimit: 50, Seed: 3}
+
+	report, err := RunBatch(cityLayout, config)
+	require.NoError(t, err)
+
+	assert.Equal(t, 40, report.Runs)
+	require.Len(t, report.Cities, 3)
+	assert.Equal(t, "A", report.Cities[0].Name)
+
+	for _, cityStats := range report.Cities {
+		assert.InDelta(t, 1-float64(cityStats.Destroyed)/40, cityStats.Survival, 0.0001)
+	}
+
+	for i := 1; i < len(report.MostDestroyed); i++ {
+		assert.GreaterOrEqual(t, report.MostDestroyed[i-1].Destroyed, report.MostDestroyed[i].Destroyed)
+	}
+
+	// Two aliens either kill each other or can still meet, none of them is ever trapped on a line
+	assert.Equal(t, 0.0, report.AliensTrapped.Mean)
+
+	// The outcome must not depend on the amount of workers
+	config.Workers = 1
+	sequentialReport, err := RunBatch(cityLayout, config)
+	require.NoError(t, err)
+	assert.Equal(t, sequentialReport, report)
+}
+
+func TestRunBatch_Trapped(t *testing.T) {
+	cityLayout := map[string]map[earth.Direction]string{"A": {}, "B": {}}
+
+	report, err := RunBatch(cityLayout, BatchConfig{Runs: 5, Workers: 2, AliensAmount: 2, TickLimit: 10, Seed: 3})
+	require.NoError(t, err)
+
+	// The aliens land at different cities without roads between them
+	assert.Equal(t, 2.0, report.AliensTrapped.Mean)
+	assert.Equal(t, 0.0, report.AliensKilled.Mean)
+}
+
+func TestRunBatch_InvalidLayout(t *testing.T) {
+	_, err := RunBatch(map[string]map[earth.Direction]string{"A": {earth.East: "Unknown"}}, BatchConfig{Runs: 3, Workers: 2})
+	assert.Error(t, err)
+}
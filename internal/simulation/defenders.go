@@ -0,0 +1,71 @@
+package simulation
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/jattento/alien-invasion-simulator/internal/earth"
+	"gopkg.in/yaml.v3"
+)
+
+var ErrInvalidDefenders = errors.New("invalid defenders")
+
+// defendersFile is the schema of a defenders file, YAML or JSON.
+//
+//	Example:
+//	defenders:
+//	  - city: New York
+//	    strength: 5
+//	  - city: Boston
+//	    name: Minutemen
+type defendersFile struct {
+	Defenders []struct {
+		Name     string `yaml:"name"`
+		City     string `yaml:"city"`
+		Strength *int   `yaml:"strength"`
+	} `yaml:"defenders"`
+}
+
+// ReadDefenders returns the garrisons of a defenders file, strength is 1 if not set.
+// Whether the cities exist is checked when the invasion is created.
+func ReadDefenders(input io.Reader) ([]earth.GarrisonPlacement, error) {
+	var file defendersFile
+
+	decoder := yaml.NewDecoder(input)
+	decoder.KnownFields(true)
+
+	if err := decoder.Decode(&file); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidDefenders, err.Error())
+	}
+
+	placements := make([]earth.GarrisonPlacement, 0, len(file.Defenders))
+	names := make(map[string]bool, len(file.Defenders))
+
+	for i, defender := range file.Defenders {
+		if defender.City == "" {
+			return nil, fmt.Errorf("%w: defender %d must have a city", ErrInvalidDefenders, i+1)
+		}
+
+		placement := earth.GarrisonPlacement{Name: defender.Name, City: defender.City, Strength: 1}
+		if defender.Strength != nil {
+			placement.Strength = *defender.Strength
+		}
+
+		if placement.Strength < 1 {
+			return nil, fmt.Errorf("%w: defender %d at %q strength must be at least 1", ErrInvalidDefenders, i+1, defender.City)
+		}
+
+		if placement.Name != "" {
+			if names[placement.Name] {
+				return nil, fmt.Errorf("%w: defender name %q is repeated", ErrInvalidDefenders, placement.Name)
+			}
+
+			names[placement.Name] = true
+		}
+
+		placements = append(placements, placement)
+	}
+
+	return placements, nil
+}
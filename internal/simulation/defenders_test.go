@@ -0,0 +1,70 @@
+package simulation
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/jattento/alien-invasion-simulator/internal/earth"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadDefenders(t *testing.T) {
+	placements, err := ReadDefenders(strings.NewReader(`defenders:
+  - city: New York
+    strength: 5
+  - city: Boston
+    name: Minutemen
+`))
+	require.NoError(t, err)
+
+	assert.Equal(t, []earth.GarrisonPlacement{
+		{City: "New York", Strength: 5},
+		{Name: "Minutemen", City: "Boston", Strength: 1},
+	}, placements)
+
+	placements, err = ReadDefenders(strings.NewReader(`{"defenders": [{"city": "Paris", "strength": 2}]}`))
+	require.NoError(t, err)
+	assert.Equal(t, []earth.GarrisonPlacement{{City: "Paris", Strength: 2}}, placements)
+
+	for _, invalid := range []string{
+		"defenders:\n  - strength: 2\n",
+		"defenders:\n  - city: Paris\n    strength: 0\n",
+		"defenders:\n  - city: Paris\n    name: a\n  - city: Rome\n    name: a\n",
+		"defenders:\n  - city: Paris\n    tanks: 2\n",
+	} {
+		_, err := ReadDefenders(strings.NewReader(invalid))
+		assert.ErrorIs(t, err, ErrInvalidDefenders, invalid)
+	}
+}
+
+func TestInvasion_Tick_Defenders(t *testing.T) {
+	cityLayout := map[string]map[earth.Direction]string{
+		"A": {earth.East: "B"},
+		"B": {earth.West: "A"},
+	}
+
+	invasion, err := NewInvasionFromLayout(cityLayout, 1, 10, 0,
+		WithGarrisons([]earth.GarrisonPlacement{{City: "A", Strength: 2}, {City: "B", Strength: 2}}))
+	require.NoError(t, err)
+
+	// The only alien spawns at a guarded city, so it dies on day zero
+	_, report := invasion.Tick()
+	require.Len(t, report.Defenses, 1)
+	assert.True(t, report.Defenses[0].Repelled)
+	assert.Equal(t, 0, invasion.AliensAlive())
+
+	garrisons := map[string]int{"A": 2, "B": 2}
+	garrisons[report.Defenses[0].City] = 1
+	assert.Equal(t, garrisons, report.Garrisons)
+
+	restored, err := RestoreInvasion(invasion.Snapshot())
+	require.NoError(t, err)
+	assert.Equal(t, "hold", restored.Snapshot().DefenderMovement)
+	assert.Len(t, restored.Snapshot().Planet.Garrisons, 2)
+
+	snapshot := invasion.Snapshot()
+	snapshot.DefenderMovement = ""
+	_, err = RestoreInvasion(snapshot)
+	assert.ErrorIs(t, err, ErrInvalidSnapshot)
+}
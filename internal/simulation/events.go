@@ -0,0 +1,274 @@
+package simulation
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/jattento/alien-invasion-simulator/internal/earth"
+)
+
+// RecordingHeader is the first line of the events stream, of type "header", it holds what is needed to replay the invasion.
+type RecordingHeader struct {
+	Seed   int64                        `json:"seed"`
+	Layout map[string]map[string]string `json:"layout"`
+
+	// Alien:City where it spawned
+	Aliens map[string]string `json:"aliens"`
+
+	// Alien:Species, the aliens of earth.DefaultSpecies aren't included
+	Species map[string]string `json:"species,omitempty"`
+}
+
+// Recording is a whole events stream read back.
+type Recording struct {
+	Header RecordingHeader
+	Events []Event
+}
+
+var ErrInvalidRecording = errors.New("invalid recording")
+
+// Event is the representation of a TickReport written to the events stream, one JSON object of type "tick" per line.
+type Event struct {
+	Tick      int           `json:"tick"`
+	Moves     []MoveEvent   `json:"moves"`
+	Battles   []BattleEvent `json:"battles"`
+	Destroyed []string      `json:"destroyed"`
+
+	RoadBattles []RoadBattleEvent `json:"road_battles,omitempty"`
+	Defenses    []DefenseEvent    `json:"defenses,omitempty"`
+	Waves       []WaveEvent       `json:"waves,omitempty"`
+
+	// City:Strength of the garrisons at it after the tick
+	Garrisons map[string]int `json:"garrisons,omitempty"`
+
+	// StopReason is why a stop condition ended the invasion at this tick
+	StopReason string `json:"stop_reason,omitempty"`
+
+	// Fragmentation isn't included once every city was destroyed
+	Fragmentation *FragmentationEvent `json:"fragmentation,omitempty"`
+}
+
+// MoveEvent Direction is "stayed" if the alien didn't leave the city.
+type MoveEvent struct {
+	Alien     string `json:"alien"`
+	From      string `json:"from"`
+	To        string `json:"to"`
+	Direction string `json:"direction"`
+}
+
+// BattleEvent Species has the species of each of the Aliens, in the same order.
+// Casualties are the Aliens that died, recordings without them are of invasions in which every alien died.
+// HumanCasualties is the population of the city if it was destroyed.
+type BattleEvent struct {
+	City       string   `json:"city"`
+	Aliens     []string `json:"aliens"`
+	Species    []string `json:"species,omitempty"`
+	Winner     string   `json:"winner,omitempty"`
+	Casualties []string `json:"casualties,omitempty"`
+
+	HumanCasualties int `json:"human_casualties,omitempty"`
+}
+
+// RoadBattleEvent is a battle between two aliens that crossed the road between From and To
+// in opposite directions, the first of the Aliens was going from From to To.
+type RoadBattleEvent struct {
+	From       string   `json:"from"`
+	To         string   `json:"to"`
+	Aliens     []string `json:"aliens"`
+	Species    []string `json:"species,omitempty"`
+	Winner     string   `json:"winner,omitempty"`
+	Casualties []string `json:"casualties"`
+}
+
+// DefenseEvent is the fight between the garrisons of a city and the aliens that reached it,
+// the city was saved if Repelled is set.
+type DefenseEvent struct {
+	City       string   `json:"city"`
+	Garrisons  []string `json:"garrisons"`
+	Aliens     []string `json:"aliens"`
+	Casualties []string `json:"casualties"`
+	Fallen     []string `json:"fallen"`
+	Losses     int      `json:"losses"`
+	Repelled   bool     `json:"repelled"`
+}
+
+// WaveEvent are the aliens of a wave that landed, Cities and Species are in the same order as Aliens.
+type WaveEvent struct {
+	Aliens  []string `json:"aliens"`
+	Cities  []string `json:"cities"`
+	Species []string `json:"species"`
+}
+
+// FragmentationEvent is how the destroyed cities split the world after the tick into components, groups of
+// standing cities connected by roads. Aliens are the ones of every component with aliens, from the largest one.
+type FragmentationEvent struct {
+	Components int                    `json:"components"`
+	Largest    int                    `json:"largest"`
+	Aliens     []ComponentAliensEvent `json:"aliens"`
+}
+
+// ComponentAliensEvent are the aliens at the cities of a component.
+type ComponentAliensEvent struct {
+	Cities int `json:"cities"`
+	Aliens int `json:"aliens"`
+}
+
+const _stayed = "stayed"
+
+// The type of each line of the events stream, so the header can be told apart from the ticks.
+const (
+	_headerLine = "header"
+	_tickLine   = "tick"
+)
+
+// eventLine is a line of the events stream, only one of RecordingHeader and Event is set and its fields
+// are written next to the Type.
+type eventLine struct {
+	Type string `json:"type"`
+	*RecordingHeader
+	*Event
+}
+
+// EventWriter writes every tick of an invasion as a JSON line, naming the moves after directions.
+type EventWriter struct {
+	encoder    *json.Encoder
+	directions *earth.Directions
+}
+
+func NewEventWriter(output io.Writer, directions *earth.Directions) *EventWriter {
+	return &EventWriter{encoder: json.NewEncoder(output), directions: directions}
+}
+
+// WriteHeader must be called once before writing the first tick.
+func (writer *EventWriter) WriteHeader(header RecordingHeader) error {
+	return writer.encoder.Encode(eventLine{Type: _headerLine, RecordingHeader: &header})
+}
+
+// Write encodes the report as a single JSON line.
+func (writer *EventWriter) Write(report TickReport) error {
+	event := NewEvent(report, writer.directions)
+
+	return writer.encoder.Encode(eventLine{Type: _tickLine, Event: &event})
+}
+
+// NewEvent converts a TickReport into its events stream representation, with the names of directions.
+func NewEvent(report TickReport, directions *earth.Directions) Event {
+	event := Event{
+		Tick:      report.Tick,
+		Moves:     make([]MoveEvent, 0, len(report.Movements)),
+		Battles:   make([]BattleEvent, 0, len(report.Battles)),
+		Destroyed: make([]string, 0, len(report.Battles)),
+		Garrisons: report.Garrisons,
+
+		StopReason: report.StopReason,
+	}
+
+	if report.Fragmentation.Components > 0 {
+		event.Fragmentation = &FragmentationEvent{
+			Components: report.Fragmentation.Components,
+			Largest:    report.Fragmentation.Largest,
+			Aliens:     make([]ComponentAliensEvent, 0, len(report.Fragmentation.Aliens)),
+		}
+
+		for _, component := range report.Fragmentation.Aliens {
+			event.Fragmentation.Aliens = append(event.Fragmentation.Aliens, ComponentAliensEvent(component))
+		}
+	}
+
+	for _, movement := range report.Movements {
+		direction := _stayed
+		if !movement.Stayed {
+			direction = directions.Name(movement.Direction)
+		}
+
+		event.Moves = append(event.Moves, MoveEvent{
+			Alien:     movement.Alien,
+			From:      movement.From,
+			To:        movement.To,
+			Direction: direction,
+		})
+	}
+
+	for _, battle := range report.Battles {
+		event.Battles = append(event.Battles, BattleEvent{
+			City:       battle.City,
+			Aliens:     battle.InvolvedAliens,
+			Species:    battle.Species,
+			Winner:     battle.Winner,
+			Casualties: battle.Casualties,
+
+			HumanCasualties: battle.HumanCasualties,
+		})
+
+		if battle.CityDestroyed {
+			event.Destroyed = append(event.Destroyed, battle.City)
+		}
+	}
+
+	for _, defense := range report.Defenses {
+		event.Defenses = append(event.Defenses, DefenseEvent(defense))
+	}
+
+	for _, wave := range report.Waves {
+		event.Waves = append(event.Waves, WaveEvent(wave))
+	}
+
+	for _, battle := range report.RoadBattles {
+		event.RoadBattles = append(event.RoadBattles, RoadBattleEvent{
+			From:       battle.From,
+			To:         battle.To,
+			Aliens:     battle.InvolvedAliens,
+			Species:    battle.Species,
+			Winner:     battle.Winner,
+			Casualties: battle.Casualties,
+		})
+	}
+
+	return event
+}
+
+// ReadRecording reads an events stream written by EventWriter, header included.
+func ReadRecording(input io.Reader) (Recording, error) {
+	var recording Recording
+
+	scanner := bufio.NewScanner(input)
+
+	// Ticks with many aliens don't fit in the default buffer
+	scanner.Buffer(make([]byte, 0, bufio.MaxScanTokenSize), 64*1024*1024)
+
+	if !scanner.Scan() {
+		if err := scanner.Err(); err != nil {
+			return Recording{}, err
+		}
+
+		return Recording{}, fmt.Errorf("%w: missing header", ErrInvalidRecording)
+	}
+
+	header := eventLine{RecordingHeader: &recording.Header}
+	if err := json.Unmarshal(scanner.Bytes(), &header); err != nil || header.Type != _headerLine || recording.Header.Layout == nil {
+		return Recording{}, fmt.Errorf("%w: line 1 is not a header", ErrInvalidRecording)
+	}
+
+	for line := 2; scanner.Scan(); line++ {
+		var event Event
+		tick := eventLine{Event: &event}
+		if err := json.Unmarshal(scanner.Bytes(), &tick); err != nil {
+			return Recording{}, fmt.Errorf("%w: line %d: %s", ErrInvalidRecording, line, err.Error())
+		}
+
+		if tick.Type != _tickLine {
+			return Recording{}, fmt.Errorf("%w: line %d is not a tick", ErrInvalidRecording, line)
+		}
+
+		recording.Events = append(recording.Events, event)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return Recording{}, err
+	}
+
+	return recording, nil
+}
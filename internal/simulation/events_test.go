@@ -0,0 +1,143 @@
+package simulation
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/jattento/alien-invasion-simulator/internal/earth"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEventWriter_Write(t *testing.T) {
+	var output bytes.Buffer
+	writer := NewEventWriter(&output, earth.NewDirections())
+
+	require.NoError(t, writer.Write(TickReport{Tick: 0}))
+	require.NoError(t, writer.Write(TickReport{
+		Tick: 1,
+		Movements: []earth.Movement{
+			{Alien: "A1", From: "City1", To: "City2", Direction: earth.North},
+			{Alien: "A2", From: "City3", To: "City3", Direction: 4, Stayed: true},
+		},
+		Battles: []earth.BattleReport{
+			{City: "City2", InvolvedAliens: []string{"A1", "A3"}, Casualties: []string{"A1", "A3"}, CityDestroyed: true},
+			{City: "City4", InvolvedAliens: []string{"A4", "A5"}, Winner: "A5", Casualties: []string{"A4"}},
+		},
+		RoadBattles: []earth.RoadBattleReport{
+			{InvolvedAliens: []string{"A6", "A7"}, From: "City1", To: "City3", Casualties: []string{"A6", "A7"}},
+		},
+	}))
+
+	lines := strings.Split(strings.TrimSpace(output.String()), "\n")
+	require.Len(t, lines, 2)
+
+	assert.JSONEq(t, `{"type":"tick","tick":0,"moves":[],"battles":[],"destroyed":[]}`, lines[0])
+
+	var event Event
+	require.NoError(t, json.Unmarshal([]byte(lines[1]), &event))
+	assert.Equal(t, Event{
+		Tick: 1,
+		Moves: []MoveEvent{
+			{Alien: "A1", From: "City1", To: "City2", Direction: "north"},
+			{Alien: "A2", From: "City3", To: "City3", Direction: "stayed"},
+		},
+		Battles: []BattleEvent{
+			{City: "City2", Aliens: []string{"A1", "A3"}, Casualties: []string{"A1", "A3"}},
+			{City: "City4", Aliens: []string{"A4", "A5"}, Winner: "A5", Casualties: []string{"A4"}},
+		},
+		Destroyed: []string{"City2"},
+		RoadBattles: []RoadBattleEvent{
+			{From: "City1", To: "City3", Aliens: []string{"A6", "A7"}, Casualties: []string{"A6", "A7"}},
+		},
+	}, event)
+}
+
+func TestEventWriter_Write_Waves(t *testing.T) {
+	var output bytes.Buffer
+	writer := NewEventWriter(&output, earth.NewDirections())
+
+	require.NoError(t, writer.Write(TickReport{
+		Tick:  2,
+		Waves: []earth.WaveReport{{Aliens: []string{"A1", "A2"}, Cities: []string{"City1", "City2"}, Species: []string{"common", "scout"}}},
+	}))
+
+	assert.JSONEq(t, `{"type":"tick","tick":2,"moves":[],"battles":[],"destroyed":[],"waves":[`+
+		`{"aliens":["A1","A2"],"cities":["City1","City2"],"species":["common","scout"]}]}`, output.String())
+}
+
+func TestNewEvent_StopReason(t *testing.T) {
+	assert.Equal(t, "every alien is isolated", NewEvent(TickReport{Tick: 9, StopReason: "every alien is isolated"}, earth.NewDirections()).StopReason)
+
+	encoded, err := json.Marshal(NewEvent(TickReport{Tick: 9}, earth.NewDirections()))
+	require.NoError(t, err)
+	assert.NotContains(t, string(encoded), "stop_reason", "the reason is only written at the tick a stop condition is met")
+}
+
+func TestNewEvent_Fragmentation(t *testing.T) {
+	event := NewEvent(TickReport{Tick: 3, Fragmentation: earth.Fragmentation{
+		Components: 2, Largest: 5, Aliens: []earth.ComponentAliens{{Cities: 5, Aliens: 3}},
+	}}, earth.NewDirections())
+
+	assert.Equal(t, &FragmentationEvent{Components: 2, Largest: 5, Aliens: []ComponentAliensEvent{{Cities: 5, Aliens: 3}}},
+		event.Fragmentation)
+
+	encoded, err := json.Marshal(NewEvent(TickReport{Tick: 3}, earth.NewDirections()))
+	require.NoError(t, err)
+	assert.NotContains(t, string(encoded), "fragmentation", "the fragmentation isn't written once every city was destroyed")
+}
+
+func TestReadRecording(t *testing.T) {
+	var output bytes.Buffer
+	writer := NewEventWriter(&output, earth.NewDirections())
+
+	header := RecordingHeader{
+		Seed:   7,
+		Layout: map[string]map[string]string{"City1": {"north": "City2"}, "City2": {"south": "City1"}},
+		Aliens: map[string]string{"A1": "City1"},
+	}
+
+	require.NoError(t, writer.WriteHeader(header))
+	require.NoError(t, writer.Write(TickReport{Tick: 0}))
+	require.NoError(t, writer.Write(TickReport{Tick: 1, Movements: []earth.Movement{
+		{Alien: "A1", From: "City1", To: "City2", Direction: earth.North},
+	}}))
+
+	assert.True(t, strings.HasPrefix(output.String(), `{"type":"header","seed":7,`), output.String())
+
+	recording, err := ReadRecording(&output)
+	require.NoError(t, err)
+
+	assert.Equal(t, header, recording.Header)
+	require.Len(t, recording.Events, 2)
+	assert.Equal(t, 1, recording.Events[1].Tick)
+	assert.Equal(t, "City2", recording.Events[1].Moves[0].To)
+
+	t.Run("missing header", func(t *testing.T) {
+		_, err := ReadRecording(strings.NewReader(`{"type":"tick","tick":0,"moves":[],"battles":[],"destroyed":[]}`))
+		assert.ErrorIs(t, err, ErrInvalidRecording)
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		_, err := ReadRecording(strings.NewReader(""))
+		assert.ErrorIs(t, err, ErrInvalidRecording)
+	})
+
+	t.Run("broken event", func(t *testing.T) {
+		_, err := ReadRecording(strings.NewReader(`{"type":"header","seed":1,"layout":{},"aliens":{}}` + "\n{"))
+		assert.ErrorIs(t, err, ErrInvalidRecording)
+	})
+
+	t.Run("untyped header", func(t *testing.T) {
+		_, err := ReadRecording(strings.NewReader(`{"seed":1,"layout":{},"aliens":{}}`))
+		assert.ErrorIs(t, err, ErrInvalidRecording)
+	})
+
+	t.Run("header twice", func(t *testing.T) {
+		_, err := ReadRecording(strings.NewReader(`{"type":"header","seed":1,"layout":{},"aliens":{}}` + "\n" +
+			`{"type":"header","seed":1,"layout":{},"aliens":{}}`))
+		assert.ErrorIs(t, err, ErrInvalidRecording)
+	})
+}
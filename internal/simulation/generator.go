@@ -9,7 +9,7 @@ import (
 	"github.com/jattento/alien-invasion-simulator/internal/platform/numeric"
 )
 
-func generateFile(name string, matrix, size int) error {
+func generateFile(name string, matrix, size int, randomizer *rand.Rand) error {
 	worldsSpecsFile, err := os.Create(name)
 	if err != nil {
 		return err
@@ -19,7 +19,7 @@ func generateFile(name string, matrix, size int) error {
 		_ = worldsSpecsFile.Close()
 	}()
 
-	generatedCitySpecs := generateCities(size, matrix)
+	generatedCitySpecs := generateCities(size, matrix, randomizer)
 
 	_, err = io.Copy(worldsSpecsFile, strings.NewReader(generatedCitySpecs))
 	if err != nil {
@@ -29,7 +29,7 @@ func generateFile(name string, matrix, size int) error {
 	return nil
 }
 
-func generateCities(numCities, size int) string {
+func generateCities(numCities, size int, randomizer *rand.Rand) string {
 	cities := make([][]string, size)
 	for i := 0; i < size; i++ {
 		cities[i] = make([]string, size)
@@ -43,11 +43,11 @@ func generateCities(numCities, size int) string {
 
 	// Add cities to the matrix randomly
 	for i := 0; i < numCities; i++ {
-		x := rand.Intn(size)
-		y := rand.Intn(size)
+		x := randomizer.Intn(size)
+		y := randomizer.Intn(size)
 		for cities[x][y] != "" {
-			x = rand.Intn(size)
-			y = rand.Intn(size)
+			x = randomizer.Intn(size)
+			y = randomizer.Intn(size)
 		}
 		if i < len(cityPool) {
 			cities[x][y] = cityPool[i]
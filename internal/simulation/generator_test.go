@@ -1,13 +1,14 @@
 package simulation
 
 import (
+	"math/rand"
 	"os"
 	"testing"
 )
 
 func TestGenerateFile(t *testing.T) {
 	// Test case 1: Valid input
-	err := generateFile("test1.txt", 5, 10)
+	err := generateFile("test1.txt", 5, 10, rand.New(rand.NewSource(0)))
 	if err != nil {
 		t.Errorf("generateFile() error = %v; want nil", err)
 	}
@@ -15,7 +16,7 @@ func TestGenerateFile(t *testing.T) {
 	_ = os.Remove("test1.txt")
 
 	// Test case 2: Invalid file name
-	err = generateFile("", 5, 10)
+	err = generateFile("", 5, 10, rand.New(rand.NewSource(0)))
 	if err == nil {
 		t.Errorf("generateFile() error = nil; want non-nil")
 	}
@@ -23,14 +24,23 @@ func TestGenerateFile(t *testing.T) {
 
 func TestGenerateCities(t *testing.T) {
 	// Test case 1: Valid input
-	cities := generateCities(10, 5)
+	cities := generateCities(10, 5, rand.New(rand.NewSource(0)))
 	if cities == "" {
 		t.Errorf("generateCities() = ''; want non-empty string")
 	}
 
 	// Test case 2: Invalid number of cities
-	cities = generateCities(0, 5)
+	cities = generateCities(0, 5, rand.New(rand.NewSource(0)))
 	if cities != "" {
 		t.Errorf("generateCities() = %v; want ''", cities)
 	}
 }
+
+func TestGenerateCities_SameSeedSameLayout(t *testing.T) {
+	first := generateCities(10, 5, rand.New(rand.NewSource(3)))
+	second := generateCities(10, 5, rand.New(rand.NewSource(3)))
+
+	if first != second {
+		t.Errorf("generateCities() with the same seed = %q and %q; want equal layouts", first, second)
+	}
+}
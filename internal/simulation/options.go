@@ -0,0 +1,210 @@
+package simulation
+
+import "github.com/jattento/alien-invasion-simulator/internal/earth"
+
+// Option configures the rules of an invasion.
+type Option interface {
+	apply(*options)
+}
+
+type options struct {
+	placement  earth.PlacementStrategy
+	movement   earth.MovementStrategy
+	speciesMix []earth.SpeciesShare
+	battle     earth.BattleResolver
+	collisions bool
+
+	garrisons              []earth.GarrisonPlacement
+	randomGarrisons        int
+	randomGarrisonStrength int
+	defenderMovement       earth.MovementStrategy
+
+	cityAttributes map[string]earth.CityAttributes
+
+	waves []earth.Wave
+
+	stopConditions []StopCondition
+
+	directions *earth.Directions
+}
+
+type placementOption struct {
+	placement earth.PlacementStrategy
+}
+
+func (opt placementOption) apply(opts *options) {
+	opts.placement = opt.placement
+}
+
+// WithPlacement sets where the aliens land when the invasion starts, see earth.ParsePlacement.
+func WithPlacement(placement earth.PlacementStrategy) Option {
+	return placementOption{placement: placement}
+}
+
+type movementOption struct {
+	movement earth.MovementStrategy
+}
+
+func (opt movementOption) apply(opts *options) {
+	opts.movement = opt.movement
+}
+
+// WithMovement sets how the aliens move every day, see earth.ParseMovement.
+func WithMovement(movement earth.MovementStrategy) Option {
+	return movementOption{movement: movement}
+}
+
+type speciesMixOption []earth.SpeciesShare
+
+func (mix speciesMixOption) apply(opts *options) {
+	opts.speciesMix = mix
+}
+
+// WithSpeciesMix spawns aliens of every species following the weights of the mix, see ParseSpeciesMix.
+func WithSpeciesMix(mix []earth.SpeciesShare) Option {
+	return speciesMixOption(mix)
+}
+
+type battleOption struct {
+	battle earth.BattleResolver
+}
+
+func (opt battleOption) apply(opts *options) {
+	opts.battle = opt.battle
+}
+
+// WithBattle sets how battles end, see earth.ParseBattle.
+func WithBattle(battle earth.BattleResolver) Option {
+	return battleOption{battle: battle}
+}
+
+type collisionsOption bool
+
+func (enabled collisionsOption) apply(opts *options) {
+	opts.collisions = bool(enabled)
+}
+
+// WithEnRouteCollisions makes the aliens crossing the same road in opposite directions fight on it.
+func WithEnRouteCollisions(enabled bool) Option {
+	return collisionsOption(enabled)
+}
+
+type garrisonsOption []earth.GarrisonPlacement
+
+func (placements garrisonsOption) apply(opts *options) {
+	opts.garrisons = placements
+}
+
+// WithGarrisons places human defenders at the given cities, see ReadDefenders.
+func WithGarrisons(placements []earth.GarrisonPlacement) Option {
+	return garrisonsOption(placements)
+}
+
+type randomGarrisonsOption struct {
+	amount   int
+	strength int
+}
+
+func (opt randomGarrisonsOption) apply(opts *options) {
+	opts.randomGarrisons, opts.randomGarrisonStrength = opt.amount, opt.strength
+}
+
+// WithRandomGarrisons places amount garrisons of the given strength at random cities.
+func WithRandomGarrisons(amount, strength int) Option {
+	return randomGarrisonsOption{amount: amount, strength: strength}
+}
+
+type defenderMovementOption struct {
+	movement earth.MovementStrategy
+}
+
+func (opt defenderMovementOption) apply(opts *options) {
+	opts.defenderMovement = opt.movement
+}
+
+// WithDefenderMovement sets how the garrisons move every day, see earth.ParseMovement.
+func WithDefenderMovement(movement earth.MovementStrategy) Option {
+	return defenderMovementOption{movement: movement}
+}
+
+type cityAttributesOption map[string]earth.CityAttributes
+
+func (attributes cityAttributesOption) apply(opts *options) {
+	opts.cityAttributes = attributes
+}
+
+// WithCityAttributes sets the population, defense and terrain of the cities, see ParseCityAttributes.
+// NewInvasion already sets the ones of the layout file.
+func WithCityAttributes(attributes map[string]earth.CityAttributes) Option {
+	return cityAttributesOption(attributes)
+}
+
+type wavesOption []earth.Wave
+
+func (waves wavesOption) apply(opts *options) {
+	opts.waves = waves
+}
+
+// WithWaves lands more aliens during the invasion, see earth.ParseWave.
+func WithWaves(waves []earth.Wave) Option {
+	return wavesOption(waves)
+}
+
+type stopConditionsOption []StopCondition
+
+func (conditions stopConditionsOption) apply(opts *options) {
+	opts.stopConditions = conditions
+}
+
+// WithStopConditions ends the invasion before its tick limit once any of the conditions is met,
+// see ParseStopCondition.
+func WithStopConditions(conditions []StopCondition) Option {
+	return stopConditionsOption(conditions)
+}
+
+type directionsOption struct {
+	directions *earth.Directions
+}
+
+func (opt directionsOption) apply(opts *options) {
+	opts.directions = opt.directions
+}
+
+// WithDirections sets the directions the roads of the layout may take, the built-in ones by default.
+// NewInvasion registers the free-form directions of the city file in them.
+func WithDirections(directions *earth.Directions) Option {
+	return directionsOption{directions: directions}
+}
+
+func newOptions(opts []Option) options {
+	o := options{placement: earth.RandomPlacement{}, movement: earth.UniformMovement{}, battle: earth.AnnihilationBattle{}, defenderMovement: earth.HoldMovement{},
+		directions: earth.NewDirections()}
+	for _, opt := range opts {
+		opt.apply(&o)
+	}
+
+	return o
+}
+
+// planetOptions are the earth options matching the invasion ones.
+// Without a species mix, restored planets keep the one of their snapshot.
+func (opts options) planetOptions() []earth.Option {
+	planetOpts := []earth.Option{
+		earth.WithPlacement(opts.placement),
+		earth.WithMovement(opts.movement),
+		earth.WithBattle(opts.battle),
+		earth.WithEnRouteCollisions(opts.collisions),
+		earth.WithGarrisons(opts.garrisons),
+		earth.WithRandomGarrisons(opts.randomGarrisons, opts.randomGarrisonStrength),
+		earth.WithDefenderMovement(opts.defenderMovement),
+		earth.WithCityAttributes(opts.cityAttributes),
+		earth.WithWaves(opts.waves),
+		earth.WithDirections(opts.directions),
+	}
+
+	if opts.speciesMix != nil {
+		planetOpts = append(planetOpts, earth.WithSpeciesMix(opts.speciesMix))
+	}
+
+	return planetOpts
+}
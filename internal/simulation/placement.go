@@ -0,0 +1,66 @@
+package simulation
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/jattento/alien-invasion-simulator/internal/earth"
+	"gopkg.in/yaml.v3"
+)
+
+var ErrInvalidPlacement = errors.New("invalid placement")
+
+// placementFile is the schema of a placement file, YAML or JSON.
+//
+//	Example:
+//	aliens:
+//	  - city: New York
+//	    count: 3
+//	  - city: Boston
+type placementFile struct {
+	Aliens []struct {
+		City  string `yaml:"city"`
+		Count *int   `yaml:"count"`
+	} `yaml:"aliens"`
+}
+
+// ReadPlacement returns the placement of a placement file, which lands one alien at each city of the file
+// in order, count aliens if it is set. Whether the cities exist is checked when the invasion is created.
+func ReadPlacement(input io.Reader) (earth.CitiesPlacement, error) {
+	var file placementFile
+
+	decoder := yaml.NewDecoder(input)
+	decoder.KnownFields(true)
+
+	if err := decoder.Decode(&file); err != nil && !errors.Is(err, io.EOF) {
+		return earth.CitiesPlacement{}, fmt.Errorf("%w: %s", ErrInvalidPlacement, err.Error())
+	}
+
+	placement := earth.CitiesPlacement{Cities: make([]string, 0, len(file.Aliens))}
+
+	for i, alien := range file.Aliens {
+		if alien.City == "" {
+			return earth.CitiesPlacement{}, fmt.Errorf("%w: alien %d must have a city", ErrInvalidPlacement, i+1)
+		}
+
+		count := 1
+		if alien.Count != nil {
+			count = *alien.Count
+		}
+
+		if count < 1 {
+			return earth.CitiesPlacement{}, fmt.Errorf("%w: aliens at %q count must be at least 1", ErrInvalidPlacement, alien.City)
+		}
+
+		for j := 0; j < count; j++ {
+			placement.Cities = append(placement.Cities, alien.City)
+		}
+	}
+
+	if len(placement.Cities) == 0 {
+		return earth.CitiesPlacement{}, fmt.Errorf("%w: there must be at least one alien", ErrInvalidPlacement)
+	}
+
+	return placement, nil
+}
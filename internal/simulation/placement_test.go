@@ -0,0 +1,48 @@
+package simulation
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/jattento/alien-invasion-simulator/internal/earth"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadPlacement(t *testing.T) {
+	placement, err := ReadPlacement(strings.NewReader(`aliens:
+  - city: New York
+    count: 2
+  - city: Boston
+`))
+	require.NoError(t, err)
+	assert.Equal(t, earth.CitiesPlacement{Cities: []string{"New York", "New York", "Boston"}}, placement)
+
+	placement, err = ReadPlacement(strings.NewReader(`{"aliens": [{"city": "Paris"}]}`))
+	require.NoError(t, err)
+	assert.Equal(t, earth.CitiesPlacement{Cities: []string{"Paris"}}, placement)
+
+	for _, invalid := range []string{
+		"",
+		"aliens:\n  - count: 2\n",
+		"aliens:\n  - city: Paris\n    count: 0\n",
+		"aliens:\n  - city: Paris\n    species: brute\n",
+	} {
+		_, err := ReadPlacement(strings.NewReader(invalid))
+		assert.ErrorIs(t, err, ErrInvalidPlacement, invalid)
+	}
+}
+
+func TestNewInvasionFromLayout_Placement(t *testing.T) {
+	cityLayout := map[string]map[earth.Direction]string{"A": {earth.East: "B"}, "B": {earth.West: "A"}, "C": {}}
+
+	invasion, err := NewInvasionFromLayout(cityLayout, 3, 10, 1, WithPlacement(earth.CitiesPlacement{Cities: []string{"C", "A", "C"}}))
+	require.NoError(t, err)
+
+	spawned := make(map[string]int)
+	for _, city := range invasion.RecordingHeader().Aliens {
+		spawned[city]++
+	}
+
+	assert.Equal(t, map[string]int{"A": 1, "C": 2}, spawned)
+}
@@ -0,0 +1,141 @@
+package simulation
+
+import (
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/jattento/alien-invasion-simulator/internal/earth"
+	"github.com/jattento/alien-invasion-simulator/internal/platform/system"
+)
+
+// Road is a single direction of a city record.
+type Road struct {
+	City      string `json:"city"`
+	Direction string `json:"direction"`
+	To        string `json:"to"`
+}
+
+func (road Road) String() string {
+	return fmt.Sprintf("%s %s=%s", road.City, road.Direction, road.To)
+}
+
+// _directionsOrder is the order in which the roads of a city are written, the free-form ones go after them sorted.
+var _directionsOrder = earth.BuiltinDirectionNames()
+
+// RepairCityLayout returns a copy of the records with the missing reciprocal roads added, and the roads it added.
+// A road is only inferred when exactly one city claims it, every conflict is left as it is
+// to be reported by ValidateCityLayout, as well as the roads in unknown directions. Cities only referenced by others
+// get their own record.
+func RepairCityLayout(fileRecords system.LoadFileRecords, directions *earth.Directions) (system.LoadFileRecords, []Road) {
+	fixed := make(system.LoadFileRecords, len(fileRecords))
+	for city, roads := range fileRecords {
+		fixed[city] = make(map[string]string, len(roads))
+		for direction, adjacentCity := range roads {
+			fixed[city][direction] = adjacentCity
+		}
+	}
+
+	// Every road that should exist but doesn't, grouped by the city and direction it would be added at
+	type slot struct{ city, direction string }
+	claims := make(map[slot][]string)
+
+	for city, roads := range fileRecords {
+		for direction, adjacentCity := range roads {
+			opposite, known := directions.InverseName(direction)
+			if !known || adjacentCity == city {
+				continue
+			}
+
+			if _, taken := fileRecords[adjacentCity][opposite]; taken {
+				continue
+			}
+
+			// A road back through another direction can't be fixed by adding one more
+			if reachesCity(fileRecords[adjacentCity], city) {
+				continue
+			}
+
+			claims[slot{adjacentCity, opposite}] = append(claims[slot{adjacentCity, opposite}], city)
+		}
+	}
+
+	added := make([]Road, 0)
+	for s, claimants := range claims {
+		if len(claimants) != 1 {
+			continue
+		}
+
+		if _, exists := fixed[s.city]; !exists {
+			fixed[s.city] = make(map[string]string)
+		}
+
+		fixed[s.city][s.direction] = claimants[0]
+		added = append(added, Road{City: s.city, Direction: s.direction, To: claimants[0]})
+	}
+
+	sort.Slice(added, func(i, j int) bool { return added[i].String() < added[j].String() })
+
+	return fixed, added
+}
+
+func reachesCity(roads map[string]string, city string) bool {
+	for _, adjacentCity := range roads {
+		if adjacentCity == city {
+			return true
+		}
+	}
+
+	return false
+}
+
+// WriteCityLayout writes the layout in the given format sorted by city, with their roads in the order of the built-in
+// directions and then the free-form ones sorted. The text format writes the city attributes after the roads.
+func WriteCityLayout(output io.Writer, loaded system.LoadedFile, format system.Format) error {
+	builtin := make(map[string]bool, len(_directionsOrder))
+	for _, direction := range _directionsOrder {
+		builtin[direction] = true
+	}
+
+	keyOrder := append([]string{}, _directionsOrder...)
+	for _, direction := range layoutDirections(loaded.Records) {
+		if !builtin[direction] && !isAttribute(direction) {
+			keyOrder = append(keyOrder, direction)
+		}
+	}
+
+	return system.WriteFile(output, loaded, format, append(keyOrder, _attributesOrder...))
+}
+
+type layoutFixer struct {
+	systemManager SystemManager
+	directions    *earth.Directions
+	report        io.Writer
+}
+
+// NewLayoutFixer returns a SystemManager that repairs the layouts loaded by systemManager with the inverses
+// of directions, every road it adds is written to report.
+func NewLayoutFixer(systemManager SystemManager, directions *earth.Directions, report io.Writer) SystemManager {
+	return &layoutFixer{systemManager: systemManager, directions: directions, report: report}
+}
+
+func (fixer *layoutFixer) Load(path string) (system.LoadedFile, error) {
+	loaded, err := fixer.systemManager.Load(path)
+	if err != nil {
+		return system.LoadedFile{}, err
+	}
+
+	// The attributes aren't roads to repair
+	loaded = SplitCityAttributes(loaded)
+
+	var added []Road
+	loaded.Records, added = RepairCityLayout(loaded.Records, fixer.directions)
+
+	for _, road := range added {
+		if _, err := fmt.Fprintf(fixer.report, "%s: added road %s\n", path, road); err != nil {
+			return system.LoadedFile{}, err
+		}
+	}
+
+	return loaded, nil
+}
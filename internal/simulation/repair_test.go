@@ -0,0 +1,127 @@
+package simulation
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/jattento/alien-invasion-simulator/internal/earth"
+	"github.com/jattento/alien-invasion-simulator/internal/platform/system"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRepairCityLayout(t *testing.T) {
+	t.Run("missing roads are inferred", func(t *testing.T) {
+		records := system.LoadFileRecords{
+			"Foo": {"north": "Bar", "west": "Baz"},
+			"Bar": {"west": "Bee"},
+			"Bee": {"east": "Bar"},
+		}
+
+		fixed, added := RepairCityLayout(records, earth.NewDirections())
+
+		assert.Equal(t, system.LoadFileRecords{
+			"Foo": {"north": "Bar", "west": "Baz"},
+			"Bar": {"west": "Bee", "south": "Foo"},
+			"Baz": {"east": "Foo"},
+			"Bee": {"east": "Bar"},
+		}, fixed)
+		assert.Equal(t, []Road{{City: "Bar", Direction: "south", To: "Foo"}, {City: "Baz", Direction: "east", To: "Foo"}}, added)
+		assert.NoError(t, ValidateCityLayout(fixed, nil, earth.NewDirections()))
+
+		// The original records must not be modified
+		assert.NotContains(t, records, "Baz")
+	})
+
+	t.Run("directions beyond the compass are inferred", func(t *testing.T) {
+		fixed, added := RepairCityLayout(system.LoadFileRecords{
+			"Foo":  {"northwest": "Bar", "label:ferry": "Isle"},
+			"Bar":  {},
+			"Isle": {},
+		}, earth.NewDirections())
+
+		assert.Equal(t, []Road{{City: "Bar", Direction: "southeast", To: "Foo"}, {City: "Isle", Direction: "label:ferry", To: "Foo"}}, added)
+		assert.NoError(t, ValidateCityLayout(fixed, nil, earth.NewDirections()))
+	})
+
+	t.Run("unknown directions are left to be reported", func(t *testing.T) {
+		records := system.LoadFileRecords{
+			"Foo": {"nrth": "Bar"},
+			"Bar": {},
+		}
+
+		fixed, added := RepairCityLayout(records, earth.NewDirections())
+
+		assert.Empty(t, added)
+		assert.Equal(t, records, fixed)
+		assert.Error(t, ValidateCityLayout(fixed, nil, earth.NewDirections()))
+	})
+
+	t.Run("conflicts are left to be reported", func(t *testing.T) {
+		records := system.LoadFileRecords{
+			// Both claim the east of Bar
+			"Foo": {"west": "Bar"},
+			"Baz": {"west": "Bar"},
+			"Bar": {},
+			// Qux already has a different city at its south
+			"Quux":  {"north": "Qux"},
+			"Qux":   {"south": "Corge"},
+			"Corge": {"north": "Qux"},
+		}
+
+		fixed, added := RepairCityLayout(records, earth.NewDirections())
+
+		assert.Empty(t, added)
+		assert.Equal(t, records, fixed)
+
+		var layoutError *LayoutError
+		require.ErrorAs(t, ValidateCityLayout(fixed, nil, earth.NewDirections()), &layoutError)
+		assert.Len(t, layoutError.Problems, 3)
+	})
+}
+
+func TestNewLayoutFixer(t *testing.T) {
+	var report bytes.Buffer
+	fixer := NewLayoutFixer(&MockSystemManager{}, earth.NewDirections(), &report)
+
+	loaded, err := fixer.Load("some_file")
+	require.NoError(t, err)
+
+	assert.NoError(t, ValidateCityLayout(loaded.Records, nil, earth.NewDirections()))
+	assert.Empty(t, report.String())
+
+	_, err = fixer.Load("invalid_file")
+	assert.Error(t, err)
+}
+
+func TestWriteCityLayout(t *testing.T) {
+	var output bytes.Buffer
+
+	err := WriteCityLayout(&output, system.LoadedFile{Records: system.LoadFileRecords{
+		"Foo": {"west": "Baz", "south": "Qu-ux", "north": "Bar"},
+		"Bar": {"south": "Foo"},
+	}}, system.FormatText)
+	require.NoError(t, err)
+
+	assert.Equal(t, "Bar south=Foo\nFoo north=Bar south=Qu-ux west=Baz\n", output.String())
+
+	// The free-form directions go after the built-in ones
+	output.Reset()
+
+	err = WriteCityLayout(&output, system.LoadedFile{Records: system.LoadFileRecords{
+		"Foo": {"ferry": "Isle", "down": "Cellar", "northeast": "Bar", "north": "Baz", "boat": "Dock"},
+	}, Attributes: system.LoadFileAttributes{"Foo": {"pop": "10"}}}, system.FormatText)
+	require.NoError(t, err)
+
+	assert.Equal(t, "Foo north=Baz northeast=Bar down=Cellar boat=Dock ferry=Isle pop=10\n", output.String())
+
+	output.Reset()
+
+	err = WriteCityLayout(&output, system.LoadedFile{
+		Records:    system.LoadFileRecords{"Foo": {"west": "Baz", "north": "Bar"}},
+		Attributes: system.LoadFileAttributes{"Foo": {"pop": "120000"}},
+	}, system.FormatYAML)
+	require.NoError(t, err)
+
+	assert.Equal(t, "cities:\n  - name: Foo\n    roads: {north: Bar, west: Baz}\n    attributes: {pop: 120000}\n", output.String())
+}
@@ -0,0 +1,44 @@
+package simulation
+
+import (
+	"fmt"
+
+	"github.com/jattento/alien-invasion-simulator/internal/platform/datastructure"
+)
+
+// Route returns the roads of the shortest way from one city to another through the cities still standing,
+// empty if both are the same. It returns datastructure.ErrPathNotFound if no road leads there.
+func (invasion Invasion) Route(from, to string) ([]Road, error) {
+	roads, err := invasion.planet.Route(from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	route := make([]Road, 0, len(roads))
+	for _, road := range roads {
+		route = append(route, Road{City: road.From, Direction: invasion.planet.Directions().Name(road.Direction), To: road.To})
+	}
+
+	return route, nil
+}
+
+// AlienDistance returns the roads between city and the nearest alien that can reach it, and whether any can.
+func (invasion Invasion) AlienDistance(city string) (int, bool, error) {
+	if _, exists := invasion.CityLayout[city]; !exists {
+		return 0, false, fmt.Errorf("%w: %q", datastructure.ErrVertexNotFound, city)
+	}
+
+	cities := make([]string, 0, len(invasion.planet.Aliens))
+	for _, alien := range invasion.planet.Aliens {
+		cities = append(cities, alien.City.Id)
+	}
+
+	distances, err := invasion.planet.Distances(cities)
+	if err != nil {
+		return 0, false, err
+	}
+
+	roads, reachable := distances[city]
+
+	return roads, reachable, nil
+}
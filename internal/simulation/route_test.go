@@ -0,0 +1,49 @@
+package simulation
+
+import (
+	"testing"
+
+	"github.com/jattento/alien-invasion-simulator/internal/earth"
+	"github.com/jattento/alien-invasion-simulator/internal/platform/datastructure"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInvasion_Route(t *testing.T) {
+	invasion, err := NewInvasionFromLayout(map[string]map[earth.Direction]string{
+		"A": {earth.East: "B"},
+		"B": {earth.West: "A", earth.South: "C"},
+		"C": {earth.North: "B"},
+	}, 1, 10, 1, WithPlacement(earth.CitiesPlacement{Cities: []string{"A"}}))
+	require.NoError(t, err)
+
+	route, err := invasion.Route("A", "C")
+	require.NoError(t, err)
+	assert.Equal(t, []Road{{City: "A", Direction: "east", To: "B"}, {City: "B", Direction: "south", To: "C"}}, route)
+
+	_, err = invasion.Route("C", "D")
+	assert.ErrorIs(t, err, datastructure.ErrVertexNotFound)
+
+	roads, reachable, err := invasion.AlienDistance("C")
+	require.NoError(t, err)
+	assert.True(t, reachable)
+	assert.Equal(t, 2, roads)
+
+	_, _, err = invasion.AlienDistance("D")
+	assert.ErrorIs(t, err, datastructure.ErrVertexNotFound)
+}
+
+func TestInvasion_AlienDistance_Unreachable(t *testing.T) {
+	invasion, err := NewInvasionFromLayout(map[string]map[earth.Direction]string{
+		"A": {},
+		"B": {},
+	}, 1, 10, 1, WithPlacement(earth.CitiesPlacement{Cities: []string{"A"}}))
+	require.NoError(t, err)
+
+	_, reachable, err := invasion.AlienDistance("B")
+	require.NoError(t, err)
+	assert.False(t, reachable)
+
+	_, err = invasion.Route("A", "B")
+	assert.ErrorIs(t, err, datastructure.ErrPathNotFound)
+}
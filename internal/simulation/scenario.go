@@ -0,0 +1,167 @@
+package simulation
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/jattento/alien-invasion-simulator/internal/earth"
+	"github.com/jattento/alien-invasion-simulator/internal/platform/system"
+	"gopkg.in/yaml.v3"
+)
+
+var ErrInvalidScenario = errors.New("invalid scenario")
+
+// Scenario describes a whole invasion: the map, the aliens, the rules and when it ends. Every field is optional,
+// the ones not set keep their default value. Paths are relative to the scenario file.
+//
+//	Example:
+//	seed: 42
+//	map:
+//	  file: world.txt
+//	aliens:
+//	  count: 20
+//	  placement: spread
+//	  species_mix: scout=50,brute=30,hive=20
+//	waves:
+//	  - 20@50
+//	rules:
+//	  movement: momentum:0.8
+//	  battle: strength
+//	defenders:
+//	  garrisons: 5
+//	days: 500
+//	stop:
+//	  - no-battles
+type Scenario struct {
+	Seed   *int64         `yaml:"seed"`
+	Map    ScenarioMap    `yaml:"map"`
+	Aliens ScenarioAliens `yaml:"aliens"`
+
+	// Waves are specs of earth.ParseWave
+	Waves []string `yaml:"waves"`
+
+	Rules     ScenarioRules     `yaml:"rules"`
+	Defenders ScenarioDefenders `yaml:"defenders"`
+	Days      *int              `yaml:"days"`
+
+	// Stop are specs of ParseStopCondition
+	Stop []string `yaml:"stop"`
+}
+
+// ScenarioMap is the city layout of a scenario, the map is generated with Matrix and Cities if File is not set.
+type ScenarioMap struct {
+	File   string `yaml:"file"`
+	Format string `yaml:"format"`
+	Fix    bool   `yaml:"fix"`
+	Matrix *int   `yaml:"matrix"`
+	Cities *int   `yaml:"cities"`
+}
+
+// ScenarioAliens are the aliens spawned when a scenario starts, Placement is a spec of earth.ParsePlacement
+// and PlacementFile a file of ReadPlacement, only one of them can be set.
+type ScenarioAliens struct {
+	Count         *int   `yaml:"count"`
+	Placement     string `yaml:"placement"`
+	PlacementFile string `yaml:"placement_file"`
+	SpeciesMix    string `yaml:"species_mix"`
+	SpeciesFile   string `yaml:"species_file"`
+}
+
+// ScenarioRules are how the aliens of a scenario move and fight.
+type ScenarioRules struct {
+	Movement   string `yaml:"movement"`
+	Battle     string `yaml:"battle"`
+	Collisions bool   `yaml:"collisions"`
+}
+
+// ScenarioDefenders are the garrisons of a scenario, the ones of File plus Garrisons random ones.
+type ScenarioDefenders struct {
+	File      string `yaml:"file"`
+	Garrisons *int   `yaml:"garrisons"`
+	Strength  *int   `yaml:"strength"`
+	Movement  string `yaml:"movement"`
+}
+
+// ReadScenario returns the scenario of a YAML or JSON file, checking every value it can without reading
+// the files it points to.
+func ReadScenario(input io.Reader) (Scenario, error) {
+	var scenario Scenario
+
+	decoder := yaml.NewDecoder(input)
+	decoder.KnownFields(true)
+
+	if err := decoder.Decode(&scenario); err != nil && !errors.Is(err, io.EOF) {
+		return Scenario{}, fmt.Errorf("%w: %s", ErrInvalidScenario, err.Error())
+	}
+
+	if err := scenario.check(); err != nil {
+		return Scenario{}, fmt.Errorf("%w: %s", ErrInvalidScenario, err.Error())
+	}
+
+	return scenario, nil
+}
+
+// check returns the first value of the scenario that isn't valid.
+func (scenario Scenario) check() error {
+	if scenario.Map.File != "" && (scenario.Map.Matrix != nil || scenario.Map.Cities != nil) {
+		return errors.New("map file can't be set with matrix or cities, those are used to generate the map")
+	}
+
+	if scenario.Aliens.Placement != "" && scenario.Aliens.PlacementFile != "" {
+		return errors.New("aliens placement can't be set with placement_file")
+	}
+
+	for _, amount := range []struct {
+		name  string
+		value *int
+	}{
+		{"map matrix", scenario.Map.Matrix},
+		{"map cities", scenario.Map.Cities},
+		{"aliens count", scenario.Aliens.Count},
+		{"defenders garrisons", scenario.Defenders.Garrisons},
+		{"days", scenario.Days},
+	} {
+		if amount.value != nil && *amount.value < 0 {
+			return fmt.Errorf("%s can't be negative", amount.name)
+		}
+	}
+
+	if scenario.Defenders.Strength != nil && *scenario.Defenders.Strength < 1 {
+		return errors.New("defenders strength must be at least 1")
+	}
+
+	if _, err := system.ParseFormat(scenario.Map.Format); err != nil {
+		return fmt.Errorf("map format: %w", err)
+	}
+
+	if _, err := earth.ParsePlacement(scenario.Aliens.Placement); err != nil {
+		return fmt.Errorf("aliens placement: %w", err)
+	}
+
+	for _, spec := range scenario.Waves {
+		if _, err := earth.ParseWave(spec); err != nil {
+			return err
+		}
+	}
+
+	if _, err := earth.ParseMovement(scenario.Rules.Movement); err != nil {
+		return fmt.Errorf("rules movement: %w", err)
+	}
+
+	if _, err := earth.ParseBattle(scenario.Rules.Battle); err != nil {
+		return fmt.Errorf("rules battle: %w", err)
+	}
+
+	if _, err := earth.ParseMovement(scenario.Defenders.Movement); err != nil {
+		return fmt.Errorf("defenders movement: %w", err)
+	}
+
+	for _, spec := range scenario.Stop {
+		if _, err := ParseStopCondition(spec); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,71 @@
+package simulation
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadScenario(t *testing.T) {
+	scenario, err := ReadScenario(strings.NewReader(`seed: 42
+map:
+  file: world.txt
+aliens:
+  count: 20
+  placement: cities:Foo,Bar
+waves:
+  - 20@50:spread
+rules:
+  movement: momentum:0.8
+  battle: strength
+  collisions: true
+defenders:
+  garrisons: 5
+days: 500
+stop:
+  - no-battles
+  - city:Foo
+`))
+	require.NoError(t, err)
+
+	require.NotNil(t, scenario.Seed)
+	assert.Equal(t, int64(42), *scenario.Seed)
+	assert.Equal(t, "world.txt", scenario.Map.File)
+	assert.Nil(t, scenario.Map.Matrix)
+	assert.Equal(t, 20, *scenario.Aliens.Count)
+	assert.Equal(t, "cities:Foo,Bar", scenario.Aliens.Placement)
+	assert.Equal(t, []string{"20@50:spread"}, scenario.Waves)
+	assert.Equal(t, ScenarioRules{Movement: "momentum:0.8", Battle: "strength", Collisions: true}, scenario.Rules)
+	assert.Equal(t, 5, *scenario.Defenders.Garrisons)
+	assert.Nil(t, scenario.Defenders.Strength)
+	assert.Equal(t, 500, *scenario.Days)
+	assert.Equal(t, []string{"no-battles", "city:Foo"}, scenario.Stop)
+
+	scenario, err = ReadScenario(strings.NewReader(`{"map": {"matrix": 3, "cities": 5}, "aliens": {"count": 0}}`))
+	require.NoError(t, err)
+	assert.Equal(t, 3, *scenario.Map.Matrix)
+	assert.Equal(t, 0, *scenario.Aliens.Count)
+
+	_, err = ReadScenario(strings.NewReader(""))
+	assert.NoError(t, err, "every field of a scenario is optional")
+
+	for _, invalid := range []string{
+		"map:\n  file: world.txt\n  matrix: 3\n",
+		"map:\n  format: xml\n",
+		"aliens:\n  count: -1\n",
+		"aliens:\n  placement: nearest\n",
+		"waves:\n  - 20\n",
+		"rules:\n  movement: teleport\n",
+		"rules:\n  battle: duel\n",
+		"rules:\n  speed: 2\n",
+		"defenders:\n  strength: 0\n",
+		"defenders:\n  movement: teleport\n",
+		"days: -5\n",
+		"stop:\n  - forever\n",
+	} {
+		_, err := ReadScenario(strings.NewReader(invalid))
+		assert.ErrorIs(t, err, ErrInvalidScenario, invalid)
+	}
+}
@@ -1,10 +1,13 @@
 package simulation
 
 import (
-	"errors"
+	"io"
+	"math/rand"
 	"os"
+	"sort"
 
 	"github.com/jattento/alien-invasion-simulator/internal/earth"
+	"github.com/jattento/alien-invasion-simulator/internal/platform/random"
 	"github.com/jattento/alien-invasion-simulator/internal/platform/system"
 )
 
@@ -12,145 +15,298 @@ type Invasion struct {
 	planet    *earth.Planet
 	tickCount int
 	tickLimit int
+	seed      int64
+
+	// The source behind every random decision, kept to be able to snapshot its state
+	source *random.Source
+
+	// City:Amount of battles fought at it
+	battles map[string]int
+
+	options options
 
 	CityLayout map[string]map[earth.Direction]string
 }
 
 type TickReport struct {
+	Movements      []earth.Movement
 	Battles        []earth.BattleReport
+	RoadBattles    []earth.RoadBattleReport
+	Defenses       []earth.DefenseReport
+	Waves          []earth.WaveReport
 	AlienPositions map[string][]string
-	Tick           int
+
+	// City:Strength of the garrisons at it
+	Garrisons map[string]int
+
+	// AliensIncoming is the amount of aliens of the waves that didn't land yet
+	AliensIncoming int
+
+	// StopReason is why a stop condition ended the invasion at this tick, empty if none did
+	StopReason string
+
+	// Fragmentation is how the destroyed cities split the world after the tick
+	Fragmentation earth.Fragmentation
+
+	Tick int
 }
 
 type SystemManager interface {
-	LoadFile(path string) (system.LoadFileRecords, error)
+	Load(path string) (system.LoadedFile, error)
 }
 
 const _defaultName = "world_specs.txt"
 
-var _directionToEnum = map[string]earth.Direction{
-	"north": earth.North,
-	"south": earth.South,
-	"east":  earth.East,
-	"west":  earth.West,
-}
-
-// Cities returns a copy of the map layout
+// Cities returns a copy of the map layout without the cities that are already destroyed
 func (invasion Invasion) Cities() map[string]map[earth.Direction]string {
 	mapCopy := make(map[string]map[earth.Direction]string)
 	for k, m := range invasion.CityLayout {
+		if invasion.planet.CityDestroyed(k) {
+			continue
+		}
+
 		mapCopy[k] = make(map[earth.Direction]string)
 		for direction, adjacent := range m {
-			mapCopy[k][direction] = adjacent
+			if !invasion.planet.CityDestroyed(adjacent) {
+				mapCopy[k][direction] = adjacent
+			}
 		}
 	}
 
 	return mapCopy
 }
 
-func NewInvasion(planetSpecsFile string, aliensAmount int, systemManager SystemManager, tickLimit, cities, matrixN int) (*Invasion, error) {
+// Seed returns the seed used to feed every random decision of the invasion,
+// running a new invasion with it and the same layout reproduces this one exactly.
+func (invasion Invasion) Seed() int64 {
+	return invasion.seed
+}
+
+// NewInvasion creates an invasion whose map generation, alien names, spawn positions and movements
+// are all derived from a single randomizer built from seed.
+func NewInvasion(planetSpecsFile string, aliensAmount int, systemManager SystemManager, tickLimit, cities, matrixN int, seed int64,
+	opts ...Option) (*Invasion, error) {
+	source := random.NewSource(seed)
+
+	// The directions of the file are registered in the ones of the invasion
+	directions := newOptions(opts).directions
+
+	cityLayout, cityAttributes, err := LoadCityLayoutWithAttributes(planetSpecsFile, systemManager, cities, matrixN, rand.New(source),
+		directions)
+	if err != nil {
+		return nil, err
+	}
+
+	// The attributes of the file go first, so they can be overridden
+	opts = append(append([]Option{WithCityAttributes(cityAttributes)}, opts...), WithDirections(directions))
+
+	return newInvasion(cityLayout, aliensAmount, tickLimit, source, newOptions(opts))
+}
+
+// NewInvasionFromLayout creates an invasion over an already loaded city layout,
+// useful to run many invasions over the same map without reading it again.
+func NewInvasionFromLayout(cityLayout map[string]map[earth.Direction]string, aliensAmount, tickLimit int, seed int64,
+	opts ...Option) (*Invasion, error) {
+	return newInvasion(cityLayout, aliensAmount, tickLimit, random.NewSource(seed), newOptions(opts))
+}
+
+func newInvasion(cityLayout map[string]map[earth.Direction]string, aliensAmount, tickLimit int, source *random.Source,
+	opts options) (*Invasion, error) {
+	planet, err := earth.New(cityLayout, aliensAmount, rand.New(source), opts.planetOptions()...)
+	if err != nil {
+		return nil, err
+	}
+
+	invasion := &Invasion{
+		planet:     planet,
+		tickLimit:  tickLimit,
+		seed:       source.State().Seed,
+		source:     source,
+		battles:    make(map[string]int),
+		options:    opts,
+		CityLayout: cityLayout,
+	}
+
+	if err := invasion.checkStopConditions(); err != nil {
+		return nil, err
+	}
+
+	return invasion, nil
+}
+
+// LoadCityLayout reads and validates the city layout file, registering its free-form directions in directions.
+// If planetSpecsFile is empty a random layout is generated with the given randomizer.
+func LoadCityLayout(planetSpecsFile string, systemManager SystemManager, cities, matrixN int, randomizer *rand.Rand,
+	directions *earth.Directions) (map[string]map[earth.Direction]string, error) {
+	cityLayout, _, err := LoadCityLayoutWithAttributes(planetSpecsFile, systemManager, cities, matrixN, randomizer, directions)
+
+	return cityLayout, err
+}
+
+// LoadCityLayoutWithAttributes works as LoadCityLayout but also returns the attributes of the cities that have any.
+func LoadCityLayoutWithAttributes(planetSpecsFile string, systemManager SystemManager, cities, matrixN int,
+	randomizer *rand.Rand, directions *earth.Directions) (map[string]map[earth.Direction]string, map[string]earth.CityAttributes, error) {
 	if planetSpecsFile == "" {
 		planetSpecsFile = _defaultName
 
-		if err := generateFile(planetSpecsFile, matrixN, cities); err != nil {
-			return nil, err
+		if err := generateFile(planetSpecsFile, matrixN, cities, randomizer); err != nil {
+			return nil, nil, err
 		}
 
 		defer func() { _ = os.Remove(planetSpecsFile) }()
 	}
 
-	recs, err := systemManager.LoadFile(planetSpecsFile)
+	loaded, err := systemManager.Load(planetSpecsFile)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
-	if valid := validateFileRecords(recs); !valid {
-		return nil, errors.New("invalid city layout")
+	loaded = SplitCityAttributes(loaded)
+
+	if err := ValidateCityFile(loaded, directions); err != nil {
+		return nil, nil, err
+	}
+
+	cityAttributes, err := ParseCityAttributes(loaded.Attributes, loaded.Positions)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// The labels are registered in order, so the same file always gets the same values
+	for _, direction := range layoutDirections(loaded.Records) {
+		if earth.IsLabel(direction) {
+			if _, err := directions.Label(direction); err != nil {
+				return nil, nil, err
+			}
+		}
 	}
 
 	earthCityLayout := make(map[string]map[earth.Direction]string)
-	for city, directions := range recs {
+	for city, roads := range loaded.Records {
 		earthCityLayout[city] = make(map[earth.Direction]string)
-		for direction, adjacentCity := range directions {
-			earthCityLayout[city][_directionToEnum[direction]] = adjacentCity
+		for direction, adjacentCity := range roads {
+			value, _ := directions.Lookup(direction)
+			earthCityLayout[city][value] = adjacentCity
 		}
 	}
 
-	planet, err := earth.New(earthCityLayout, aliensAmount)
-	if err != nil {
-		return nil, err
+	return earthCityLayout, cityAttributes, nil
+}
+
+// layoutDirections returns the name of every direction of the records, sorted.
+func layoutDirections(records system.LoadFileRecords) []string {
+	known := make(map[string]bool)
+	directions := make([]string, 0)
+	for _, roads := range records {
+		for direction := range roads {
+			if !known[direction] {
+				known[direction] = true
+				directions = append(directions, direction)
+			}
+		}
+	}
+	sort.Strings(directions)
+
+	return directions
+}
+
+// WriteDOT writes the current state of the world as a Graphviz graph, with the battles fought at each city.
+func (invasion Invasion) WriteDOT(output io.Writer) error {
+	return invasion.planet.WriteDOT(output, invasion.battles)
+}
+
+// AliensAlive returns the amount of aliens that are still alive.
+func (invasion Invasion) AliensAlive() int {
+	return len(invasion.planet.Aliens)
+}
+
+// Directions returns the directions of the roads of the city layout.
+func (invasion Invasion) Directions() *earth.Directions {
+	return invasion.planet.Directions()
+}
+
+// AliensIncoming returns the amount of aliens of the waves that didn't land yet.
+func (invasion Invasion) AliensIncoming() int {
+	return invasion.planet.AliensIncoming()
+}
+
+// RecordingHeader returns the layout and the alien spawn positions, it must be called before the first tick.
+func (invasion Invasion) RecordingHeader() RecordingHeader {
+	header := RecordingHeader{
+		Seed:   invasion.seed,
+		Layout: make(map[string]map[string]string),
+		Aliens: make(map[string]string),
 	}
 
-	return &Invasion{planet: planet, tickLimit: tickLimit, CityLayout: earthCityLayout}, nil
+	for city, adjacentCities := range invasion.CityLayout {
+		header.Layout[city] = make(map[string]string)
+		for direction, adjacentCity := range adjacentCities {
+			header.Layout[city][invasion.planet.Directions().Name(direction)] = adjacentCity
+		}
+	}
+
+	for name, alien := range invasion.planet.Aliens {
+		header.Aliens[name] = alien.City.Id
+
+		if alien.Species.Name != earth.DefaultSpecies.Name {
+			if header.Species == nil {
+				header.Species = make(map[string]string)
+			}
+
+			header.Species[name] = alien.Species.Name
+		}
+	}
+
+	return header
 }
 
 func (invasion Invasion) alienPositions() map[string][]string {
 	positions := make(map[string][]string)
 
-	for alien, vertex := range invasion.planet.Aliens {
-		positions[vertex.Id] = append(positions[vertex.Id], alien)
+	for name, alien := range invasion.planet.Aliens {
+		positions[alien.City.Id] = append(positions[alien.City.Id], name)
 	}
 
 	return positions
 }
 
-// Tick first return value indicates if the function should continue to be called
-func (invasion *Invasion) Tick() (bool, TickReport) {
-	invasion.tickCount++
+// garrisons returns City:Strength of the garrisons at it, nil if there are no garrisons.
+func (invasion Invasion) garrisons() map[string]int {
+	if len(invasion.planet.Garrisons) == 0 {
+		return nil
+	}
 
-	return invasion.tickCount < invasion.tickLimit, TickReport{
-		Battles:        invasion.planet.NextDay(),
-		Tick:           invasion.tickCount - 1,
-		AlienPositions: invasion.alienPositions(),
+	garrisons := make(map[string]int)
+	for _, garrison := range invasion.planet.Garrisons {
+		garrisons[garrison.City.Id] += garrison.Strength
 	}
+
+	return garrisons
 }
 
-func validateFileRecords(fileRecords system.LoadFileRecords) bool {
-	for _, cityRoads := range fileRecords {
-		for key := range cityRoads {
-			if key != "north" && key != "south" && key != "west" && key != "east" {
-				return false
-			}
-		}
+// Tick first return value indicates if the function should continue to be called
+func (invasion *Invasion) Tick() (bool, TickReport) {
+	invasion.tickCount++
 
-		_, hasNorth := cityRoads["north"]
-		_, hasEast := cityRoads["east"]
-		_, hasSouth := cityRoads["south"]
-		_, hasWest := cityRoads["west"]
+	dayReport := invasion.planet.NextDay()
 
-		if hasSouth {
-			if _, exists := fileRecords[cityRoads["south"]]; !exists {
-				return false
-			}
-			if _, hasSubNorth := fileRecords[cityRoads["south"]]["north"]; !hasSubNorth {
-				return false
-			}
-		}
-		if hasWest {
-			if _, exists := fileRecords[cityRoads["west"]]; !exists {
-				return false
-			}
-			if _, hasSubEast := fileRecords[cityRoads["west"]]["east"]; !hasSubEast {
-				return false
-			}
-		}
-		if hasEast {
-			if _, exists := fileRecords[cityRoads["east"]]; !exists {
-				return false
-			}
-			if _, hasSubWest := fileRecords[cityRoads["east"]]["west"]; !hasSubWest {
-				return false
-			}
-		}
-		if hasNorth {
-			if _, exists := fileRecords[cityRoads["north"]]; !exists {
-				return false
-			}
-			if _, hasSubSouth := fileRecords[cityRoads["north"]]["south"]; !hasSubSouth {
-				return false
-			}
-		}
+	for _, battle := range dayReport.Battles {
+		invasion.battles[battle.City]++
 	}
 
-	return true
+	stopReason := invasion.stopReason()
+
+	return invasion.tickCount < invasion.tickLimit && stopReason == "", TickReport{
+		Movements:      dayReport.Movements,
+		Battles:        dayReport.Battles,
+		RoadBattles:    dayReport.RoadBattles,
+		Defenses:       dayReport.Defenses,
+		Waves:          dayReport.Waves,
+		Garrisons:      invasion.garrisons(),
+		AliensIncoming: invasion.AliensIncoming(),
+		Tick:           invasion.tickCount - 1,
+		AlienPositions: invasion.alienPositions(),
+		StopReason:     stopReason,
+		Fragmentation:  invasion.planet.Fragmentation(),
+	}
 }
@@ -1,9 +1,14 @@
 package simulation
 
 import (
+	"bytes"
 	"errors"
+	"fmt"
 	"io/ioutil"
 	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
 	"testing"
 
 	"github.com/jattento/alien-invasion-simulator/internal/earth"
@@ -15,12 +20,12 @@ import (
 
 type MockSystemManager struct{}
 
-func (msm *MockSystemManager) LoadFile(path string) (system.LoadFileRecords, error) {
+func (msm *MockSystemManager) Load(path string) (system.LoadedFile, error) {
 	if path == "invalid_file" {
-		return nil, errors.New("invalid file")
+		return system.LoadedFile{}, errors.New("invalid file")
 	}
 
-	return system.LoadFileRecords{
+	return system.LoadedFile{Records: system.LoadFileRecords{
 		"City1": {
 			"north": "City2",
 			"south": "City3",
@@ -39,37 +44,67 @@ func (msm *MockSystemManager) LoadFile(path string) (system.LoadFileRecords, err
 		"City5": {
 			"east": "City1",
 		},
-	}, nil
+	}}, nil
 }
 
 func TestNewInvasion(t *testing.T) {
 	// Test loading file error
 	msm := &MockSystemManager{}
-	_, err := NewInvasion("invalid_file", 10, msm, 100, 5, 5)
+	_, err := NewInvasion("invalid_file", 10, msm, 100, 5, 5, 0)
 	assert.Error(t, err)
 
-	inv, err := NewInvasion("", 10, msm, 100, 5, 5)
+	inv, err := NewInvasion("", 10, msm, 100, 5, 5, 0)
 	assert.NoError(t, err)
 
 	assert.Equal(t, 5, len(inv.Cities()))
 
 	// Test valid city layout
-	invasion, err := NewInvasion("some_file", 10, msm, 100, 5, 5)
+	invasion, err := NewInvasion("some_file", 10, msm, 100, 5, 5, 42)
 	assert.NoError(t, err)
 	assert.NotNil(t, invasion.planet)
 	assert.Equal(t, 100, invasion.tickLimit)
+	assert.Equal(t, int64(42), invasion.Seed())
 	assert.NotEmpty(t, invasion.CityLayout)
 }
 
+// layoutManager loads the same records for every path.
+type layoutManager system.LoadFileRecords
+
+func (manager layoutManager) Load(string) (system.LoadedFile, error) {
+	return system.LoadedFile{Records: system.LoadFileRecords(manager)}, nil
+}
+
+func TestNewInvasion_Directions(t *testing.T) {
+	invasion, err := NewInvasion("some_file", 1, layoutManager{
+		"Hall":   {"up": "Attic", "label:ferry": "Isle", "southeast": "Garden"},
+		"Attic":  {"down": "Hall"},
+		"Isle":   {"label:ferry": "Hall"},
+		"Garden": {"northwest": "Hall"},
+	}, 10, 5, 5, 1, WithPlacement(earth.CitiesPlacement{Cities: []string{"Isle"}}))
+	require.NoError(t, err)
+
+	route, err := invasion.Route("Attic", "Isle")
+	require.NoError(t, err)
+	assert.Equal(t, []Road{{City: "Attic", Direction: "down", To: "Hall"}, {City: "Hall", Direction: "label:ferry", To: "Isle"}}, route)
+
+	assert.Equal(t, map[string]string{"up": "Attic", "label:ferry": "Isle", "southeast": "Garden"},
+		invasion.RecordingHeader().Layout["Hall"])
+
+	_, err = NewInvasion("some_file", 1, layoutManager{"Foo": {"nrth": "Bar"}, "Bar": {"nrth": "Foo"}}, 10, 5, 5, 1)
+	var layoutErr *LayoutError
+	require.ErrorAs(t, err, &layoutErr)
+	assert.Len(t, layoutErr.Problems, 2)
+}
+
 func TestInvasion_alienPositions(t *testing.T) {
 	invasion := &Invasion{
 		planet: &earth.Planet{
-			Aliens: map[string]*datastructure.Vertex{
-				"A1": &datastructure.Vertex{Id: "City1"},
-				"A2": &datastructure.Vertex{Id: "City2"},
-				"A3": &datastructure.Vertex{Id: "City3"},
-				"A4": &datastructure.Vertex{Id: "City3"},
-				"A5": &datastructure.Vertex{Id: "City1"},
+			Aliens: map[string]*earth.Alien{
+				"A1": {Name: "A1", City: &datastructure.Vertex{Id: "City1"}},
+				"A2": {Name: "A2", City: &datastructure.Vertex{Id: "City2"}},
+				"A3": {Name: "A3", City: &datastructure.Vertex{Id: "City3"}},
+				"A4": {Name: "A4", City: &datastructure.Vertex{Id: "City3"}},
+				"A5": {Name: "A5", City: &datastructure.Vertex{Id: "City1"}},
 			},
 		},
 	}
@@ -92,7 +127,7 @@ func TestInvasion_Tick(t *testing.T) {
 	_, err = file.WriteString("north=south_city\neast=east_city\nwest=west_city\n")
 	require.NoError(t, err)
 
-	invasion, err := NewInvasion(file.Name(), 2, &MockSystemManager{}, 2, 5, 5)
+	invasion, err := NewInvasion(file.Name(), 2, &MockSystemManager{}, 2, 5, 5, 0)
 	require.NoError(t, err)
 
 	// Call the Tick() method and check the return values
@@ -104,3 +139,104 @@ func TestInvasion_Tick(t *testing.T) {
 	assert.False(t, ok)
 	assert.Equal(t, 1, report.Tick)
 }
+
+func TestInvasion_Tick_Fragmentation(t *testing.T) {
+	// The aliens landing at B destroy it on day zero, splitting A from C - D
+	invasion, err := NewInvasionFromLayout(map[string]map[earth.Direction]string{
+		"A": {earth.East: "B"},
+		"B": {earth.West: "A", earth.East: "C"},
+		"C": {earth.West: "B", earth.East: "D"},
+		"D": {earth.West: "C"},
+	}, 3, 10, 1, WithMovement(earth.HoldMovement{}),
+		WithPlacement(earth.CitiesPlacement{Cities: []string{"B", "B", "D"}}))
+	require.NoError(t, err)
+
+	_, report := invasion.Tick()
+	assert.Equal(t, earth.Fragmentation{Components: 2, Largest: 2, Aliens: []earth.ComponentAliens{{Cities: 2, Aliens: 1}}},
+		report.Fragmentation)
+}
+
+func TestInvasion_WriteDOT(t *testing.T) {
+	invasion, err := NewInvasion("some_file", 10, &MockSystemManager{}, 100, 5, 5, 3)
+	require.NoError(t, err)
+
+	battles := 0
+	for keepTicking := true; keepTicking && invasion.AliensAlive() > 0; {
+		var report TickReport
+		keepTicking, report = invasion.Tick()
+		battles += len(report.Battles)
+	}
+
+	require.NotZero(t, battles)
+
+	countedBattles := 0
+	for _, amount := range invasion.battles {
+		countedBattles += amount
+	}
+	assert.Equal(t, battles, countedBattles)
+
+	var output bytes.Buffer
+	require.NoError(t, invasion.WriteDOT(&output))
+
+	for city := range invasion.battles {
+		assert.Contains(t, output.String(), fmt.Sprintf(`label="%s\n1 battle"`, city))
+	}
+
+	// Battles are part of the snapshot, so the picture of a resumed invasion is the same
+	restored, err := RestoreInvasion(invasion.Snapshot())
+	require.NoError(t, err)
+
+	var restoredOutput bytes.Buffer
+	require.NoError(t, restored.WriteDOT(&restoredOutput))
+	assert.Equal(t, output.String(), restoredOutput.String())
+}
+
+// writeGridFile writes a side x side grid of cities with their population to a text file, every road listed twice.
+func writeGridFile(b *testing.B, side int) string {
+	var builder strings.Builder
+
+	name := func(x, y int) string { return fmt.Sprintf("City%d_%d", x, y) }
+	for y := 0; y < side; y++ {
+		for x := 0; x < side; x++ {
+			builder.WriteString(name(x, y))
+
+			if y > 0 {
+				builder.WriteString(" north=" + name(x, y-1))
+			}
+			if x < side-1 {
+				builder.WriteString(" east=" + name(x+1, y))
+			}
+			if y < side-1 {
+				builder.WriteString(" south=" + name(x, y+1))
+			}
+			if x > 0 {
+				builder.WriteString(" west=" + name(x-1, y))
+			}
+
+			builder.WriteString(fmt.Sprintf(" pop=%d\n", x*y))
+		}
+	}
+
+	path := filepath.Join(b.TempDir(), "grid.txt")
+	if err := os.WriteFile(path, []byte(builder.String()), 0o644); err != nil {
+		b.Fatal(err)
+	}
+
+	return path
+}
+
+// BenchmarkLoadCityLayoutWithAttributes measures reading, splitting the attributes and validating a city file.
+func BenchmarkLoadCityLayoutWithAttributes(b *testing.B) {
+	// Up to a million cities
+	for _, side := range []int{32, 316, 1000} {
+		path := writeGridFile(b, side)
+
+		b.Run(strconv.Itoa(side*side), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				if _, _, err := LoadCityLayoutWithAttributes(path, system.NewManager(), 0, 0, nil, earth.NewDirections()); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,205 @@
+package simulation
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+
+	"github.com/jattento/alien-invasion-simulator/internal/earth"
+	"github.com/jattento/alien-invasion-simulator/internal/platform/random"
+)
+
+var ErrInvalidSnapshot = errors.New("invalid snapshot")
+
+// Snapshot is the serializable state of an invasion, restoring it continues the invasion exactly where it was.
+type Snapshot struct {
+	Planet    earth.Snapshot `json:"planet"`
+	Random    random.State   `json:"random"`
+	Seed      int64          `json:"seed"`
+	TickCount int            `json:"tick_count"`
+	TickLimit int            `json:"tick_limit"`
+
+	// City:Amount of battles fought at it
+	Battles map[string]int `json:"battles,omitempty"`
+
+	// Movement is the spec of the aliens movement strategy, see earth.ParseMovement.
+	Movement string `json:"movement,omitempty"`
+
+	// Battle is the spec of the battle rules, see earth.ParseBattle.
+	Battle string `json:"battle,omitempty"`
+
+	// Collisions is set when the aliens crossing the same road in opposite directions fight on it.
+	Collisions bool `json:"collisions,omitempty"`
+
+	// DefenderMovement is the spec of the garrisons movement strategy, see earth.ParseMovement.
+	DefenderMovement string `json:"defender_movement"`
+
+	// Waves are the specs of the waves of aliens, see earth.ParseWave. The aliens of the waves that land
+	// after restoring follow the species mix of the planet, unless the restored invasion sets another one.
+	Waves []string `json:"waves,omitempty"`
+
+	// Stop are the specs of the stop conditions, see ParseStopCondition.
+	Stop []string `json:"stop,omitempty"`
+}
+
+// Snapshot returns the current state of the invasion.
+func (invasion *Invasion) Snapshot() Snapshot {
+	return Snapshot{
+		Planet:     invasion.planet.Snapshot(),
+		Random:     invasion.source.State(),
+		Seed:       invasion.seed,
+		TickCount:  invasion.tickCount,
+		TickLimit:  invasion.tickLimit,
+		Battles:    copyBattles(invasion.battles),
+		Movement:   invasion.options.movement.String(),
+		Battle:     invasion.options.battle.String(),
+		Collisions: invasion.options.collisions,
+
+		DefenderMovement: invasion.options.defenderMovement.String(),
+		Waves:            waveSpecs(invasion.options.waves),
+		Stop:             stopSpecs(invasion.options.stopConditions),
+	}
+}
+
+func stopSpecs(conditions []StopCondition) []string {
+	if len(conditions) == 0 {
+		return nil
+	}
+
+	specs := make([]string, 0, len(conditions))
+	for _, condition := range conditions {
+		specs = append(specs, condition.String())
+	}
+
+	return specs
+}
+
+func waveSpecs(waves []earth.Wave) []string {
+	if len(waves) == 0 {
+		return nil
+	}
+
+	specs := make([]string, 0, len(waves))
+	for _, wave := range waves {
+		specs = append(specs, wave.String())
+	}
+
+	return specs
+}
+
+func copyBattles(battles map[string]int) map[string]int {
+	battlesCopy := make(map[string]int, len(battles))
+	for city, amount := range battles {
+		battlesCopy[city] = amount
+	}
+
+	return battlesCopy
+}
+
+// RestoreInvasion rebuilds the invasion saved in the snapshot, opts replace the rules the snapshot was taken with.
+func RestoreInvasion(snapshot Snapshot, opts ...Option) (*Invasion, error) {
+	movement, err := earth.ParseMovement(snapshot.Movement)
+	if err != nil {
+		return nil, err
+	}
+
+	battle, err := earth.ParseBattle(snapshot.Battle)
+	if err != nil {
+		return nil, err
+	}
+
+	if snapshot.DefenderMovement == "" {
+		return nil, fmt.Errorf("%w: missing defender_movement", ErrInvalidSnapshot)
+	}
+
+	defenderMovement, err := earth.ParseMovement(snapshot.DefenderMovement)
+	if err != nil {
+		return nil, err
+	}
+
+	waves := make([]earth.Wave, 0, len(snapshot.Waves))
+	for _, spec := range snapshot.Waves {
+		wave, err := earth.ParseWave(spec)
+		if err != nil {
+			return nil, err
+		}
+
+		waves = append(waves, wave)
+	}
+
+	stopConditions := make([]StopCondition, 0, len(snapshot.Stop))
+	for _, spec := range snapshot.Stop {
+		condition, err := ParseStopCondition(spec)
+		if err != nil {
+			return nil, err
+		}
+
+		stopConditions = append(stopConditions, condition)
+	}
+
+	restoredOptions := newOptions(append([]Option{
+		WithMovement(movement),
+		WithBattle(battle),
+		WithEnRouteCollisions(snapshot.Collisions),
+		WithDefenderMovement(defenderMovement),
+		WithWaves(waves),
+		WithStopConditions(stopConditions),
+	}, opts...))
+	source := random.Restore(snapshot.Random)
+
+	planet, err := earth.Restore(snapshot.Planet, rand.New(source), restoredOptions.planetOptions()...)
+	if err != nil {
+		return nil, err
+	}
+
+	cityLayout := make(map[string]map[earth.Direction]string, len(snapshot.Planet.Cities))
+	for _, city := range snapshot.Planet.Cities {
+		cityLayout[city.Name] = make(map[earth.Direction]string, len(city.Roads))
+		for direction, adjacentCity := range city.Roads {
+			cityLayout[city.Name][direction] = adjacentCity
+		}
+	}
+
+	invasion := &Invasion{
+		planet:     planet,
+		tickCount:  snapshot.TickCount,
+		tickLimit:  snapshot.TickLimit,
+		seed:       snapshot.Seed,
+		source:     source,
+		battles:    copyBattles(snapshot.Battles),
+		options:    restoredOptions,
+		CityLayout: cityLayout,
+	}
+
+	if err := invasion.checkStopConditions(); err != nil {
+		return nil, err
+	}
+
+	return invasion, nil
+}
+
+// Reseed changes the future of the invasion, useful to branch different scenarios from the same snapshot.
+func (invasion *Invasion) Reseed(seed int64) {
+	invasion.source.Seed(seed)
+	invasion.seed = seed
+}
+
+// SetTickLimit changes the tick in which the invasion ends.
+func (invasion *Invasion) SetTickLimit(tickLimit int) {
+	invasion.tickLimit = tickLimit
+}
+
+func WriteSnapshot(output io.Writer, snapshot Snapshot) error {
+	return json.NewEncoder(output).Encode(snapshot)
+}
+
+func ReadSnapshot(input io.Reader) (Snapshot, error) {
+	var snapshot Snapshot
+	if err := json.NewDecoder(input).Decode(&snapshot); err != nil {
+		return Snapshot{}, err
+	}
+
+	return snapshot, nil
+}
@@ -0,0 +1,229 @@
+package simulation
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/jattento/alien-invasion-simulator/internal/earth"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInvasion_SnapshotRestore(t *testing.T) {
+	cityLayout := map[string]map[earth.Direction]string{
+		"A": {earth.East: "B", earth.South: "C"},
+		"B": {earth.West: "A", earth.South: "D"},
+		"C": {earth.North: "A", earth.East: "D"},
+		"D": {earth.North: "B", earth.West: "C"},
+	}
+
+	invasion, err := NewInvasionFromLayout(cityLayout, 4, 30, 21)
+	require.NoError(t, err)
+
+	for i := 0; i < 3; i++ {
+		invasion.Tick()
+	}
+
+	var file bytes.Buffer
+	require.NoError(t, WriteSnapshot(&file, invasion.Snapshot()))
+
+	snapshot, err := ReadSnapshot(&file)
+	require.NoError(t, err)
+
+	restored, err := RestoreInvasion(snapshot)
+	require.NoError(t, err)
+
+	assert.Equal(t, invasion.Seed(), restored.Seed())
+	assert.Equal(t, invasion.Cities(), restored.Cities())
+
+	for keepTicking := true; keepTicking; {
+		var expected, actual TickReport
+		keepTicking, expected = invasion.Tick()
+		_, actual = restored.Tick()
+
+		require.Equal(t, expected, actual)
+	}
+}
+
+func TestInvasion_SnapshotRestore_Movement(t *testing.T) {
+	cityLayout := map[string]map[earth.Direction]string{
+		"A": {earth.East: "B"},
+		"B": {earth.West: "A", earth.East: "C"},
+		"C": {earth.West: "B", earth.East: "D"},
+		"D": {earth.West: "C"},
+	}
+
+	invasion, err := NewInvasionFromLayout(cityLayout, 1, 30, 4, WithMovement(earth.MomentumMovement{Persistence: 0.9}))
+	require.NoError(t, err)
+
+	for i := 0; i < 3; i++ {
+		invasion.Tick()
+	}
+
+	snapshot := invasion.Snapshot()
+	assert.Equal(t, "momentum:0.9", snapshot.Movement)
+
+	// The last direction of each alien is needed to keep the momentum
+	restored, err := RestoreInvasion(snapshot)
+	require.NoError(t, err)
+
+	for keepTicking := true; keepTicking; {
+		var expected, actual TickReport
+		keepTicking, expected = invasion.Tick()
+		_, actual = restored.Tick()
+
+		require.Equal(t, expected, actual)
+	}
+
+	restored, err = RestoreInvasion(snapshot, WithMovement(earth.RandomWalkMovement{}))
+	require.NoError(t, err)
+	assert.Equal(t, "walk", restored.Snapshot().Movement)
+
+	snapshot.Movement = "teleport"
+	_, err = RestoreInvasion(snapshot)
+	assert.ErrorIs(t, err, earth.ErrUnknownMovement)
+}
+
+func TestInvasion_SnapshotRestore_Directions(t *testing.T) {
+	directions := earth.NewDirections()
+	ferry, err := directions.Label("label:ferry")
+	require.NoError(t, err)
+	cityLayout := map[string]map[earth.Direction]string{
+		"A": {earth.Up: "B", ferry: "C"},
+		"B": {earth.Down: "A"},
+		"C": {ferry: "A", earth.Northeast: "D"},
+		"D": {earth.Southwest: "C"},
+	}
+
+	invasion, err := NewInvasionFromLayout(cityLayout, 1, 30, 8, WithMovement(earth.MomentumMovement{Persistence: 0.9}),
+		WithDirections(directions))
+	require.NoError(t, err)
+
+	invasion.Tick()
+
+	snapshot := invasion.Snapshot()
+	assert.Equal(t, []earth.DirectionSnapshot{{Name: "label:ferry", Inverse: "label:ferry"}}, snapshot.Planet.Directions)
+
+	restored, err := RestoreInvasion(snapshot)
+	require.NoError(t, err)
+	assert.Equal(t, invasion.Cities(), restored.Cities())
+
+	for keepTicking := true; keepTicking; {
+		var expected, actual TickReport
+		keepTicking, expected = invasion.Tick()
+		_, actual = restored.Tick()
+
+		require.Equal(t, expected, actual)
+	}
+}
+
+func TestInvasion_SnapshotRestore_Battle(t *testing.T) {
+	cityLayout := map[string]map[earth.Direction]string{
+		"A": {earth.East: "B"},
+		"B": {earth.West: "A"},
+	}
+
+	invasion, err := NewInvasionFromLayout(cityLayout, 4, 30, 2, WithBattle(earth.ChanceBattle{DestroyProbability: 0.2}))
+	require.NoError(t, err)
+
+	snapshot := invasion.Snapshot()
+	assert.Equal(t, "chance:0.2", snapshot.Battle)
+
+	restored, err := RestoreInvasion(snapshot)
+	require.NoError(t, err)
+
+	for keepTicking := true; keepTicking; {
+		var expected, actual TickReport
+		keepTicking, expected = invasion.Tick()
+		_, actual = restored.Tick()
+
+		require.Equal(t, expected, actual)
+	}
+
+	restored, err = RestoreInvasion(snapshot, WithBattle(earth.StrengthBattle{}), WithEnRouteCollisions(true))
+	require.NoError(t, err)
+	assert.Equal(t, "strength", restored.Snapshot().Battle)
+	assert.True(t, restored.Snapshot().Collisions)
+
+	snapshot.Battle = "duel"
+	_, err = RestoreInvasion(snapshot)
+	assert.ErrorIs(t, err, earth.ErrUnknownBattle)
+}
+
+func TestInvasion_SnapshotRestore_Waves(t *testing.T) {
+	cityLayout := map[string]map[earth.Direction]string{
+		"A": {earth.East: "B"},
+		"B": {earth.West: "A", earth.East: "C"},
+		"C": {earth.West: "B"},
+	}
+
+	species := earth.BuiltinSpecies()
+	invasion, err := NewInvasionFromLayout(cityLayout, 1, 30, 5, WithWaves([]earth.Wave{
+		{Aliens: 2, Tick: 1},
+		{Aliens: 3, Tick: 4, Placement: earth.CitiesPlacement{Cities: []string{"C"}}},
+	}), WithSpeciesMix([]earth.SpeciesShare{{Species: species["scout"], Weight: 1}, {Species: species["brute"], Weight: 2}}))
+	require.NoError(t, err)
+	assert.Equal(t, 5, invasion.AliensIncoming())
+
+	for i := 0; i < 2; i++ {
+		invasion.Tick()
+	}
+
+	snapshot := invasion.Snapshot()
+	assert.Equal(t, []string{"2@1", "3@4:cities:C"}, snapshot.Waves)
+	assert.Equal(t, 3, invasion.AliensIncoming())
+
+	// The names taken by the landed aliens are needed so the next waves don't repeat them,
+	// and the species mix so they get the same species
+	restored, err := RestoreInvasion(snapshot)
+	require.NoError(t, err)
+	assert.Equal(t, 3, restored.AliensIncoming())
+
+	for keepTicking := true; keepTicking; {
+		var expected, actual TickReport
+		keepTicking, expected = invasion.Tick()
+		_, actual = restored.Tick()
+
+		require.Equal(t, expected, actual)
+	}
+
+	snapshot.Waves = []string{"3@x"}
+	_, err = RestoreInvasion(snapshot)
+	assert.ErrorIs(t, err, earth.ErrInvalidWave)
+}
+
+func TestInvasion_Reseed(t *testing.T) {
+	cityLayout := map[string]map[earth.Direction]string{
+		"A": {earth.East: "B"},
+		"B": {earth.West: "A"},
+	}
+
+	invasion, err := NewInvasionFromLayout(cityLayout, 1, 10, 1)
+	require.NoError(t, err)
+
+	invasion.Reseed(2)
+	assert.Equal(t, int64(2), invasion.Seed())
+	assert.Equal(t, int64(2), invasion.Snapshot().Random.Seed)
+	assert.Zero(t, invasion.Snapshot().Random.Draws)
+}
+
+func TestInvasion_CitiesWithoutDestroyed(t *testing.T) {
+	cityLayout := map[string]map[earth.Direction]string{
+		"A": {earth.East: "B"},
+		"B": {earth.West: "A", earth.East: "C"},
+		"C": {earth.West: "B"},
+	}
+
+	invasion, err := NewInvasionFromLayout(cityLayout, 0, 10, 1)
+	require.NoError(t, err)
+
+	snapshot := invasion.Snapshot()
+	for i := range snapshot.Planet.Cities {
+		snapshot.Planet.Cities[i].Destroyed = snapshot.Planet.Cities[i].Name == "B"
+	}
+
+	restored, err := RestoreInvasion(snapshot)
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]map[earth.Direction]string{"A": {}, "C": {}}, restored.Cities())
+}
@@ -0,0 +1,107 @@
+package simulation
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/jattento/alien-invasion-simulator/internal/earth"
+	"gopkg.in/yaml.v3"
+)
+
+var ErrInvalidSpecies = errors.New("invalid species")
+
+// speciesFile is the schema of a species file, YAML or JSON.
+//
+//	Example:
+//	species:
+//	  - name: scout
+//	    movement: walk
+//	    speed: 2
+//	    strength: 1
+type speciesFile struct {
+	Species []struct {
+		Name     string `yaml:"name"`
+		Movement string `yaml:"movement"`
+		Speed    *int   `yaml:"speed"`
+		Strength *int   `yaml:"strength"`
+	} `yaml:"species"`
+}
+
+// ReadSpecies returns the builtin species with the ones of the species file added, a species of the file
+// with the same name as a builtin one replaces it. Speed and strength are 1 if not set, and an empty
+// movement means the species uses the movement of the invasion.
+func ReadSpecies(input io.Reader) (map[string]earth.Species, error) {
+	var file speciesFile
+
+	decoder := yaml.NewDecoder(input)
+	decoder.KnownFields(true)
+
+	if err := decoder.Decode(&file); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidSpecies, err.Error())
+	}
+
+	species := earth.BuiltinSpecies()
+
+	for _, fileSpecies := range file.Species {
+		if fileSpecies.Name == "" {
+			return nil, fmt.Errorf("%w: every species must have a name", ErrInvalidSpecies)
+		}
+
+		newSpecies := earth.Species{Name: fileSpecies.Name, Speed: 1, Strength: 1}
+
+		if fileSpecies.Movement != "" {
+			movement, err := earth.ParseMovement(fileSpecies.Movement)
+			if err != nil {
+				return nil, fmt.Errorf("%w: %q: %s", ErrInvalidSpecies, fileSpecies.Name, err.Error())
+			}
+
+			newSpecies.Movement = movement
+		}
+
+		if fileSpecies.Speed != nil {
+			newSpecies.Speed = *fileSpecies.Speed
+		}
+
+		if fileSpecies.Strength != nil {
+			newSpecies.Strength = *fileSpecies.Strength
+		}
+
+		if newSpecies.Speed < 1 || newSpecies.Strength < 1 {
+			return nil, fmt.Errorf("%w: %q speed and strength must be at least 1", ErrInvalidSpecies, fileSpecies.Name)
+		}
+
+		species[newSpecies.Name] = newSpecies
+	}
+
+	return species, nil
+}
+
+// ParseSpeciesMix parses a comma separated list of species=weight, like "scout=50,brute=30,hive=20".
+// The weight is optional and 1 by default, every species must be in available.
+func ParseSpeciesMix(spec string, available map[string]earth.Species) ([]earth.SpeciesShare, error) {
+	mix := make([]earth.SpeciesShare, 0)
+
+	for _, entry := range strings.Split(spec, ",") {
+		name, weightText, hasWeight := strings.Cut(strings.TrimSpace(entry), "=")
+
+		species, exists := available[name]
+		if !exists {
+			return nil, fmt.Errorf("%w: %q", earth.ErrUnknownSpecies, name)
+		}
+
+		weight := 1
+		if hasWeight {
+			var err error
+			if weight, err = strconv.Atoi(weightText); err != nil || weight < 1 {
+				return nil, fmt.Errorf("%w: %q weight must be a positive number", ErrInvalidSpecies, entry)
+			}
+		}
+
+		mix = append(mix, earth.SpeciesShare{Species: species, Weight: weight})
+	}
+
+	return mix, nil
+}
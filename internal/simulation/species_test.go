@@ -0,0 +1,81 @@
+package simulation
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/jattento/alien-invasion-simulator/internal/earth"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadSpecies(t *testing.T) {
+	species, err := ReadSpecies(strings.NewReader(`species:
+  - name: titan
+    movement: hunter
+    speed: 3
+    strength: 5
+  - name: scout
+    strength: 2
+`))
+	require.NoError(t, err)
+
+	assert.Equal(t, earth.Species{Name: "titan", Movement: earth.HunterMovement{}, Speed: 3, Strength: 5}, species["titan"])
+	assert.Equal(t, earth.Species{Name: "scout", Speed: 1, Strength: 2}, species["scout"], "file species replace builtin ones")
+	assert.Equal(t, earth.BuiltinSpecies()["brute"], species["brute"])
+
+	species, err = ReadSpecies(strings.NewReader(`{"species": [{"name": "drone", "speed": 2}]}`))
+	require.NoError(t, err)
+	assert.Equal(t, earth.Species{Name: "drone", Speed: 2, Strength: 1}, species["drone"])
+
+	for _, invalid := range []string{
+		"species:\n  - speed: 2\n",
+		"species:\n  - name: drone\n    speed: 0\n",
+		"species:\n  - name: drone\n    strength: 0\n",
+		"species:\n  - name: drone\n    movement: teleport\n",
+		"species:\n  - name: drone\n    wings: 2\n",
+	} {
+		_, err := ReadSpecies(strings.NewReader(invalid))
+		assert.ErrorIs(t, err, ErrInvalidSpecies, invalid)
+	}
+}
+
+func TestParseSpeciesMix(t *testing.T) {
+	available := earth.BuiltinSpecies()
+
+	mix, err := ParseSpeciesMix("scout=50, brute=30,hive", available)
+	require.NoError(t, err)
+
+	assert.Equal(t, []earth.SpeciesShare{
+		{Species: available["scout"], Weight: 50},
+		{Species: available["brute"], Weight: 30},
+		{Species: available["hive"], Weight: 1},
+	}, mix)
+
+	_, err = ParseSpeciesMix("scout=50,titan=1", available)
+	assert.ErrorIs(t, err, earth.ErrUnknownSpecies)
+
+	_, err = ParseSpeciesMix("scout=many", available)
+	assert.ErrorIs(t, err, ErrInvalidSpecies)
+
+	_, err = ParseSpeciesMix("scout=0,brute=1", available)
+	assert.ErrorIs(t, err, ErrInvalidSpecies)
+}
+
+func TestInvasion_RecordingHeader_Species(t *testing.T) {
+	cityLayout := map[string]map[earth.Direction]string{
+		"A": {earth.East: "B"},
+		"B": {earth.West: "A"},
+	}
+
+	scout := earth.BuiltinSpecies()["scout"]
+	invasion, err := NewInvasionFromLayout(cityLayout, 2, 10, 0, WithSpeciesMix([]earth.SpeciesShare{{Species: scout, Weight: 1}}))
+	require.NoError(t, err)
+
+	header := invasion.RecordingHeader()
+	require.Len(t, header.Species, 2)
+
+	for alien := range header.Aliens {
+		assert.Equal(t, "scout", header.Species[alien])
+	}
+}
@@ -0,0 +1,150 @@
+package simulation
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/jattento/alien-invasion-simulator/internal/platform/datastructure"
+)
+
+// StopCondition ends an invasion before its tick limit, it is checked after every tick.
+type StopCondition interface {
+	// Check returns why the invasion must stop, or an empty reason if it goes on.
+	Check(invasion *Invasion) string
+
+	// String returns the spec that ParseStopCondition turns into this condition.
+	String() string
+}
+
+var ErrUnknownStopCondition = errors.New("unknown stop condition")
+
+// ParseStopCondition returns the condition described by spec: isolated, no-battles, destroyed:<percent>
+// or city:<name>.
+func ParseStopCondition(spec string) (StopCondition, error) {
+	name, parameter, hasParameter := strings.Cut(spec, ":")
+
+	switch name {
+	case "isolated":
+		return AliensIsolatedCondition{}, nil
+	case "no-battles":
+		return NoBattlesCondition{}, nil
+	case "destroyed":
+		percent, err := strconv.ParseFloat(parameter, 64)
+		if !hasParameter || err != nil || percent <= 0 || percent > 100 {
+			return nil, fmt.Errorf("%w: %q parameter must be a percent greater than 0 and up to 100", ErrUnknownStopCondition, spec)
+		}
+
+		return CitiesDestroyedCondition{Percent: percent}, nil
+	case "city":
+		if !hasParameter || parameter == "" {
+			return nil, fmt.Errorf("%w: %q parameter must be the name of a city", ErrUnknownStopCondition, spec)
+		}
+
+		return CityDestroyedCondition{City: parameter}, nil
+	default:
+		return nil, fmt.Errorf("%w: %q, must be isolated, no-battles, destroyed or city", ErrUnknownStopCondition, spec)
+	}
+}
+
+// AliensIsolatedCondition stops the invasion once every alien is alone at a city without roads,
+// as long as no wave is still coming.
+type AliensIsolatedCondition struct{}
+
+func (AliensIsolatedCondition) Check(invasion *Invasion) string {
+	if invasion.AliensIncoming() > 0 || !invasion.planet.AliensIsolated() {
+		return ""
+	}
+
+	return "every alien is isolated"
+}
+
+func (AliensIsolatedCondition) String() string {
+	return "isolated"
+}
+
+// NoBattlesCondition stops the invasion once no two aliens can meet anymore,
+// as long as no wave is still coming.
+type NoBattlesCondition struct{}
+
+func (NoBattlesCondition) Check(invasion *Invasion) string {
+	if invasion.AliensAlive() == 0 || invasion.AliensIncoming() > 0 || invasion.planet.BattlesPossible() {
+		return ""
+	}
+
+	return "no battle is possible anymore"
+}
+
+func (NoBattlesCondition) String() string {
+	return "no-battles"
+}
+
+// CitiesDestroyedCondition stops the invasion once at least Percent of the cities were destroyed.
+type CitiesDestroyedCondition struct {
+	Percent float64
+}
+
+func (condition CitiesDestroyedCondition) Check(invasion *Invasion) string {
+	destroyed := 0
+	for city := range invasion.CityLayout {
+		if invasion.planet.CityDestroyed(city) {
+			destroyed++
+		}
+	}
+
+	if len(invasion.CityLayout) == 0 || float64(destroyed*100) < condition.Percent*float64(len(invasion.CityLayout)) {
+		return ""
+	}
+
+	return fmt.Sprintf("%d of %d cities were destroyed, at least %s%%", destroyed, len(invasion.CityLayout),
+		strconv.FormatFloat(condition.Percent, 'f', -1, 64))
+}
+
+func (condition CitiesDestroyedCondition) String() string {
+	return "destroyed:" + strconv.FormatFloat(condition.Percent, 'f', -1, 64)
+}
+
+// CityDestroyedCondition stops the invasion once City is destroyed.
+type CityDestroyedCondition struct {
+	City string
+}
+
+func (condition CityDestroyedCondition) Check(invasion *Invasion) string {
+	if !invasion.planet.CityDestroyed(condition.City) {
+		return ""
+	}
+
+	return fmt.Sprintf("%q was destroyed", condition.City)
+}
+
+func (condition CityDestroyedCondition) String() string {
+	return "city:" + condition.City
+}
+
+// checkStopConditions returns an error if a condition waits for a city that doesn't exist.
+func (invasion *Invasion) checkStopConditions() error {
+	for _, condition := range invasion.options.stopConditions {
+		cityCondition, isCityCondition := condition.(CityDestroyedCondition)
+		if !isCityCondition {
+			continue
+		}
+
+		if _, exists := invasion.CityLayout[cityCondition.City]; !exists {
+			return fmt.Errorf("%w: stop condition %s waits for %q", datastructure.ErrVertexNotFound, condition, cityCondition.City)
+		}
+	}
+
+	return nil
+}
+
+// stopReason returns the reason of the first stop condition that is met, empty if none is.
+func (invasion *Invasion) stopReason() string {
+	for _, condition := range invasion.options.stopConditions {
+		if reason := condition.Check(invasion); reason != "" {
+			return reason
+		}
+	}
+
+	return ""
+}
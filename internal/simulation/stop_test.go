@@ -0,0 +1,95 @@
+package simulation
+
+import (
+	"testing"
+
+	"github.com/jattento/alien-invasion-simulator/internal/earth"
+	"github.com/jattento/alien-invasion-simulator/internal/platform/datastructure"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseStopCondition(t *testing.T) {
+	for _, spec := range []string{"isolated", "no-battles", "destroyed:50", "destroyed:12.5", "city:New York"} {
+		condition, err := ParseStopCondition(spec)
+		require.NoError(t, err, spec)
+		assert.Equal(t, spec, condition.String())
+	}
+
+	for _, spec := range []string{"", "destroyed", "destroyed:0", "destroyed:101", "destroyed:x", "city", "city:", "forever"} {
+		_, err := ParseStopCondition(spec)
+		assert.ErrorIs(t, err, ErrUnknownStopCondition, spec)
+	}
+}
+
+func TestInvasion_Tick_StopConditions(t *testing.T) {
+	// A and B fight at day zero, C and D are left apart with an alien each
+	cityLayout := map[string]map[earth.Direction]string{
+		"A": {earth.East: "B"},
+		"B": {earth.West: "A"},
+		"C": {},
+		"D": {},
+	}
+
+	tick := func(conditions ...StopCondition) (bool, TickReport) {
+		invasion, err := NewInvasionFromLayout(cityLayout, 0, 100, 1, WithMovement(earth.HoldMovement{}),
+			WithStopConditions(conditions), WithWaves([]earth.Wave{
+				{Aliens: 2, Tick: 0, Placement: earth.CitiesPlacement{Cities: []string{"A"}}},
+				{Aliens: 2, Tick: 0, Placement: earth.CitiesPlacement{Cities: []string{"C", "D"}}},
+			}))
+		require.NoError(t, err)
+
+		return invasion.Tick()
+	}
+
+	keepTicking, report := tick()
+	assert.True(t, keepTicking)
+	assert.Empty(t, report.StopReason)
+
+	for _, testCase := range []struct {
+		condition StopCondition
+		reason    string
+	}{
+		{AliensIsolatedCondition{}, "every alien is isolated"},
+		{NoBattlesCondition{}, "no battle is possible anymore"},
+		{CitiesDestroyedCondition{Percent: 25}, "1 of 4 cities were destroyed, at least 25%"},
+		{CityDestroyedCondition{City: "A"}, `"A" was destroyed`},
+	} {
+		keepTicking, report = tick(testCase.condition)
+		assert.False(t, keepTicking, testCase.condition.String())
+		assert.Equal(t, testCase.reason, report.StopReason)
+	}
+
+	for _, condition := range []StopCondition{CitiesDestroyedCondition{Percent: 50}, CityDestroyedCondition{City: "C"}} {
+		keepTicking, report = tick(condition)
+		assert.True(t, keepTicking, condition.String())
+		assert.Empty(t, report.StopReason)
+	}
+
+	_, err := NewInvasionFromLayout(cityLayout, 1, 100, 1, WithStopConditions([]StopCondition{CityDestroyedCondition{City: "Z"}}))
+	assert.ErrorIs(t, err, datastructure.ErrVertexNotFound)
+}
+
+func TestNoBattlesCondition_IncomingWaves(t *testing.T) {
+	cityLayout := map[string]map[earth.Direction]string{"A": {}, "B": {}}
+
+	invasion, err := NewInvasionFromLayout(cityLayout, 2, 100, 1, WithStopConditions([]StopCondition{NoBattlesCondition{}}),
+		WithWaves([]earth.Wave{{Aliens: 1, Tick: 3}}))
+	require.NoError(t, err)
+
+	// The aliens spawned apart, but the wave may still land next to one of them
+	for tick := 0; tick < 3; tick++ {
+		keepTicking, _ := invasion.Tick()
+		require.True(t, keepTicking, "tick %d", tick)
+	}
+
+	snapshot := invasion.Snapshot()
+	assert.Equal(t, []string{"no-battles"}, snapshot.Stop)
+
+	restored, err := RestoreInvasion(snapshot)
+	require.NoError(t, err)
+
+	keepTicking, report := restored.Tick()
+	assert.False(t, keepTicking)
+	assert.Equal(t, "no battle is possible anymore", report.StopReason)
+}
@@ -0,0 +1,133 @@
+package simulation
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/jattento/alien-invasion-simulator/internal/earth"
+	"github.com/jattento/alien-invasion-simulator/internal/platform/system"
+)
+
+// LayoutError lists every problem found in a city layout.
+type LayoutError struct {
+	Problems []LayoutProblem
+}
+
+// LayoutProblem Line and Column are 0 when the position of the road is unknown.
+// The problems of the city attributes have the attribute as Direction.
+type LayoutProblem struct {
+	Line      int
+	Column    int
+	City      string
+	Direction string
+	Reason    string
+}
+
+func (e *LayoutError) Error() string {
+	messages := make([]string, 0, len(e.Problems))
+	for _, problem := range e.Problems {
+		messages = append(messages, problem.String())
+	}
+
+	return fmt.Sprintf("invalid city layout: %d problem(s)\n%s", len(e.Problems), strings.Join(messages, "\n"))
+}
+
+func (problem LayoutProblem) String() string {
+	location := ""
+	if problem.Line > 0 {
+		location = fmt.Sprintf("line %d, column %d: ", problem.Line, problem.Column)
+	}
+
+	return fmt.Sprintf("%s%s %s: %s", location, problem.City, problem.Direction, problem.Reason)
+}
+
+// ValidateCityLayout returns a *LayoutError with every road that breaks the layout rules,
+// positions is optional and only used to locate the problems. Every road must be in one of directions, or in a label
+// marked with earth.LabelPrefix that is its own inverse, and have a road back in the inverse of its direction.
+func ValidateCityLayout(fileRecords system.LoadFileRecords, positions system.LoadFilePositions, directions *earth.Directions) error {
+	return newLayoutError(layoutProblems(fileRecords, positions, directions))
+}
+
+func layoutProblems(fileRecords system.LoadFileRecords, positions system.LoadFilePositions,
+	directions *earth.Directions) []LayoutProblem {
+	problems := make([]LayoutProblem, 0)
+
+	cities := make([]string, 0, len(fileRecords))
+	for city := range fileRecords {
+		cities = append(cities, city)
+	}
+	sort.Strings(cities)
+
+	for _, city := range cities {
+		cityRoads := fileRecords[city]
+
+		roadDirections := make([]string, 0, len(cityRoads))
+		for direction := range cityRoads {
+			roadDirections = append(roadDirections, direction)
+		}
+		sort.Strings(roadDirections)
+
+		for _, direction := range roadDirections {
+			adjacentCity := cityRoads[direction]
+
+			report := func(reason string, args ...interface{}) {
+				position := positions[city].Values[direction]
+				problems = append(problems, LayoutProblem{
+					Line:      position.Line,
+					Column:    position.Column,
+					City:      city,
+					Direction: direction,
+					Reason:    fmt.Sprintf(reason, args...),
+				})
+			}
+
+			opposite, known := directions.InverseName(direction)
+			if !known {
+				report("unknown direction, must be one of %s or a label such as %s%s",
+					strings.Join(directions.Names(), ", "), earth.LabelPrefix, direction)
+				continue
+			}
+
+			if adjacentCity == city {
+				report("road leads to the same city")
+				continue
+			}
+
+			adjacentRoads, exists := fileRecords[adjacentCity]
+			if !exists {
+				report("%q has no record", adjacentCity)
+				continue
+			}
+
+			reciprocal, hasReciprocal := adjacentRoads[opposite]
+			if !hasReciprocal {
+				report("missing reciprocal road, %q has no %s road", adjacentCity, opposite)
+				continue
+			}
+
+			if reciprocal != city {
+				report("reciprocal road points to a different city, %q has %s=%s", adjacentCity, opposite, reciprocal)
+			}
+		}
+	}
+
+	return problems
+}
+
+// newLayoutError returns a *LayoutError with the problems sorted by position, nil if there are none.
+func newLayoutError(problems []LayoutProblem) error {
+	if len(problems) == 0 {
+		return nil
+	}
+
+	sort.SliceStable(problems, func(i, j int) bool {
+		if problems[i].Line != problems[j].Line {
+			return problems[i].Line < problems[j].Line
+		}
+
+		return problems[i].Column < problems[j].Column
+	})
+
+	return &LayoutError{Problems: problems}
+}
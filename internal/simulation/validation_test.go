@@ -0,0 +1,125 @@
+package simulation
+
+import (
+	"testing"
+
+	"github.com/jattento/alien-invasion-simulator/internal/earth"
+	"github.com/jattento/alien-invasion-simulator/internal/platform/system"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateCityLayout(t *testing.T) {
+	t.Run("valid layout", func(t *testing.T) {
+		err := ValidateCityLayout(system.LoadFileRecords{
+			"Foo": {"north": "Bar", "west": "Baz"},
+			"Bar": {"south": "Foo"},
+			"Baz": {"east": "Foo"},
+		}, nil, earth.NewDirections())
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("many roads to the same city", func(t *testing.T) {
+		err := ValidateCityLayout(system.LoadFileRecords{
+			"Foo": {"north": "Bar", "east": "Bar"},
+			"Bar": {"south": "Foo", "west": "Foo"},
+		}, nil, earth.NewDirections())
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("every problem is listed", func(t *testing.T) {
+		records := system.LoadFileRecords{
+			"Foo": {"north": "Bar", "west": "Baz", "south": "Qux", "up": "Bar"},
+			"Bar": {"south": "Baz"},
+			"Baz": {"north": "Baz"},
+			"Bee": {"east": "Nowhere"},
+			"Qux": {"east": "Foo", "west": "Foo"},
+		}
+		positions := system.LoadFilePositions{
+			"Foo": {Position: system.Position{Line: 1, Column: 1}, Values: map[string]system.Position{
+				"north": {Line: 1, Column: 5}, "west": {Line: 1, Column: 15}, "south": {Line: 1, Column: 24}, "up": {Line: 1, Column: 34},
+			}},
+			"Bar": {Position: system.Position{Line: 2, Column: 1}, Values: map[string]system.Position{"south": {Line: 2, Column: 5}}},
+			"Baz": {Position: system.Position{Line: 3, Column: 1}, Values: map[string]system.Position{"north": {Line: 3, Column: 5}}},
+			"Bee": {Position: system.Position{Line: 4, Column: 1}, Values: map[string]system.Position{"east": {Line: 4, Column: 5}}},
+			"Qux": {Position: system.Position{Line: 5, Column: 1}, Values: map[string]system.Position{
+				"east": {Line: 5, Column: 5}, "west": {Line: 5, Column: 14},
+			}},
+		}
+
+		err := ValidateCityLayout(records, positions, earth.NewDirections())
+
+		var layoutErr *LayoutError
+		require.ErrorAs(t, err, &layoutErr)
+
+		assert.Equal(t, []LayoutProblem{
+			{Line: 1, Column: 5, City: "Foo", Direction: "north", Reason: `reciprocal road points to a different city, "Bar" has south=Baz`},
+			{Line: 1, Column: 15, City: "Foo", Direction: "west", Reason: `missing reciprocal road, "Baz" has no east road`},
+			{Line: 1, Column: 24, City: "Foo", Direction: "south", Reason: `missing reciprocal road, "Qux" has no north road`},
+			{Line: 1, Column: 34, City: "Foo", Direction: "up", Reason: `missing reciprocal road, "Bar" has no down road`},
+			{Line: 2, Column: 5, City: "Bar", Direction: "south", Reason: `reciprocal road points to a different city, "Baz" has north=Baz`},
+			{Line: 3, Column: 5, City: "Baz", Direction: "north", Reason: "road leads to the same city"},
+			{Line: 4, Column: 5, City: "Bee", Direction: "east", Reason: `"Nowhere" has no record`},
+			{Line: 5, Column: 5, City: "Qux", Direction: "east", Reason: `reciprocal road points to a different city, "Foo" has west=Baz`},
+			{Line: 5, Column: 14, City: "Qux", Direction: "west", Reason: `missing reciprocal road, "Foo" has no east road`},
+		}, layoutErr.Problems)
+
+		assert.Contains(t, err.Error(), "invalid city layout: 9 problem(s)")
+		assert.Contains(t, err.Error(), `line 4, column 5: Bee east: "Nowhere" has no record`)
+	})
+
+	t.Run("directions beyond the compass", func(t *testing.T) {
+		directions, err := earth.ParseDirections("inside:outside")
+		require.NoError(t, err)
+
+		records := system.LoadFileRecords{
+			"Foo":   {"northeast": "Bar", "up": "Attic", "inside": "Hall", "label:ferry": "Isle"},
+			"Bar":   {"southwest": "Foo"},
+			"Attic": {"down": "Foo"},
+			"Hall":  {"outside": "Foo"},
+			"Isle":  {"label:ferry": "Foo"},
+		}
+		assert.NoError(t, ValidateCityLayout(records, nil, directions))
+
+		// Labels are their own inverse, and the pairs need their inverse back
+		records["Isle"] = map[string]string{"label:boat": "Foo"}
+		records["Hall"] = map[string]string{"inside": "Foo"}
+
+		var layoutErr *LayoutError
+		require.ErrorAs(t, ValidateCityLayout(records, nil, directions), &layoutErr)
+		assert.Equal(t, []LayoutProblem{
+			{City: "Foo", Direction: "inside", Reason: `missing reciprocal road, "Hall" has no outside road`},
+			{City: "Foo", Direction: "label:ferry", Reason: `missing reciprocal road, "Isle" has no label:ferry road`},
+			{City: "Hall", Direction: "inside", Reason: `missing reciprocal road, "Foo" has no outside road`},
+			{City: "Isle", Direction: "label:boat", Reason: `missing reciprocal road, "Foo" has no label:boat road`},
+		}, layoutErr.Problems)
+	})
+
+	t.Run("unknown directions", func(t *testing.T) {
+		directions, err := earth.ParseDirections("inside:outside")
+		require.NoError(t, err)
+
+		// A misspelled direction isn't a label, even with a road back
+		err = ValidateCityLayout(system.LoadFileRecords{
+			"Foo": {"nrth": "Bar"},
+			"Bar": {"nrth": "Foo"},
+		}, nil, directions)
+
+		var layoutErr *LayoutError
+		require.ErrorAs(t, err, &layoutErr)
+		assert.Equal(t, []LayoutProblem{
+			{City: "Bar", Direction: "nrth", Reason: "unknown direction, must be one of north, east, south, west, northeast, " +
+				"southeast, southwest, northwest, up, down, inside, outside or a label such as label:nrth"},
+			{City: "Foo", Direction: "nrth", Reason: "unknown direction, must be one of north, east, south, west, northeast, " +
+				"southeast, southwest, northwest, up, down, inside, outside or a label such as label:nrth"},
+		}, layoutErr.Problems)
+	})
+
+	t.Run("unknown positions", func(t *testing.T) {
+		err := ValidateCityLayout(system.LoadFileRecords{"Foo": {"north": "Foo"}}, nil, earth.NewDirections())
+
+		assert.EqualError(t, err, "invalid city layout: 1 problem(s)\nFoo north: road leads to the same city")
+	})
+}